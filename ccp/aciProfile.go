@@ -17,10 +17,13 @@ package ccp
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // ACIProfile struct
@@ -48,49 +51,47 @@ type ACIProfile struct {
 
 // GetACIProfiles gets
 func (s *Client) GetACIProfiles() ([]ACIProfile, error) {
+	return s.GetACIProfilesWithContext(context.Background())
+}
 
-	url := s.BaseURL + "/v3/aci-profiles"
-
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, err
-	}
-	bytes, err := s.doRequest(req)
-	if err != nil {
-		return nil, err
-	}
-
-	fmt.Print(string(bytes))
-	// Create an Array of ACI Profiles
-	var data []ACIProfile
-
-	err = json.Unmarshal(bytes, &data)
-	if err != nil {
-		return nil, err
-	}
+// GetACIProfilesWithContext is GetACIProfiles with a context that can cancel the call
+func (s *Client) GetACIProfilesWithContext(ctx context.Context) (_ []ACIProfile, err error) {
+	ctx, span := s.startOperation(ctx, "GetACIProfiles")
+	defer func() { endOperation(span, err) }()
 
-	return data, nil
+	return Collect(paginate[ACIProfile](ctx, s, "/v3/aci-profiles"))
 }
 
 // GetACIProfileByName gets
 func (s *Client) GetACIProfileByName(profileName string) (*ACIProfile, error) {
+	return s.GetACIProfileByNameWithContext(context.Background(), profileName)
+}
 
-	aciProfiles, err := s.GetACIProfiles()
+// GetACIProfileByNameWithContext is GetACIProfileByName with a context that can cancel the call
+func (s *Client) GetACIProfileByNameWithContext(ctx context.Context, profileName string) (_ *ACIProfile, err error) {
+	ctx, span := s.startOperation(ctx, "GetACIProfileByName", attribute.String("ccp.aci_profile.name", profileName))
+	defer func() { endOperation(span, err) }()
+
+	x, err := findByName(ctx, s, cacheACIProfiles, profileName, s.GetACIProfilesWithContext)
 	if err != nil {
 		return nil, err
 	}
-
-	for _, x := range aciProfiles {
-
-		if string(profileName) == string(*x.Name) {
-			return &x, nil
-		}
+	if x == nil {
+		return nil, fmt.Errorf("Cannot find ACI Profile %s: %w", profileName, ErrNotFound)
 	}
-	return nil, errors.New("Cannot find ACI Profile " + profileName)
+	return x, nil
 }
 
 // AddACIProfile adds
 func (s *Client) AddACIProfile(aciProfile *ACIProfile) (*ACIProfile, error) {
+	return s.AddACIProfileWithContext(context.Background(), aciProfile)
+}
+
+// AddACIProfileWithContext is AddACIProfile with a context that can cancel the call
+func (s *Client) AddACIProfileWithContext(ctx context.Context, aciProfile *ACIProfile) (_ *ACIProfile, err error) {
+	ctx, span := s.startOperation(ctx, "AddACIProfile")
+	defer func() { endOperation(span, err) }()
+	defer s.cache.forget(cacheACIProfiles, nameOf(aciProfile), "")
 
 	url := s.BaseURL + "/v3/aci-profiles/"
 
@@ -100,7 +101,7 @@ func (s *Client) AddACIProfile(aciProfile *ACIProfile) (*ACIProfile, error) {
 		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(j))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(j))
 
 	if err != nil {
 		return nil, err
@@ -125,6 +126,14 @@ func (s *Client) AddACIProfile(aciProfile *ACIProfile) (*ACIProfile, error) {
 
 // DeleteACIProfile delete a profile
 func (s *Client) DeleteACIProfile(profileUUID string) error {
+	return s.DeleteACIProfileWithContext(context.Background(), profileUUID)
+}
+
+// DeleteACIProfileWithContext is DeleteACIProfile with a context that can cancel the call
+func (s *Client) DeleteACIProfileWithContext(ctx context.Context, profileUUID string) (err error) {
+	ctx, span := s.startOperation(ctx, "DeleteACIProfile", attribute.String("ccp.aci_profile.uuid", profileUUID))
+	defer func() { endOperation(span, err) }()
+	defer s.cache.forget(cacheACIProfiles, "", profileUUID)
 
 	if profileUUID == "" {
 		return errors.New("Cluster UUID to delete is required")
@@ -132,7 +141,7 @@ func (s *Client) DeleteACIProfile(profileUUID string) error {
 
 	url := s.BaseURL + "/v3/aci-profiles/" + profileUUID + "/"
 
-	req, err := http.NewRequest("DELETE", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
 	if err != nil {
 		return err
 	}
@@ -146,6 +155,14 @@ func (s *Client) DeleteACIProfile(profileUUID string) error {
 
 // PatchACIProfile patch an ACI profile
 func (s *Client) PatchACIProfile(profile *ACIProfile, profileUUID string) (*ACIProfile, error) {
+	return s.PatchACIProfileWithContext(context.Background(), profile, profileUUID)
+}
+
+// PatchACIProfileWithContext is PatchACIProfile with a context that can cancel the call
+func (s *Client) PatchACIProfileWithContext(ctx context.Context, profile *ACIProfile, profileUUID string) (_ *ACIProfile, err error) {
+	ctx, span := s.startOperation(ctx, "PatchACIProfile", attribute.String("ccp.aci_profile.uuid", profileUUID))
+	defer func() { endOperation(span, err) }()
+	defer s.cache.forget(cacheACIProfiles, "", profileUUID)
 
 	var data ACIProfile
 
@@ -158,7 +175,7 @@ func (s *Client) PatchACIProfile(profile *ACIProfile, profileUUID string) (*ACIP
 		return nil, err
 	}
 
-	req, err := http.NewRequest("PATCH", url, bytes.NewBuffer(j))
+	req, err := http.NewRequestWithContext(ctx, "PATCH", url, bytes.NewBuffer(j))
 	if err != nil {
 		return nil, err
 	}
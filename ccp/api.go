@@ -0,0 +1,229 @@
+/*Copyright (c) 2019 Cisco and/or its affiliates.
+
+This software is licensed to you under the terms of the Cisco Sample
+Code License, Version 1.0 (the "License"). You may obtain a copy of the
+License at
+
+               https://developer.cisco.com/docs/licenses
+
+All use of the material herein must be in accordance with the terms of
+the License. All rights not expressly granted by the License are
+reserved. Unless required by applicable law or agreed to separately in
+writing, software distributed under the License is distributed on an "AS
+IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+or implied.*/
+
+package ccp
+
+import (
+	"context"
+	"iter"
+)
+
+// The interfaces below group the Client's methods by API area so code built on
+// the client can take just the part it needs and be tested against a fake.
+// *Client satisfies all of them, and package ccpmock has configurable mocks.
+//
+// When adding a method to Client, add it to its interface here and run
+// go generate ./ccp/ccpmock to update the mocks.
+
+// ClustersAPI is the cluster lifecycle part of the CCP API
+type ClustersAPI interface {
+	GetClusters() ([]Cluster, error)
+	GetClustersWithContext(ctx context.Context) ([]Cluster, error)
+	ListClusters(ctx context.Context) iter.Seq2[Cluster, error]
+	GetClusterByName(clusterName string) (*Cluster, error)
+	GetClusterByNameWithContext(ctx context.Context, clusterName string) (*Cluster, error)
+	GetClusterByUUID(clusterUUID string) (*Cluster, error)
+	GetClusterByUUIDWithContext(ctx context.Context, clusterUUID string) (*Cluster, error)
+	GetClusterStatusByName(clusterName string) (*string, error)
+	GetClusterStatusByNameWithContext(ctx context.Context, clusterName string) (*string, error)
+	AddCluster(cluster *Cluster) (*Cluster, error)
+	AddClusterWithContext(ctx context.Context, cluster *Cluster) (*Cluster, error)
+	AddClusterBasic(cluster *Cluster) (*Cluster, error)
+	AddClusterBasicWithContext(ctx context.Context, cluster *Cluster) (*Cluster, error)
+	AddClusterSynchronous(cluster *Cluster) (*Cluster, error)
+	AddClusterSynchronousWithContext(ctx context.Context, cluster *Cluster) (*Cluster, error)
+	PatchCluster(cluster *Cluster, clusterUUID string) (*Cluster, error)
+	PatchClusterWithContext(ctx context.Context, cluster *Cluster, clusterUUID string) (*Cluster, error)
+	DeleteCluster(clusterUUID string) error
+	DeleteClusterWithContext(ctx context.Context, clusterUUID string) error
+	ScaleCluster(clusterUUID, workerPoolName string, size int) (*Cluster, error)
+	ScaleClusterWithContext(ctx context.Context, clusterUUID, workerPoolName string, size int) (*Cluster, error)
+}
+
+// AddonsAPI installs and removes cluster addons
+type AddonsAPI interface {
+	GetAddonsCatalogue(clusterUUID string) (*AddonsCatalogue, error)
+	GetAddonsCatalogueWithContext(ctx context.Context, clusterUUID string) (*AddonsCatalogue, error)
+	GetClusterInstalledAddons(clusterUUID string) (*ClusterInstalledAddons, error)
+	GetClusterInstalledAddonsWithContext(ctx context.Context, clusterUUID string) (*ClusterInstalledAddons, error)
+	ListClusterInstalledAddons(ctx context.Context, clusterUUID string) iter.Seq2[InstalledAddon, error]
+	InstallAddonIstioOp(clusterUUID string) error
+	InstallAddonIstioOpWithContext(ctx context.Context, clusterUUID string) error
+	InstallAddonIstioInstance(clusterUUID string) error
+	InstallAddonIstioInstanceWithContext(ctx context.Context, clusterUUID string) error
+	InstallAddonIstio(clusterUUID string) error
+	InstallAddonIstioWithContext(ctx context.Context, clusterUUID string) error
+	InstallAddonDashboard(clusterUUID string) error
+	InstallAddonDashboardWithContext(ctx context.Context, clusterUUID string) error
+	InstallAddonMonitoring(clusterUUID string) error
+	InstallAddonMonitoringWithContext(ctx context.Context, clusterUUID string) error
+	InstallAddonLogging(clusterUUID string) error
+	InstallAddonLoggingWithContext(ctx context.Context, clusterUUID string) error
+	InstallAddonHarborOp(clusterUUID string) error
+	InstallAddonHarborOpWithContext(ctx context.Context, clusterUUID string) error
+	InstallAddonHarborInstance(clusterUUID string) error
+	InstallAddonHarborInstanceWithContext(ctx context.Context, clusterUUID string) error
+	InstallAddonHarbor(clusterUUID string) error
+	InstallAddonHarborWithContext(ctx context.Context, clusterUUID string) error
+	InstallAddonHXCSI(clusterUUID string) error
+	InstallAddonHXCSIWithContext(ctx context.Context, clusterUUID string) error
+	InstallAddonKubeflow(clusterUUID string) error
+	InstallAddonKubeflowWithContext(ctx context.Context, clusterUUID string) error
+	DeleteAddonLogging(clusterUUID string) error
+	DeleteAddonLoggingWithContext(ctx context.Context, clusterUUID string) error
+	DeleteAddonMonitor(clusterUUID string) error
+	DeleteAddonMonitorWithContext(ctx context.Context, clusterUUID string) error
+	DeleteAddonIstioInstance(clusterUUID string) error
+	DeleteAddonIstioInstanceWithContext(ctx context.Context, clusterUUID string) error
+	DeleteAddonIstioOp(clusterUUID string) error
+	DeleteAddonIstioOpWithContext(ctx context.Context, clusterUUID string) error
+	DeleteAddonIstio(clusterUUID string) error
+	DeleteAddonIstioWithContext(ctx context.Context, clusterUUID string) error
+	DeleteAddonDashboard(clusterUUID string) error
+	DeleteAddonDashboardWithContext(ctx context.Context, clusterUUID string) error
+	DeleteAddonHarborInstance(clusterUUID string) error
+	DeleteAddonHarborInstanceWithContext(ctx context.Context, clusterUUID string) error
+	DeleteAddonHarborOp(clusterUUID string) error
+	DeleteAddonHarborOpWithContext(ctx context.Context, clusterUUID string) error
+	DeleteAddonHarbor(clusterUUID string) error
+	DeleteAddonHarborWithContext(ctx context.Context, clusterUUID string) error
+	DeleteAddonHXCSI(clusterUUID string) error
+	DeleteAddonHXCSIWithContext(ctx context.Context, clusterUUID string) error
+	DeleteAddonKubeflow(clusterUUID string) error
+	DeleteAddonKubeflowWithContext(ctx context.Context, clusterUUID string) error
+}
+
+// ProvidersAPI reads the infrastructure providers
+type ProvidersAPI interface {
+	GetInfraProviders() ([]ProviderClientConfig, error)
+	GetInfraProvidersWithContext(ctx context.Context) ([]ProviderClientConfig, error)
+	ListInfraProviders(ctx context.Context) iter.Seq2[ProviderClientConfig, error]
+	GetInfraProviderByUUID(providerUUID string) (*ProviderClientConfig, error)
+	GetInfraProviderByUUIDWithContext(ctx context.Context, providerUUID string) (*ProviderClientConfig, error)
+	GetInfraProviderByName(providerName string) (*ProviderClientConfig, error)
+	GetInfraProviderByNameWithContext(ctx context.Context, providerName string) (*ProviderClientConfig, error)
+}
+
+// SubnetsAPI reads the network provider subnets
+type SubnetsAPI interface {
+	GetNetworkProviderSubnets() ([]NetworkProviderSubnet, error)
+	GetNetworkProviderSubnetsWithContext(ctx context.Context) ([]NetworkProviderSubnet, error)
+	ListNetworkProviderSubnets(ctx context.Context) iter.Seq2[NetworkProviderSubnet, error]
+	GetNetworkProviderSubnetByName(networkProviderName string) (*NetworkProviderSubnet, error)
+	GetNetworkProviderSubnetByNameWithContext(ctx context.Context, networkProviderName string) (*NetworkProviderSubnet, error)
+}
+
+// ACIProfilesAPI manages ACI profiles
+type ACIProfilesAPI interface {
+	GetACIProfiles() ([]ACIProfile, error)
+	GetACIProfilesWithContext(ctx context.Context) ([]ACIProfile, error)
+	ListACIProfiles(ctx context.Context) iter.Seq2[ACIProfile, error]
+	GetACIProfileByName(profileName string) (*ACIProfile, error)
+	GetACIProfileByNameWithContext(ctx context.Context, profileName string) (*ACIProfile, error)
+	AddACIProfile(aciProfile *ACIProfile) (*ACIProfile, error)
+	AddACIProfileWithContext(ctx context.Context, aciProfile *ACIProfile) (*ACIProfile, error)
+	PatchACIProfile(profile *ACIProfile, profileUUID string) (*ACIProfile, error)
+	PatchACIProfileWithContext(ctx context.Context, profile *ACIProfile, profileUUID string) (*ACIProfile, error)
+	DeleteACIProfile(profileUUID string) error
+	DeleteACIProfileWithContext(ctx context.Context, profileUUID string) error
+}
+
+// UsersAPI manages the control plane's users
+type UsersAPI interface {
+	GetUsers() ([]User, error)
+	GetUsersWithContext(ctx context.Context) ([]User, error)
+	ListUsers(ctx context.Context) iter.Seq2[User, error]
+	GetUser(username string) (*User, error)
+	GetUserWithContext(ctx context.Context, username string) (*User, error)
+	AddUser(user *User) (*User, error)
+	AddUserWithContext(ctx context.Context, user *User) (*User, error)
+	PatchUser(user *User) (*User, error)
+	PatchUserWithContext(ctx context.Context, user *User) (*User, error)
+	EnableUser(username string) (*User, error)
+	EnableUserWithContext(ctx context.Context, username string) (*User, error)
+	DisableUser(username string) (*User, error)
+	DisableUserWithContext(ctx context.Context, username string) (*User, error)
+	ResetUserPassword(username, password string) (*User, error)
+	ResetUserPasswordWithContext(ctx context.Context, username, password string) (*User, error)
+	DeleteUser(username string) error
+	DeleteUserWithContext(ctx context.Context, username string) error
+}
+
+// LDAPAPI manages the control plane's LDAP or Active Directory integration
+type LDAPAPI interface {
+	GetLDAPSetup() (*LDAPSetup, error)
+	GetLDAPSetupWithContext(ctx context.Context) (*LDAPSetup, error)
+	SetLDAPSetup(setup *LDAPSetup) (*LDAPSetup, error)
+	SetLDAPSetupWithContext(ctx context.Context, setup *LDAPSetup) (*LDAPSetup, error)
+	UpdateLDAPSetup(setup *LDAPSetup) (*LDAPSetup, error)
+	UpdateLDAPSetupWithContext(ctx context.Context, setup *LDAPSetup) (*LDAPSetup, error)
+	DeleteLDAPSetup() error
+	DeleteLDAPSetupWithContext(ctx context.Context) error
+	TestLDAPSetup(setup *LDAPSetup) (*LDAPTestResult, error)
+	TestLDAPSetupWithContext(ctx context.Context, setup *LDAPSetup) (*LDAPTestResult, error)
+}
+
+// RBACAPI reads the RBAC roles and manages who may use which cluster
+type RBACAPI interface {
+	GetRoles() ([]Role, error)
+	GetRolesWithContext(ctx context.Context) ([]Role, error)
+	ListRoles(ctx context.Context) iter.Seq2[Role, error]
+	GetRole(role string) (*Role, error)
+	GetRoleWithContext(ctx context.Context, role string) (*Role, error)
+	GetClusterAuthz(clusterUUID string) (*ClusterAuthz, error)
+	GetClusterAuthzWithContext(ctx context.Context, clusterUUID string) (*ClusterAuthz, error)
+	GrantClusterAccess(clusterUUID, authType, name string) (*ClusterAuthz, error)
+	GrantClusterAccessWithContext(ctx context.Context, clusterUUID, authType, name string) (*ClusterAuthz, error)
+	RevokeClusterAccess(clusterUUID, authType, name string) error
+	RevokeClusterAccessWithContext(ctx context.Context, clusterUUID, authType, name string) error
+}
+
+// SystemAPI is the session with the control plane and its health
+type SystemAPI interface {
+	Login(client *Client) error
+	LoginWithContext(ctx context.Context, client *Client) error
+	Logout() error
+	LogoutWithContext(ctx context.Context) error
+	WhoAmI() (*Session, error)
+	WhoAmIWithContext(ctx context.Context) (*Session, error)
+	ValidateSession() (*Session, error)
+	ValidateSessionWithContext(ctx context.Context) (*Session, error)
+	ResumeSession() (bool, error)
+	ResumeSessionWithContext(ctx context.Context) (bool, error)
+	GetLivenessHealth() (*LivenessHealth, error)
+	GetLivenessHealthWithContext(ctx context.Context) (*LivenessHealth, error)
+	GetHealth() (*Health, error)
+	GetHealthWithContext(ctx context.Context) (*Health, error)
+	GetVersion() (*Version, error)
+	GetVersionWithContext(ctx context.Context) (*Version, error)
+	Capabilities(ctx context.Context) (*Capabilities, error)
+	Token() string
+	SetToken(token string)
+}
+
+// API is the whole CCP API, as implemented by *Client
+type API interface {
+	ClustersAPI
+	AddonsAPI
+	ProvidersAPI
+	SubnetsAPI
+	ACIProfilesAPI
+	UsersAPI
+	LDAPAPI
+	RBACAPI
+	SystemAPI
+}
+
+var _ API = (*Client)(nil)
@@ -0,0 +1,203 @@
+/*Copyright (c) 2019 Cisco and/or its affiliates.
+
+This software is licensed to you under the terms of the Cisco Sample
+Code License, Version 1.0 (the "License"). You may obtain a copy of the
+License at
+
+               https://developer.cisco.com/docs/licenses
+
+All use of the material herein must be in accordance with the terms of
+the License. All rights not expressly granted by the License are
+reserved. Unless required by applicable law or agreed to separately in
+writing, software distributed under the License is distributed on an "AS
+IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+or implied.*/
+
+package ccp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+)
+
+// the collections behind the ByName lookups
+const (
+	cacheClusters    = "clusters"
+	cacheProviders   = "providers"
+	cacheSubnets     = "subnets"
+	cacheACIProfiles = "aci-profiles"
+	cacheUsers       = "users"
+)
+
+// WithCache keeps the lists fetched by the ByName lookups for ttl, indexed by name,
+// so resolving many names costs one list call. A name that isn't cached still
+// fetches the list again. Writes through the client drop the entries they change,
+// and Refresh drops everything. Changes made by anyone else show up once ttl passes
+func WithCache(ttl time.Duration) Option {
+	return func(s *Client) error {
+		if ttl <= 0 {
+			return errors.New("cache ttl must be positive")
+		}
+		s.cache = &cache{ttl: ttl, indexes: map[string]forgetter{}}
+		return nil
+	}
+}
+
+// Refresh empties the lookup cache so the next ByName call lists again. It does nothing without WithCache
+func (s *Client) Refresh() {
+	s.cache.clear()
+}
+
+// cacheNow tells the time for cache expiry, the tests replace it
+var cacheNow = time.Now
+
+// cache holds one index per collection. A nil *cache caches nothing
+type cache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	indexes map[string]forgetter // collection name to *index[T]
+}
+
+// forgetter is an index with its item type erased
+type forgetter interface {
+	forget(name, uuid string)
+}
+
+// index is a cached list with name to UUID and UUID to item maps
+type index[T any] struct {
+	expires time.Time
+	uuids   map[string]string
+	items   map[string]T
+}
+
+// named is a CCP object with a name and a UUID, either of which may be missing
+type named interface {
+	key() (name, uuid *string)
+}
+
+func (c Cluster) key() (*string, *string)               { return c.Name, c.UUID }
+func (p ProviderClientConfig) key() (*string, *string)  { return p.Name, p.UUID }
+func (n NetworkProviderSubnet) key() (*string, *string) { return n.Name, n.UUID }
+func (p ACIProfile) key() (*string, *string)            { return p.Name, p.UUID }
+func (u User) key() (*string, *string)                  { return u.Username, u.UUID }
+
+// nameOf returns the name of an object being written, "" if it has none
+func nameOf[T named](item *T) string {
+	if item == nil {
+		return ""
+	}
+	if name, _ := (*item).key(); name != nil {
+		return *name
+	}
+	return ""
+}
+
+func (ix *index[T]) forget(name, uuid string) {
+	if name != "" {
+		delete(ix.items, ix.uuids[name])
+		delete(ix.uuids, name)
+	}
+	if uuid != "" {
+		delete(ix.items, uuid)
+	}
+}
+
+// forget drops the cached object with the given name or UUID, either may be ""
+func (c *cache) forget(collection, name, uuid string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if ix, ok := c.indexes[collection]; ok {
+		ix.forget(name, uuid)
+	}
+}
+
+func (c *cache) clear() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.indexes = map[string]forgetter{}
+}
+
+// cached returns a copy of the fresh cached object called name
+func cached[T named](c *cache, collection, name string) (T, bool) {
+	var zero T
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ix, ok := c.indexes[collection].(*index[T])
+	if !ok || cacheNow().After(ix.expires) {
+		return zero, false
+	}
+	item, ok := ix.items[ix.uuids[name]]
+	if !ok {
+		return zero, false
+	}
+	return deepCopy(item), true
+}
+
+// deepCopy copies an object and everything its pointers reach, so a caller changing
+// what a lookup returned never changes the cache. The objects came from JSON, so a
+// JSON round trip keeps every field
+func deepCopy[T any](item T) T {
+	var out T
+	j, err := json.Marshal(item)
+	if err != nil || json.Unmarshal(j, &out) != nil {
+		return item
+	}
+	return out
+}
+
+// fill replaces the collection's index with copies of items
+func fill[T named](c *cache, collection string, items []T) {
+	ix := &index[T]{
+		expires: cacheNow().Add(c.ttl),
+		uuids:   map[string]string{},
+		items:   map[string]T{},
+	}
+	for _, item := range items {
+		name, uuid := item.key()
+		if name == nil || uuid == nil {
+			continue
+		}
+		ix.uuids[*name] = *uuid
+		ix.items[*uuid] = deepCopy(item)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.indexes[collection] = ix
+}
+
+// findByName returns the object called name, or nil if there is none. It answers
+// from the cache when it can and otherwise calls list, refilling the cache
+func findByName[T named](ctx context.Context, s *Client, collection, name string, list func(context.Context) ([]T, error)) (*T, error) {
+	if s.cache != nil {
+		if item, ok := cached[T](s.cache, collection, name); ok {
+			s.log().DebugContext(ctx, "cache hit", "collection", collection, "name", name)
+			return &item, nil
+		}
+	}
+
+	items, err := list(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if s.cache != nil {
+		fill(s.cache, collection, items)
+	}
+
+	for _, item := range items {
+		if n, _ := item.key(); n != nil && *n == name {
+			return &item, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,163 @@
+/*Copyright (c) 2019 Cisco and/or its affiliates.
+
+This software is licensed to you under the terms of the Cisco Sample
+Code License, Version 1.0 (the "License"). You may obtain a copy of the
+License at
+
+               https://developer.cisco.com/docs/licenses
+
+All use of the material herein must be in accordance with the terms of
+the License. All rights not expressly granted by the License are
+reserved. Unless required by applicable law or agreed to separately in
+writing, software distributed under the License is distributed on an "AS
+IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+or implied.*/
+
+package ccp_test
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rob-moss/ccp-clientlibrary-go/ccp"
+	"github.com/rob-moss/ccp-clientlibrary-go/ccp/ccptest"
+)
+
+// lookup resolves a cluster name and returns how many cluster lists that took
+func lookup(t *testing.T, srv *ccptest.Server, client *ccp.Client, name string) (*ccp.Cluster, int, error) {
+	t.Helper()
+
+	before := countRequests(srv, http.MethodGet, "/v3/clusters")
+	c, err := client.GetClusterByName(name)
+	return c, countRequests(srv, http.MethodGet, "/v3/clusters") - before, err
+}
+
+func TestCacheAnswersLookups(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	addClusters(srv, 3)
+	client := newTestClient(t, srv, ccp.WithCache(time.Minute))
+
+	for _, name := range []string{"cluster-1", "cluster-2", "cluster-1", "cluster-3"} {
+		c, _, err := lookup(t, srv, client, name)
+		if err != nil {
+			t.Fatalf("GetClusterByName(%s): %v", name, err)
+		}
+		if *c.Name != name {
+			t.Errorf("GetClusterByName(%s) = %s", name, *c.Name)
+		}
+	}
+	if n := countRequests(srv, http.MethodGet, "/v3/clusters"); n != 1 {
+		t.Errorf("listed clusters %d times for 4 lookups, want 1", n)
+	}
+
+	// a name that isn't cached lists again, it may be new
+	if _, lists, err := lookup(t, srv, client, "missing"); !errors.Is(err, ccp.ErrNotFound) || lists != 1 {
+		t.Errorf("GetClusterByName(missing) = %v after %d lists, want ErrNotFound after 1", err, lists)
+	}
+}
+
+func TestCacheWithoutWithCache(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	addClusters(srv, 1)
+	client := newTestClient(t, srv)
+
+	for i := 0; i < 2; i++ {
+		if _, lists, err := lookup(t, srv, client, "cluster-1"); err != nil || lists != 1 {
+			t.Errorf("GetClusterByName = %v after %d lists, want a fresh list every time", err, lists)
+		}
+	}
+}
+
+func TestCacheDroppedByWrites(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	addClusters(srv, 2)
+	client := newTestClient(t, srv, ccp.WithCache(time.Minute))
+
+	c, _, err := lookup(t, srv, client, "cluster-1")
+	if err != nil {
+		t.Fatalf("GetClusterByName: %v", err)
+	}
+	if err := client.DeleteCluster(*c.UUID); err != nil {
+		t.Fatalf("DeleteCluster: %v", err)
+	}
+	if _, lists, err := lookup(t, srv, client, "cluster-1"); !errors.Is(err, ccp.ErrNotFound) || lists != 1 {
+		t.Errorf("GetClusterByName after DeleteCluster = %v after %d lists, want ErrNotFound after 1", err, lists)
+	}
+	if _, lists, err := lookup(t, srv, client, "cluster-2"); err != nil || lists != 0 {
+		t.Errorf("GetClusterByName(cluster-2) = %v after %d lists, want the cached cluster", err, lists)
+	}
+}
+
+func TestCacheExpiresAndRefreshes(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	addClusters(srv, 1)
+	client := newTestClient(t, srv, ccp.WithCache(time.Minute))
+
+	// the cache reads this clock, so expiry doesn't depend on how fast the test runs
+	var mu sync.Mutex
+	clock := time.Now()
+	defer ccp.SetCacheClock(func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return clock
+	})()
+	advance := func(d time.Duration) {
+		mu.Lock()
+		defer mu.Unlock()
+		clock = clock.Add(d)
+	}
+
+	if _, lists, err := lookup(t, srv, client, "cluster-1"); err != nil || lists != 1 {
+		t.Fatalf("first GetClusterByName = %v after %d lists", err, lists)
+	}
+
+	advance(59 * time.Second)
+	if _, lists, err := lookup(t, srv, client, "cluster-1"); err != nil || lists != 0 {
+		t.Errorf("GetClusterByName before the ttl = %v after %d lists, want the cached cluster", err, lists)
+	}
+
+	advance(2 * time.Second)
+	if _, lists, _ := lookup(t, srv, client, "cluster-1"); lists != 1 {
+		t.Errorf("GetClusterByName after the ttl listed %d times, want 1", lists)
+	}
+
+	client.Refresh()
+	if _, lists, _ := lookup(t, srv, client, "cluster-1"); lists != 1 {
+		t.Errorf("GetClusterByName after Refresh listed %d times, want 1", lists)
+	}
+}
+
+func TestCacheReturnsCopies(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	addClusters(srv, 1)
+	client := newTestClient(t, srv, ccp.WithCache(time.Minute))
+
+	// the first lookup fills the cache, the second answers from it
+	for i := 0; i < 2; i++ {
+		c, _, err := lookup(t, srv, client, "cluster-1")
+		if err != nil || c == nil {
+			t.Fatalf("GetClusterByName: %v, %v", c, err)
+		}
+		if *c.Status != "READY" {
+			t.Fatalf("lookup %d found status %q, want READY: a caller's change leaked into the cache", i+1, *c.Status)
+		}
+		*c.Status = "CHANGED BY CALLER"
+		c.Name = ccp.String("renamed")
+	}
+
+	c, lists, err := lookup(t, srv, client, "cluster-1")
+	if err != nil || c == nil || lists != 0 {
+		t.Fatalf("GetClusterByName = %v, %v after %d lists, want the cached cluster", c, err, lists)
+	}
+	if *c.Name != "cluster-1" || *c.Status != "READY" {
+		t.Errorf("cached cluster is %s %s, want cluster-1 READY", *c.Name, *c.Status)
+	}
+}
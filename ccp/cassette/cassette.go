@@ -0,0 +1,300 @@
+/*Copyright (c) 2019 Cisco and/or its affiliates.
+
+This software is licensed to you under the terms of the Cisco Sample
+Code License, Version 1.0 (the "License"). You may obtain a copy of the
+License at
+
+               https://developer.cisco.com/docs/licenses
+
+All use of the material herein must be in accordance with the terms of
+the License. All rights not expressly granted by the License are
+reserved. Unless required by applicable law or agreed to separately in
+writing, software distributed under the License is distributed on an "AS
+IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+or implied.*/
+
+// Package cassette records the HTTP traffic of a ccp.Client to a file and plays it back,
+// so code built on the client can be tested without a control plane.
+//
+// Record once against a real CCP:
+//
+//	rec, err := cassette.New("testdata/addcluster.json", cassette.ModeRecord)
+//	client, err := ccp.NewClient(url, ccp.WithCredentials(user, pass), ccp.WithTransport(rec))
+//	... drive the client ...
+//	err = rec.Save()
+//
+// and replay in CI with cassette.ModeReplay and the same calls. Tokens, passwords,
+// SSH keys and kubeconfigs are masked before anything is written to the cassette.
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/rob-moss/ccp-clientlibrary-go/ccp"
+)
+
+// Mode selects whether a Recorder talks to the control plane or plays back a cassette
+type Mode int
+
+const (
+	// ModeReplay answers every request from the cassette and never touches the network
+	ModeReplay Mode = iota
+	// ModeRecord sends requests on to the control plane and records them for Save
+	ModeRecord
+)
+
+// Cassette is the file format, a list of request/response pairs in the order they were made
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is one recorded request and the response it got
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a recorded request
+type Request struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// Response is a recorded response
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// secretHeaders are masked in recorded requests and responses
+var secretHeaders = []string{"X-Auth-Token", "Authorization", "Cookie", "Set-Cookie"}
+
+// Recorder is an http.RoundTripper that records or replays a cassette.
+// Give it to a client with ccp.WithTransport
+type Recorder struct {
+	path    string
+	mode    Mode
+	matcher Matcher
+	next    http.RoundTripper
+
+	mu       sync.Mutex
+	cassette Cassette
+	used     []bool // replayed interactions
+}
+
+// Option configures a Recorder in New
+type Option func(*Recorder)
+
+// WithMatcher replaces DefaultMatcher for choosing which recorded request answers a new one
+func WithMatcher(m Matcher) Option {
+	return func(r *Recorder) {
+		r.matcher = m
+	}
+}
+
+// WithTransport sends recorded requests through rt instead of http.DefaultTransport,
+// for example one with the control plane's CA configured
+func WithTransport(rt http.RoundTripper) Option {
+	return func(r *Recorder) {
+		r.next = rt
+	}
+}
+
+// New returns a Recorder for the cassette file at path. In ModeReplay the file must exist
+func New(path string, mode Mode, opts ...Option) (*Recorder, error) {
+	r := &Recorder{
+		path:    path,
+		mode:    mode,
+		matcher: DefaultMatcher,
+		next:    http.DefaultTransport,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	if mode == ModeReplay {
+		j, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(j, &r.cassette); err != nil {
+			return nil, fmt.Errorf("cassette %s: %w", path, err)
+		}
+		r.used = make([]bool, len(r.cassette.Interactions))
+	}
+
+	return r, nil
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	if r.mode == ModeRecord {
+		return r.record(req, body)
+	}
+	return r.replay(req, body)
+}
+
+// record sends req on and keeps the redacted exchange
+func (r *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request: Request{
+			Method: req.Method,
+			URL:    req.URL.String(),
+			Header: redactHeader(req.Header),
+			Body:   string(ccp.RedactBody(body)),
+		},
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Header:     redactHeader(resp.Header),
+			Body:       string(ccp.RedactJSON(respBody)),
+		},
+	})
+	return resp, nil
+}
+
+// replay answers req with the first unused recorded interaction that matches it.
+// When every match has been used the last one is served again, so polling loops keep working
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	body = ccp.RedactBody(body) // the cassette only holds the redacted form
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	found := -1
+	for i, in := range r.cassette.Interactions {
+		if !r.matcher(req, body, in.Request) {
+			continue
+		}
+		found = i
+		if !r.used[i] {
+			break
+		}
+	}
+	if found < 0 {
+		return nil, fmt.Errorf("cassette %s: no recorded interaction for %s %s", r.path, req.Method, req.URL.Path)
+	}
+	r.used[found] = true
+
+	rec := r.cassette.Interactions[found].Response
+	header := rec.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", rec.StatusCode, http.StatusText(rec.StatusCode)),
+		StatusCode:    rec.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(strings.NewReader(rec.Body)),
+		ContentLength: int64(len(rec.Body)),
+		Request:       req,
+	}, nil
+}
+
+// Save writes the recorded interactions to the cassette file. It does nothing in ModeReplay
+func (r *Recorder) Save() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	j, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(r.path, j, 0644)
+}
+
+// redactHeader copies h with the session token and cookies masked
+func redactHeader(h http.Header) http.Header {
+	h = h.Clone()
+	for _, k := range secretHeaders {
+		if h.Get(k) != "" {
+			h.Set(k, ccp.Redacted)
+		}
+	}
+	return h
+}
+
+// Matcher reports whether a recorded request can answer req, whose redacted body is given
+type Matcher func(req *http.Request, body []byte, recorded Request) bool
+
+// DefaultMatcher matches on method, path and JSON body
+var DefaultMatcher = MatchAll(MatchMethod, MatchPath, MatchJSONBody)
+
+// MatchAll matches when every one of matchers does
+func MatchAll(matchers ...Matcher) Matcher {
+	return func(req *http.Request, body []byte, recorded Request) bool {
+		for _, m := range matchers {
+			if !m(req, body, recorded) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// MatchMethod matches on the HTTP method
+func MatchMethod(req *http.Request, body []byte, recorded Request) bool {
+	return req.Method == recorded.Method
+}
+
+// MatchPath matches on the URL path, ignoring scheme and host so a cassette
+// recorded against one control plane replays against any base URL
+func MatchPath(req *http.Request, body []byte, recorded Request) bool {
+	u, err := req.URL.Parse(recorded.URL)
+	if err != nil {
+		return false
+	}
+	return strings.TrimSuffix(req.URL.Path, "/") == strings.TrimSuffix(u.Path, "/")
+}
+
+// MatchJSONBody matches bodies that decode to the same JSON, whatever the key order
+// and whitespace. Bodies that are not JSON must be byte for byte equal
+func MatchJSONBody(req *http.Request, body []byte, recorded Request) bool {
+	if len(body) == 0 && recorded.Body == "" {
+		return true
+	}
+	var a, b interface{}
+	if json.Unmarshal(body, &a) != nil || json.Unmarshal([]byte(recorded.Body), &b) != nil {
+		return string(body) == recorded.Body
+	}
+	return reflect.DeepEqual(a, b)
+}
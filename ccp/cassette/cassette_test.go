@@ -0,0 +1,139 @@
+/*Copyright (c) 2019 Cisco and/or its affiliates.
+
+This software is licensed to you under the terms of the Cisco Sample
+Code License, Version 1.0 (the "License"). You may obtain a copy of the
+License at
+
+               https://developer.cisco.com/docs/licenses
+
+All use of the material herein must be in accordance with the terms of
+the License. All rights not expressly granted by the License are
+reserved. Unless required by applicable law or agreed to separately in
+writing, software distributed under the License is distributed on an "AS
+IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+or implied.*/
+
+package cassette_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rob-moss/ccp-clientlibrary-go/ccp"
+	"github.com/rob-moss/ccp-clientlibrary-go/ccp/cassette"
+	"github.com/rob-moss/ccp-clientlibrary-go/ccp/ccptest"
+)
+
+// Credentials recorded in the cassette, distinct enough to spot if they leak into it
+const (
+	username = "recorder"
+	password = "s3cret-Pa55"
+)
+
+// newClient returns a client for baseURL that sends everything through rec.
+// It doesn't retry, a request missing from the cassette won't appear on a second try
+func newClient(t *testing.T, baseURL string, rec *cassette.Recorder) *ccp.Client {
+	t.Helper()
+
+	client, err := ccp.NewClient(baseURL, ccp.WithCredentials(username, password), ccp.WithTransport(rec), ccp.WithRetryPolicy(nil))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	return client
+}
+
+// record logs in to a fresh ccptest server with one cluster, lists the clusters
+// and saves the cassette. It returns the cassette path and the session token
+func record(t *testing.T) (string, string) {
+	t.Helper()
+
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	srv.AddUser(username, password)
+	srv.AddCluster(ccp.Cluster{Name: ccp.String("recorded"), KubeConfig: ccp.String("apiVersion: v1")})
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	rec, err := cassette.New(path, cassette.ModeRecord)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	client := newClient(t, srv.URL, rec)
+	if err := client.Login(client); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if _, err := client.GetClusters(); err != nil {
+		t.Fatalf("GetClusters: %v", err)
+	}
+	if err := rec.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	return path, client.Token()
+}
+
+func TestRecordRedactsSecrets(t *testing.T) {
+	path, token := record(t)
+
+	j, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, secret := range map[string]string{"password": password, "token": token, "kubeconfig": "apiVersion: v1"} {
+		if strings.Contains(string(j), secret) {
+			t.Errorf("the cassette holds the %s", name)
+		}
+	}
+}
+
+func TestReplay(t *testing.T) {
+	path, _ := record(t)
+
+	rec, err := cassette.New(path, cassette.ModeReplay)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	// no server is listening here, every answer comes from the cassette
+	client := newClient(t, "http://ccp.invalid", rec)
+	if err := client.Login(client); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	clusters, err := client.GetClusters()
+	if err != nil {
+		t.Fatalf("GetClusters: %v", err)
+	}
+	if len(clusters) != 1 || *clusters[0].Name != "recorded" {
+		t.Errorf("GetClusters = %v, want the recorded cluster", clusters)
+	}
+
+	// polling the same request again gets the last recorded answer
+	if _, err := client.GetClusters(); err != nil {
+		t.Errorf("GetClusters again: %v", err)
+	}
+
+	if _, err := client.GetUsers(); err == nil || !strings.Contains(err.Error(), "no recorded interaction") {
+		t.Errorf("GetUsers error = %v, want no recorded interaction", err)
+	}
+}
+
+func TestReplayMatchesOnBody(t *testing.T) {
+	path, _ := record(t)
+
+	rec, err := cassette.New(path, cassette.ModeReplay)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	client := newClient(t, "http://ccp.invalid", rec)
+
+	// the login body differs, so the recorded login doesn't answer it
+	client.Username = "someone-else"
+	if err := client.Login(client); err == nil {
+		t.Error("Login as another user replayed, want no recorded interaction")
+	}
+}
+
+func TestReplayMissingCassette(t *testing.T) {
+	if _, err := cassette.New(filepath.Join(t.TempDir(), "missing.json"), cassette.ModeReplay); err == nil {
+		t.Error("New succeeded without a cassette file")
+	}
+}
@@ -0,0 +1,41 @@
+/*Copyright (c) 2019 Cisco and/or its affiliates.
+
+This software is licensed to you under the terms of the Cisco Sample
+Code License, Version 1.0 (the "License"). You may obtain a copy of the
+License at
+
+               https://developer.cisco.com/docs/licenses
+
+All use of the material herein must be in accordance with the terms of
+the License. All rights not expressly granted by the License are
+reserved. Unless required by applicable law or agreed to separately in
+writing, software distributed under the License is distributed on an "AS
+IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+or implied.*/
+
+// Package ccpmock has mocks of the ccp API interfaces for unit tests.
+// Set the Func field for each call the code under test makes:
+//
+//	clusters := &ccpmock.ClustersAPI{
+//		GetClusterByUUIDFunc: func(ctx context.Context, clusterUUID string) (*ccp.Cluster, error) {
+//			return &ccp.Cluster{UUID: ccp.String(clusterUUID), Status: ccp.String("READY")}, nil
+//		},
+//	}
+//
+// A Foo and FooWithContext pair share the FooFunc field, which is given the context.
+// Calls without a Func set return ErrNotConfigured.
+package ccpmock
+
+//go:generate go run ./internal/mockgen -in ../api.go -out mock.go
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNotConfigured is returned by a mock method whose Func field is not set
+var ErrNotConfigured = errors.New("ccpmock: method not configured")
+
+func notConfigured(method string) error {
+	return fmt.Errorf("%s: %w", method, ErrNotConfigured)
+}
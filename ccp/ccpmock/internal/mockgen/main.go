@@ -0,0 +1,254 @@
+/*Copyright (c) 2019 Cisco and/or its affiliates.
+
+This software is licensed to you under the terms of the Cisco Sample
+Code License, Version 1.0 (the "License"). You may obtain a copy of the
+License at
+
+               https://developer.cisco.com/docs/licenses
+
+All use of the material herein must be in accordance with the terms of
+the License. All rights not expressly granted by the License are
+reserved. Unless required by applicable law or agreed to separately in
+writing, software distributed under the License is distributed on an "AS
+IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+or implied.*/
+
+// mockgen writes the ccpmock mocks from the interfaces in ccp/api.go.
+// Run it with go generate ./ccp/ccpmock
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"strings"
+)
+
+func main() {
+	in := flag.String("in", "../api.go", "file declaring the ccp interfaces")
+	out := flag.String("out", "mock.go", "file to write the mocks to")
+	flag.Parse()
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, *in, nil, 0)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by mockgen from ccp/api.go. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package ccpmock\n\nimport (\n\t\"context\"\n\t\"iter\"\n\n\t\"github.com/rob-moss/ccp-clientlibrary-go/ccp\"\n)\n")
+
+	var names []string
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			ts := spec.(*ast.TypeSpec)
+			iface, ok := ts.Type.(*ast.InterfaceType)
+			if !ok || !onlyMethods(iface) {
+				continue // API embeds the others, it gets the Mock struct below
+			}
+			names = append(names, ts.Name.Name)
+			writeMock(&b, ts.Name.Name, iface)
+		}
+	}
+
+	fmt.Fprintf(&b, "\n// Mock implements the whole ccp.API. Set the Func fields of the embedded mocks\ntype Mock struct {\n")
+	for _, n := range names {
+		fmt.Fprintf(&b, "\t%s\n", n)
+	}
+	fmt.Fprintf(&b, "}\n\nvar _ ccp.API = (*Mock)(nil)\n")
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		log.Fatalf("%v\n%s", err, b.Bytes())
+	}
+	if err := ioutil.WriteFile(*out, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// onlyMethods reports whether iface declares methods rather than embedding other interfaces
+func onlyMethods(iface *ast.InterfaceType) bool {
+	for _, m := range iface.Methods.List {
+		if len(m.Names) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// writeMock writes the mock struct for one interface. A Foo/FooWithContext pair shares
+// one FooFunc field taking the context, Foo calls it with context.Background()
+func writeMock(b *bytes.Buffer, name string, iface *ast.InterfaceType) {
+	methods := map[string]*ast.FuncType{}
+	for _, m := range iface.Methods.List {
+		methods[m.Names[0].Name] = m.Type.(*ast.FuncType)
+	}
+
+	fmt.Fprintf(b, "\n// %s is a configurable mock of ccp.%s. Each method calls its Func field,\n", name, name)
+	fmt.Fprintf(b, "// or returns ErrNotConfigured if the field is nil\ntype %s struct {\n", name)
+	for _, m := range iface.Methods.List {
+		method := m.Names[0].Name
+		if _, ok := methods[method+"WithContext"]; ok {
+			continue // shares the WithContext field
+		}
+		fmt.Fprintf(b, "\t%sFunc func(%s) %s\n", strings.TrimSuffix(method, "WithContext"), params(methods[method], false), results(methods[method]))
+	}
+	fmt.Fprintf(b, "}\n\nvar _ ccp.%s = (*%s)(nil)\n", name, name)
+
+	for _, m := range iface.Methods.List {
+		method := m.Names[0].Name
+		ft := methods[method]
+		if _, ok := methods[method+"WithContext"]; ok {
+			fmt.Fprintf(b, "\n// %s calls %sWithContext with context.Background()\n", method, method)
+			fmt.Fprintf(b, "func (m *%s) %s(%s) %s {\n", name, method, params(ft, true), results(ft))
+			call := fmt.Sprintf("m.%sWithContext(%s)", method, args(ft, "context.Background()"))
+			if ft.Results == nil {
+				fmt.Fprintf(b, "\t%s\n}\n", call)
+			} else {
+				fmt.Fprintf(b, "\treturn %s\n}\n", call)
+			}
+			continue
+		}
+
+		field := strings.TrimSuffix(method, "WithContext") + "Func"
+		fmt.Fprintf(b, "\n// %s calls %s\n", method, field)
+		fmt.Fprintf(b, "func (m *%s) %s(%s) %s {\n", name, method, params(ft, true), results(ft))
+		fmt.Fprintf(b, "\tif m.%s == nil {\n", field)
+		if elem, ok := seqElem(ft); ok {
+			// a nil iterator would panic in a range loop, yield the error instead
+			fmt.Fprintf(b, "\t\treturn func(yield func(%s, error) bool) {\n", elem)
+			fmt.Fprintf(b, "\t\t\tvar zero %s\n\t\t\tyield(zero, notConfigured(%q))\n\t\t}\n", elem, name+"."+method)
+		} else if ft.Results != nil {
+			var zeros []string
+			for i, r := range flatten(ft.Results) {
+				if typeString(r) == "error" {
+					zeros = append(zeros, fmt.Sprintf("notConfigured(%q)", name+"."+strings.TrimSuffix(method, "WithContext")))
+					continue
+				}
+				fmt.Fprintf(b, "\t\tvar r%d %s\n", i, typeString(r))
+				zeros = append(zeros, fmt.Sprintf("r%d", i))
+			}
+			fmt.Fprintf(b, "\t\treturn %s\n", strings.Join(zeros, ", "))
+		} else {
+			fmt.Fprintf(b, "\t\treturn\n")
+		}
+		fmt.Fprintf(b, "\t}\n")
+		call := fmt.Sprintf("m.%s(%s)", field, args(ft, ""))
+		if ft.Results == nil {
+			fmt.Fprintf(b, "\t%s\n}\n", call)
+		} else {
+			fmt.Fprintf(b, "\treturn %s\n}\n", call)
+		}
+	}
+}
+
+// seqElem returns T when ft returns a single iter.Seq2[T, error]
+func seqElem(ft *ast.FuncType) (string, bool) {
+	if ft.Results == nil || len(ft.Results.List) != 1 {
+		return "", false
+	}
+	t := typeString(ft.Results.List[0].Type)
+	if !strings.HasPrefix(t, "iter.Seq2[") || !strings.HasSuffix(t, ", error]") {
+		return "", false
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(t, "iter.Seq2["), ", error]"), true
+}
+
+// params renders the parameter list with ccp types qualified. Without named the names are dropped
+func params(ft *ast.FuncType, named bool) string {
+	var ps []string
+	for _, f := range ft.Params.List {
+		t := typeString(f.Type)
+		if !named || len(f.Names) == 0 {
+			for range max(1, len(f.Names)) {
+				ps = append(ps, t)
+			}
+			continue
+		}
+		var ns []string
+		for _, n := range f.Names {
+			ns = append(ns, n.Name)
+		}
+		ps = append(ps, strings.Join(ns, ", ")+" "+t)
+	}
+	return strings.Join(ps, ", ")
+}
+
+// args renders the call arguments, with first in place of a leading context argument
+func args(ft *ast.FuncType, first string) string {
+	var as []string
+	if first != "" {
+		as = append(as, first)
+	}
+	for _, f := range ft.Params.List {
+		for _, n := range f.Names {
+			as = append(as, n.Name)
+		}
+	}
+	return strings.Join(as, ", ")
+}
+
+// results renders the result list
+func results(ft *ast.FuncType) string {
+	if ft.Results == nil {
+		return ""
+	}
+	rs := flatten(ft.Results)
+	var ts []string
+	for _, r := range rs {
+		ts = append(ts, typeString(r))
+	}
+	if len(ts) == 1 {
+		return ts[0]
+	}
+	return "(" + strings.Join(ts, ", ") + ")"
+}
+
+// flatten expands "a, b T" into one type per name
+func flatten(fl *ast.FieldList) []ast.Expr {
+	var ts []ast.Expr
+	for _, f := range fl.List {
+		for range max(1, len(f.Names)) {
+			ts = append(ts, f.Type)
+		}
+	}
+	return ts
+}
+
+// typeString renders a type expression, qualifying the ccp package's own types
+func typeString(e ast.Expr) string {
+	switch t := e.(type) {
+	case *ast.Ident:
+		if t.IsExported() {
+			return "ccp." + t.Name
+		}
+		return t.Name
+	case *ast.StarExpr:
+		return "*" + typeString(t.X)
+	case *ast.ArrayType:
+		return "[]" + typeString(t.Elt)
+	case *ast.MapType:
+		return "map[" + typeString(t.Key) + "]" + typeString(t.Value)
+	case *ast.SelectorExpr:
+		return typeString(t.X) + "." + t.Sel.Name
+	case *ast.IndexListExpr:
+		var ts []string
+		for _, i := range t.Indices {
+			ts = append(ts, typeString(i))
+		}
+		return typeString(t.X) + "[" + strings.Join(ts, ", ") + "]"
+	}
+	log.Fatalf("unsupported type %T", e)
+	return ""
+}
@@ -0,0 +1,1222 @@
+// Code generated by mockgen from ccp/api.go. DO NOT EDIT.
+
+package ccpmock
+
+import (
+	"context"
+	"iter"
+
+	"github.com/rob-moss/ccp-clientlibrary-go/ccp"
+)
+
+// ClustersAPI is a configurable mock of ccp.ClustersAPI. Each method calls its Func field,
+// or returns ErrNotConfigured if the field is nil
+type ClustersAPI struct {
+	GetClustersFunc            func(context.Context) ([]ccp.Cluster, error)
+	ListClustersFunc           func(context.Context) iter.Seq2[ccp.Cluster, error]
+	GetClusterByNameFunc       func(context.Context, string) (*ccp.Cluster, error)
+	GetClusterByUUIDFunc       func(context.Context, string) (*ccp.Cluster, error)
+	GetClusterStatusByNameFunc func(context.Context, string) (*string, error)
+	AddClusterFunc             func(context.Context, *ccp.Cluster) (*ccp.Cluster, error)
+	AddClusterBasicFunc        func(context.Context, *ccp.Cluster) (*ccp.Cluster, error)
+	AddClusterSynchronousFunc  func(context.Context, *ccp.Cluster) (*ccp.Cluster, error)
+	PatchClusterFunc           func(context.Context, *ccp.Cluster, string) (*ccp.Cluster, error)
+	DeleteClusterFunc          func(context.Context, string) error
+	ScaleClusterFunc           func(context.Context, string, string, int) (*ccp.Cluster, error)
+}
+
+var _ ccp.ClustersAPI = (*ClustersAPI)(nil)
+
+// GetClusters calls GetClustersWithContext with context.Background()
+func (m *ClustersAPI) GetClusters() ([]ccp.Cluster, error) {
+	return m.GetClustersWithContext(context.Background())
+}
+
+// GetClustersWithContext calls GetClustersFunc
+func (m *ClustersAPI) GetClustersWithContext(ctx context.Context) ([]ccp.Cluster, error) {
+	if m.GetClustersFunc == nil {
+		var r0 []ccp.Cluster
+		return r0, notConfigured("ClustersAPI.GetClusters")
+	}
+	return m.GetClustersFunc(ctx)
+}
+
+// ListClusters calls ListClustersFunc
+func (m *ClustersAPI) ListClusters(ctx context.Context) iter.Seq2[ccp.Cluster, error] {
+	if m.ListClustersFunc == nil {
+		return func(yield func(ccp.Cluster, error) bool) {
+			var zero ccp.Cluster
+			yield(zero, notConfigured("ClustersAPI.ListClusters"))
+		}
+	}
+	return m.ListClustersFunc(ctx)
+}
+
+// GetClusterByName calls GetClusterByNameWithContext with context.Background()
+func (m *ClustersAPI) GetClusterByName(clusterName string) (*ccp.Cluster, error) {
+	return m.GetClusterByNameWithContext(context.Background(), clusterName)
+}
+
+// GetClusterByNameWithContext calls GetClusterByNameFunc
+func (m *ClustersAPI) GetClusterByNameWithContext(ctx context.Context, clusterName string) (*ccp.Cluster, error) {
+	if m.GetClusterByNameFunc == nil {
+		var r0 *ccp.Cluster
+		return r0, notConfigured("ClustersAPI.GetClusterByName")
+	}
+	return m.GetClusterByNameFunc(ctx, clusterName)
+}
+
+// GetClusterByUUID calls GetClusterByUUIDWithContext with context.Background()
+func (m *ClustersAPI) GetClusterByUUID(clusterUUID string) (*ccp.Cluster, error) {
+	return m.GetClusterByUUIDWithContext(context.Background(), clusterUUID)
+}
+
+// GetClusterByUUIDWithContext calls GetClusterByUUIDFunc
+func (m *ClustersAPI) GetClusterByUUIDWithContext(ctx context.Context, clusterUUID string) (*ccp.Cluster, error) {
+	if m.GetClusterByUUIDFunc == nil {
+		var r0 *ccp.Cluster
+		return r0, notConfigured("ClustersAPI.GetClusterByUUID")
+	}
+	return m.GetClusterByUUIDFunc(ctx, clusterUUID)
+}
+
+// GetClusterStatusByName calls GetClusterStatusByNameWithContext with context.Background()
+func (m *ClustersAPI) GetClusterStatusByName(clusterName string) (*string, error) {
+	return m.GetClusterStatusByNameWithContext(context.Background(), clusterName)
+}
+
+// GetClusterStatusByNameWithContext calls GetClusterStatusByNameFunc
+func (m *ClustersAPI) GetClusterStatusByNameWithContext(ctx context.Context, clusterName string) (*string, error) {
+	if m.GetClusterStatusByNameFunc == nil {
+		var r0 *string
+		return r0, notConfigured("ClustersAPI.GetClusterStatusByName")
+	}
+	return m.GetClusterStatusByNameFunc(ctx, clusterName)
+}
+
+// AddCluster calls AddClusterWithContext with context.Background()
+func (m *ClustersAPI) AddCluster(cluster *ccp.Cluster) (*ccp.Cluster, error) {
+	return m.AddClusterWithContext(context.Background(), cluster)
+}
+
+// AddClusterWithContext calls AddClusterFunc
+func (m *ClustersAPI) AddClusterWithContext(ctx context.Context, cluster *ccp.Cluster) (*ccp.Cluster, error) {
+	if m.AddClusterFunc == nil {
+		var r0 *ccp.Cluster
+		return r0, notConfigured("ClustersAPI.AddCluster")
+	}
+	return m.AddClusterFunc(ctx, cluster)
+}
+
+// AddClusterBasic calls AddClusterBasicWithContext with context.Background()
+func (m *ClustersAPI) AddClusterBasic(cluster *ccp.Cluster) (*ccp.Cluster, error) {
+	return m.AddClusterBasicWithContext(context.Background(), cluster)
+}
+
+// AddClusterBasicWithContext calls AddClusterBasicFunc
+func (m *ClustersAPI) AddClusterBasicWithContext(ctx context.Context, cluster *ccp.Cluster) (*ccp.Cluster, error) {
+	if m.AddClusterBasicFunc == nil {
+		var r0 *ccp.Cluster
+		return r0, notConfigured("ClustersAPI.AddClusterBasic")
+	}
+	return m.AddClusterBasicFunc(ctx, cluster)
+}
+
+// AddClusterSynchronous calls AddClusterSynchronousWithContext with context.Background()
+func (m *ClustersAPI) AddClusterSynchronous(cluster *ccp.Cluster) (*ccp.Cluster, error) {
+	return m.AddClusterSynchronousWithContext(context.Background(), cluster)
+}
+
+// AddClusterSynchronousWithContext calls AddClusterSynchronousFunc
+func (m *ClustersAPI) AddClusterSynchronousWithContext(ctx context.Context, cluster *ccp.Cluster) (*ccp.Cluster, error) {
+	if m.AddClusterSynchronousFunc == nil {
+		var r0 *ccp.Cluster
+		return r0, notConfigured("ClustersAPI.AddClusterSynchronous")
+	}
+	return m.AddClusterSynchronousFunc(ctx, cluster)
+}
+
+// PatchCluster calls PatchClusterWithContext with context.Background()
+func (m *ClustersAPI) PatchCluster(cluster *ccp.Cluster, clusterUUID string) (*ccp.Cluster, error) {
+	return m.PatchClusterWithContext(context.Background(), cluster, clusterUUID)
+}
+
+// PatchClusterWithContext calls PatchClusterFunc
+func (m *ClustersAPI) PatchClusterWithContext(ctx context.Context, cluster *ccp.Cluster, clusterUUID string) (*ccp.Cluster, error) {
+	if m.PatchClusterFunc == nil {
+		var r0 *ccp.Cluster
+		return r0, notConfigured("ClustersAPI.PatchCluster")
+	}
+	return m.PatchClusterFunc(ctx, cluster, clusterUUID)
+}
+
+// DeleteCluster calls DeleteClusterWithContext with context.Background()
+func (m *ClustersAPI) DeleteCluster(clusterUUID string) error {
+	return m.DeleteClusterWithContext(context.Background(), clusterUUID)
+}
+
+// DeleteClusterWithContext calls DeleteClusterFunc
+func (m *ClustersAPI) DeleteClusterWithContext(ctx context.Context, clusterUUID string) error {
+	if m.DeleteClusterFunc == nil {
+		return notConfigured("ClustersAPI.DeleteCluster")
+	}
+	return m.DeleteClusterFunc(ctx, clusterUUID)
+}
+
+// ScaleCluster calls ScaleClusterWithContext with context.Background()
+func (m *ClustersAPI) ScaleCluster(clusterUUID, workerPoolName string, size int) (*ccp.Cluster, error) {
+	return m.ScaleClusterWithContext(context.Background(), clusterUUID, workerPoolName, size)
+}
+
+// ScaleClusterWithContext calls ScaleClusterFunc
+func (m *ClustersAPI) ScaleClusterWithContext(ctx context.Context, clusterUUID, workerPoolName string, size int) (*ccp.Cluster, error) {
+	if m.ScaleClusterFunc == nil {
+		var r0 *ccp.Cluster
+		return r0, notConfigured("ClustersAPI.ScaleCluster")
+	}
+	return m.ScaleClusterFunc(ctx, clusterUUID, workerPoolName, size)
+}
+
+// AddonsAPI is a configurable mock of ccp.AddonsAPI. Each method calls its Func field,
+// or returns ErrNotConfigured if the field is nil
+type AddonsAPI struct {
+	GetAddonsCatalogueFunc         func(context.Context, string) (*ccp.AddonsCatalogue, error)
+	GetClusterInstalledAddonsFunc  func(context.Context, string) (*ccp.ClusterInstalledAddons, error)
+	ListClusterInstalledAddonsFunc func(context.Context, string) iter.Seq2[ccp.InstalledAddon, error]
+	InstallAddonIstioOpFunc        func(context.Context, string) error
+	InstallAddonIstioInstanceFunc  func(context.Context, string) error
+	InstallAddonIstioFunc          func(context.Context, string) error
+	InstallAddonDashboardFunc      func(context.Context, string) error
+	InstallAddonMonitoringFunc     func(context.Context, string) error
+	InstallAddonLoggingFunc        func(context.Context, string) error
+	InstallAddonHarborOpFunc       func(context.Context, string) error
+	InstallAddonHarborInstanceFunc func(context.Context, string) error
+	InstallAddonHarborFunc         func(context.Context, string) error
+	InstallAddonHXCSIFunc          func(context.Context, string) error
+	InstallAddonKubeflowFunc       func(context.Context, string) error
+	DeleteAddonLoggingFunc         func(context.Context, string) error
+	DeleteAddonMonitorFunc         func(context.Context, string) error
+	DeleteAddonIstioInstanceFunc   func(context.Context, string) error
+	DeleteAddonIstioOpFunc         func(context.Context, string) error
+	DeleteAddonIstioFunc           func(context.Context, string) error
+	DeleteAddonDashboardFunc       func(context.Context, string) error
+	DeleteAddonHarborInstanceFunc  func(context.Context, string) error
+	DeleteAddonHarborOpFunc        func(context.Context, string) error
+	DeleteAddonHarborFunc          func(context.Context, string) error
+	DeleteAddonHXCSIFunc           func(context.Context, string) error
+	DeleteAddonKubeflowFunc        func(context.Context, string) error
+}
+
+var _ ccp.AddonsAPI = (*AddonsAPI)(nil)
+
+// GetAddonsCatalogue calls GetAddonsCatalogueWithContext with context.Background()
+func (m *AddonsAPI) GetAddonsCatalogue(clusterUUID string) (*ccp.AddonsCatalogue, error) {
+	return m.GetAddonsCatalogueWithContext(context.Background(), clusterUUID)
+}
+
+// GetAddonsCatalogueWithContext calls GetAddonsCatalogueFunc
+func (m *AddonsAPI) GetAddonsCatalogueWithContext(ctx context.Context, clusterUUID string) (*ccp.AddonsCatalogue, error) {
+	if m.GetAddonsCatalogueFunc == nil {
+		var r0 *ccp.AddonsCatalogue
+		return r0, notConfigured("AddonsAPI.GetAddonsCatalogue")
+	}
+	return m.GetAddonsCatalogueFunc(ctx, clusterUUID)
+}
+
+// GetClusterInstalledAddons calls GetClusterInstalledAddonsWithContext with context.Background()
+func (m *AddonsAPI) GetClusterInstalledAddons(clusterUUID string) (*ccp.ClusterInstalledAddons, error) {
+	return m.GetClusterInstalledAddonsWithContext(context.Background(), clusterUUID)
+}
+
+// GetClusterInstalledAddonsWithContext calls GetClusterInstalledAddonsFunc
+func (m *AddonsAPI) GetClusterInstalledAddonsWithContext(ctx context.Context, clusterUUID string) (*ccp.ClusterInstalledAddons, error) {
+	if m.GetClusterInstalledAddonsFunc == nil {
+		var r0 *ccp.ClusterInstalledAddons
+		return r0, notConfigured("AddonsAPI.GetClusterInstalledAddons")
+	}
+	return m.GetClusterInstalledAddonsFunc(ctx, clusterUUID)
+}
+
+// ListClusterInstalledAddons calls ListClusterInstalledAddonsFunc
+func (m *AddonsAPI) ListClusterInstalledAddons(ctx context.Context, clusterUUID string) iter.Seq2[ccp.InstalledAddon, error] {
+	if m.ListClusterInstalledAddonsFunc == nil {
+		return func(yield func(ccp.InstalledAddon, error) bool) {
+			var zero ccp.InstalledAddon
+			yield(zero, notConfigured("AddonsAPI.ListClusterInstalledAddons"))
+		}
+	}
+	return m.ListClusterInstalledAddonsFunc(ctx, clusterUUID)
+}
+
+// InstallAddonIstioOp calls InstallAddonIstioOpWithContext with context.Background()
+func (m *AddonsAPI) InstallAddonIstioOp(clusterUUID string) error {
+	return m.InstallAddonIstioOpWithContext(context.Background(), clusterUUID)
+}
+
+// InstallAddonIstioOpWithContext calls InstallAddonIstioOpFunc
+func (m *AddonsAPI) InstallAddonIstioOpWithContext(ctx context.Context, clusterUUID string) error {
+	if m.InstallAddonIstioOpFunc == nil {
+		return notConfigured("AddonsAPI.InstallAddonIstioOp")
+	}
+	return m.InstallAddonIstioOpFunc(ctx, clusterUUID)
+}
+
+// InstallAddonIstioInstance calls InstallAddonIstioInstanceWithContext with context.Background()
+func (m *AddonsAPI) InstallAddonIstioInstance(clusterUUID string) error {
+	return m.InstallAddonIstioInstanceWithContext(context.Background(), clusterUUID)
+}
+
+// InstallAddonIstioInstanceWithContext calls InstallAddonIstioInstanceFunc
+func (m *AddonsAPI) InstallAddonIstioInstanceWithContext(ctx context.Context, clusterUUID string) error {
+	if m.InstallAddonIstioInstanceFunc == nil {
+		return notConfigured("AddonsAPI.InstallAddonIstioInstance")
+	}
+	return m.InstallAddonIstioInstanceFunc(ctx, clusterUUID)
+}
+
+// InstallAddonIstio calls InstallAddonIstioWithContext with context.Background()
+func (m *AddonsAPI) InstallAddonIstio(clusterUUID string) error {
+	return m.InstallAddonIstioWithContext(context.Background(), clusterUUID)
+}
+
+// InstallAddonIstioWithContext calls InstallAddonIstioFunc
+func (m *AddonsAPI) InstallAddonIstioWithContext(ctx context.Context, clusterUUID string) error {
+	if m.InstallAddonIstioFunc == nil {
+		return notConfigured("AddonsAPI.InstallAddonIstio")
+	}
+	return m.InstallAddonIstioFunc(ctx, clusterUUID)
+}
+
+// InstallAddonDashboard calls InstallAddonDashboardWithContext with context.Background()
+func (m *AddonsAPI) InstallAddonDashboard(clusterUUID string) error {
+	return m.InstallAddonDashboardWithContext(context.Background(), clusterUUID)
+}
+
+// InstallAddonDashboardWithContext calls InstallAddonDashboardFunc
+func (m *AddonsAPI) InstallAddonDashboardWithContext(ctx context.Context, clusterUUID string) error {
+	if m.InstallAddonDashboardFunc == nil {
+		return notConfigured("AddonsAPI.InstallAddonDashboard")
+	}
+	return m.InstallAddonDashboardFunc(ctx, clusterUUID)
+}
+
+// InstallAddonMonitoring calls InstallAddonMonitoringWithContext with context.Background()
+func (m *AddonsAPI) InstallAddonMonitoring(clusterUUID string) error {
+	return m.InstallAddonMonitoringWithContext(context.Background(), clusterUUID)
+}
+
+// InstallAddonMonitoringWithContext calls InstallAddonMonitoringFunc
+func (m *AddonsAPI) InstallAddonMonitoringWithContext(ctx context.Context, clusterUUID string) error {
+	if m.InstallAddonMonitoringFunc == nil {
+		return notConfigured("AddonsAPI.InstallAddonMonitoring")
+	}
+	return m.InstallAddonMonitoringFunc(ctx, clusterUUID)
+}
+
+// InstallAddonLogging calls InstallAddonLoggingWithContext with context.Background()
+func (m *AddonsAPI) InstallAddonLogging(clusterUUID string) error {
+	return m.InstallAddonLoggingWithContext(context.Background(), clusterUUID)
+}
+
+// InstallAddonLoggingWithContext calls InstallAddonLoggingFunc
+func (m *AddonsAPI) InstallAddonLoggingWithContext(ctx context.Context, clusterUUID string) error {
+	if m.InstallAddonLoggingFunc == nil {
+		return notConfigured("AddonsAPI.InstallAddonLogging")
+	}
+	return m.InstallAddonLoggingFunc(ctx, clusterUUID)
+}
+
+// InstallAddonHarborOp calls InstallAddonHarborOpWithContext with context.Background()
+func (m *AddonsAPI) InstallAddonHarborOp(clusterUUID string) error {
+	return m.InstallAddonHarborOpWithContext(context.Background(), clusterUUID)
+}
+
+// InstallAddonHarborOpWithContext calls InstallAddonHarborOpFunc
+func (m *AddonsAPI) InstallAddonHarborOpWithContext(ctx context.Context, clusterUUID string) error {
+	if m.InstallAddonHarborOpFunc == nil {
+		return notConfigured("AddonsAPI.InstallAddonHarborOp")
+	}
+	return m.InstallAddonHarborOpFunc(ctx, clusterUUID)
+}
+
+// InstallAddonHarborInstance calls InstallAddonHarborInstanceWithContext with context.Background()
+func (m *AddonsAPI) InstallAddonHarborInstance(clusterUUID string) error {
+	return m.InstallAddonHarborInstanceWithContext(context.Background(), clusterUUID)
+}
+
+// InstallAddonHarborInstanceWithContext calls InstallAddonHarborInstanceFunc
+func (m *AddonsAPI) InstallAddonHarborInstanceWithContext(ctx context.Context, clusterUUID string) error {
+	if m.InstallAddonHarborInstanceFunc == nil {
+		return notConfigured("AddonsAPI.InstallAddonHarborInstance")
+	}
+	return m.InstallAddonHarborInstanceFunc(ctx, clusterUUID)
+}
+
+// InstallAddonHarbor calls InstallAddonHarborWithContext with context.Background()
+func (m *AddonsAPI) InstallAddonHarbor(clusterUUID string) error {
+	return m.InstallAddonHarborWithContext(context.Background(), clusterUUID)
+}
+
+// InstallAddonHarborWithContext calls InstallAddonHarborFunc
+func (m *AddonsAPI) InstallAddonHarborWithContext(ctx context.Context, clusterUUID string) error {
+	if m.InstallAddonHarborFunc == nil {
+		return notConfigured("AddonsAPI.InstallAddonHarbor")
+	}
+	return m.InstallAddonHarborFunc(ctx, clusterUUID)
+}
+
+// InstallAddonHXCSI calls InstallAddonHXCSIWithContext with context.Background()
+func (m *AddonsAPI) InstallAddonHXCSI(clusterUUID string) error {
+	return m.InstallAddonHXCSIWithContext(context.Background(), clusterUUID)
+}
+
+// InstallAddonHXCSIWithContext calls InstallAddonHXCSIFunc
+func (m *AddonsAPI) InstallAddonHXCSIWithContext(ctx context.Context, clusterUUID string) error {
+	if m.InstallAddonHXCSIFunc == nil {
+		return notConfigured("AddonsAPI.InstallAddonHXCSI")
+	}
+	return m.InstallAddonHXCSIFunc(ctx, clusterUUID)
+}
+
+// InstallAddonKubeflow calls InstallAddonKubeflowWithContext with context.Background()
+func (m *AddonsAPI) InstallAddonKubeflow(clusterUUID string) error {
+	return m.InstallAddonKubeflowWithContext(context.Background(), clusterUUID)
+}
+
+// InstallAddonKubeflowWithContext calls InstallAddonKubeflowFunc
+func (m *AddonsAPI) InstallAddonKubeflowWithContext(ctx context.Context, clusterUUID string) error {
+	if m.InstallAddonKubeflowFunc == nil {
+		return notConfigured("AddonsAPI.InstallAddonKubeflow")
+	}
+	return m.InstallAddonKubeflowFunc(ctx, clusterUUID)
+}
+
+// DeleteAddonLogging calls DeleteAddonLoggingWithContext with context.Background()
+func (m *AddonsAPI) DeleteAddonLogging(clusterUUID string) error {
+	return m.DeleteAddonLoggingWithContext(context.Background(), clusterUUID)
+}
+
+// DeleteAddonLoggingWithContext calls DeleteAddonLoggingFunc
+func (m *AddonsAPI) DeleteAddonLoggingWithContext(ctx context.Context, clusterUUID string) error {
+	if m.DeleteAddonLoggingFunc == nil {
+		return notConfigured("AddonsAPI.DeleteAddonLogging")
+	}
+	return m.DeleteAddonLoggingFunc(ctx, clusterUUID)
+}
+
+// DeleteAddonMonitor calls DeleteAddonMonitorWithContext with context.Background()
+func (m *AddonsAPI) DeleteAddonMonitor(clusterUUID string) error {
+	return m.DeleteAddonMonitorWithContext(context.Background(), clusterUUID)
+}
+
+// DeleteAddonMonitorWithContext calls DeleteAddonMonitorFunc
+func (m *AddonsAPI) DeleteAddonMonitorWithContext(ctx context.Context, clusterUUID string) error {
+	if m.DeleteAddonMonitorFunc == nil {
+		return notConfigured("AddonsAPI.DeleteAddonMonitor")
+	}
+	return m.DeleteAddonMonitorFunc(ctx, clusterUUID)
+}
+
+// DeleteAddonIstioInstance calls DeleteAddonIstioInstanceWithContext with context.Background()
+func (m *AddonsAPI) DeleteAddonIstioInstance(clusterUUID string) error {
+	return m.DeleteAddonIstioInstanceWithContext(context.Background(), clusterUUID)
+}
+
+// DeleteAddonIstioInstanceWithContext calls DeleteAddonIstioInstanceFunc
+func (m *AddonsAPI) DeleteAddonIstioInstanceWithContext(ctx context.Context, clusterUUID string) error {
+	if m.DeleteAddonIstioInstanceFunc == nil {
+		return notConfigured("AddonsAPI.DeleteAddonIstioInstance")
+	}
+	return m.DeleteAddonIstioInstanceFunc(ctx, clusterUUID)
+}
+
+// DeleteAddonIstioOp calls DeleteAddonIstioOpWithContext with context.Background()
+func (m *AddonsAPI) DeleteAddonIstioOp(clusterUUID string) error {
+	return m.DeleteAddonIstioOpWithContext(context.Background(), clusterUUID)
+}
+
+// DeleteAddonIstioOpWithContext calls DeleteAddonIstioOpFunc
+func (m *AddonsAPI) DeleteAddonIstioOpWithContext(ctx context.Context, clusterUUID string) error {
+	if m.DeleteAddonIstioOpFunc == nil {
+		return notConfigured("AddonsAPI.DeleteAddonIstioOp")
+	}
+	return m.DeleteAddonIstioOpFunc(ctx, clusterUUID)
+}
+
+// DeleteAddonIstio calls DeleteAddonIstioWithContext with context.Background()
+func (m *AddonsAPI) DeleteAddonIstio(clusterUUID string) error {
+	return m.DeleteAddonIstioWithContext(context.Background(), clusterUUID)
+}
+
+// DeleteAddonIstioWithContext calls DeleteAddonIstioFunc
+func (m *AddonsAPI) DeleteAddonIstioWithContext(ctx context.Context, clusterUUID string) error {
+	if m.DeleteAddonIstioFunc == nil {
+		return notConfigured("AddonsAPI.DeleteAddonIstio")
+	}
+	return m.DeleteAddonIstioFunc(ctx, clusterUUID)
+}
+
+// DeleteAddonDashboard calls DeleteAddonDashboardWithContext with context.Background()
+func (m *AddonsAPI) DeleteAddonDashboard(clusterUUID string) error {
+	return m.DeleteAddonDashboardWithContext(context.Background(), clusterUUID)
+}
+
+// DeleteAddonDashboardWithContext calls DeleteAddonDashboardFunc
+func (m *AddonsAPI) DeleteAddonDashboardWithContext(ctx context.Context, clusterUUID string) error {
+	if m.DeleteAddonDashboardFunc == nil {
+		return notConfigured("AddonsAPI.DeleteAddonDashboard")
+	}
+	return m.DeleteAddonDashboardFunc(ctx, clusterUUID)
+}
+
+// DeleteAddonHarborInstance calls DeleteAddonHarborInstanceWithContext with context.Background()
+func (m *AddonsAPI) DeleteAddonHarborInstance(clusterUUID string) error {
+	return m.DeleteAddonHarborInstanceWithContext(context.Background(), clusterUUID)
+}
+
+// DeleteAddonHarborInstanceWithContext calls DeleteAddonHarborInstanceFunc
+func (m *AddonsAPI) DeleteAddonHarborInstanceWithContext(ctx context.Context, clusterUUID string) error {
+	if m.DeleteAddonHarborInstanceFunc == nil {
+		return notConfigured("AddonsAPI.DeleteAddonHarborInstance")
+	}
+	return m.DeleteAddonHarborInstanceFunc(ctx, clusterUUID)
+}
+
+// DeleteAddonHarborOp calls DeleteAddonHarborOpWithContext with context.Background()
+func (m *AddonsAPI) DeleteAddonHarborOp(clusterUUID string) error {
+	return m.DeleteAddonHarborOpWithContext(context.Background(), clusterUUID)
+}
+
+// DeleteAddonHarborOpWithContext calls DeleteAddonHarborOpFunc
+func (m *AddonsAPI) DeleteAddonHarborOpWithContext(ctx context.Context, clusterUUID string) error {
+	if m.DeleteAddonHarborOpFunc == nil {
+		return notConfigured("AddonsAPI.DeleteAddonHarborOp")
+	}
+	return m.DeleteAddonHarborOpFunc(ctx, clusterUUID)
+}
+
+// DeleteAddonHarbor calls DeleteAddonHarborWithContext with context.Background()
+func (m *AddonsAPI) DeleteAddonHarbor(clusterUUID string) error {
+	return m.DeleteAddonHarborWithContext(context.Background(), clusterUUID)
+}
+
+// DeleteAddonHarborWithContext calls DeleteAddonHarborFunc
+func (m *AddonsAPI) DeleteAddonHarborWithContext(ctx context.Context, clusterUUID string) error {
+	if m.DeleteAddonHarborFunc == nil {
+		return notConfigured("AddonsAPI.DeleteAddonHarbor")
+	}
+	return m.DeleteAddonHarborFunc(ctx, clusterUUID)
+}
+
+// DeleteAddonHXCSI calls DeleteAddonHXCSIWithContext with context.Background()
+func (m *AddonsAPI) DeleteAddonHXCSI(clusterUUID string) error {
+	return m.DeleteAddonHXCSIWithContext(context.Background(), clusterUUID)
+}
+
+// DeleteAddonHXCSIWithContext calls DeleteAddonHXCSIFunc
+func (m *AddonsAPI) DeleteAddonHXCSIWithContext(ctx context.Context, clusterUUID string) error {
+	if m.DeleteAddonHXCSIFunc == nil {
+		return notConfigured("AddonsAPI.DeleteAddonHXCSI")
+	}
+	return m.DeleteAddonHXCSIFunc(ctx, clusterUUID)
+}
+
+// DeleteAddonKubeflow calls DeleteAddonKubeflowWithContext with context.Background()
+func (m *AddonsAPI) DeleteAddonKubeflow(clusterUUID string) error {
+	return m.DeleteAddonKubeflowWithContext(context.Background(), clusterUUID)
+}
+
+// DeleteAddonKubeflowWithContext calls DeleteAddonKubeflowFunc
+func (m *AddonsAPI) DeleteAddonKubeflowWithContext(ctx context.Context, clusterUUID string) error {
+	if m.DeleteAddonKubeflowFunc == nil {
+		return notConfigured("AddonsAPI.DeleteAddonKubeflow")
+	}
+	return m.DeleteAddonKubeflowFunc(ctx, clusterUUID)
+}
+
+// ProvidersAPI is a configurable mock of ccp.ProvidersAPI. Each method calls its Func field,
+// or returns ErrNotConfigured if the field is nil
+type ProvidersAPI struct {
+	GetInfraProvidersFunc      func(context.Context) ([]ccp.ProviderClientConfig, error)
+	ListInfraProvidersFunc     func(context.Context) iter.Seq2[ccp.ProviderClientConfig, error]
+	GetInfraProviderByUUIDFunc func(context.Context, string) (*ccp.ProviderClientConfig, error)
+	GetInfraProviderByNameFunc func(context.Context, string) (*ccp.ProviderClientConfig, error)
+}
+
+var _ ccp.ProvidersAPI = (*ProvidersAPI)(nil)
+
+// GetInfraProviders calls GetInfraProvidersWithContext with context.Background()
+func (m *ProvidersAPI) GetInfraProviders() ([]ccp.ProviderClientConfig, error) {
+	return m.GetInfraProvidersWithContext(context.Background())
+}
+
+// GetInfraProvidersWithContext calls GetInfraProvidersFunc
+func (m *ProvidersAPI) GetInfraProvidersWithContext(ctx context.Context) ([]ccp.ProviderClientConfig, error) {
+	if m.GetInfraProvidersFunc == nil {
+		var r0 []ccp.ProviderClientConfig
+		return r0, notConfigured("ProvidersAPI.GetInfraProviders")
+	}
+	return m.GetInfraProvidersFunc(ctx)
+}
+
+// ListInfraProviders calls ListInfraProvidersFunc
+func (m *ProvidersAPI) ListInfraProviders(ctx context.Context) iter.Seq2[ccp.ProviderClientConfig, error] {
+	if m.ListInfraProvidersFunc == nil {
+		return func(yield func(ccp.ProviderClientConfig, error) bool) {
+			var zero ccp.ProviderClientConfig
+			yield(zero, notConfigured("ProvidersAPI.ListInfraProviders"))
+		}
+	}
+	return m.ListInfraProvidersFunc(ctx)
+}
+
+// GetInfraProviderByUUID calls GetInfraProviderByUUIDWithContext with context.Background()
+func (m *ProvidersAPI) GetInfraProviderByUUID(providerUUID string) (*ccp.ProviderClientConfig, error) {
+	return m.GetInfraProviderByUUIDWithContext(context.Background(), providerUUID)
+}
+
+// GetInfraProviderByUUIDWithContext calls GetInfraProviderByUUIDFunc
+func (m *ProvidersAPI) GetInfraProviderByUUIDWithContext(ctx context.Context, providerUUID string) (*ccp.ProviderClientConfig, error) {
+	if m.GetInfraProviderByUUIDFunc == nil {
+		var r0 *ccp.ProviderClientConfig
+		return r0, notConfigured("ProvidersAPI.GetInfraProviderByUUID")
+	}
+	return m.GetInfraProviderByUUIDFunc(ctx, providerUUID)
+}
+
+// GetInfraProviderByName calls GetInfraProviderByNameWithContext with context.Background()
+func (m *ProvidersAPI) GetInfraProviderByName(providerName string) (*ccp.ProviderClientConfig, error) {
+	return m.GetInfraProviderByNameWithContext(context.Background(), providerName)
+}
+
+// GetInfraProviderByNameWithContext calls GetInfraProviderByNameFunc
+func (m *ProvidersAPI) GetInfraProviderByNameWithContext(ctx context.Context, providerName string) (*ccp.ProviderClientConfig, error) {
+	if m.GetInfraProviderByNameFunc == nil {
+		var r0 *ccp.ProviderClientConfig
+		return r0, notConfigured("ProvidersAPI.GetInfraProviderByName")
+	}
+	return m.GetInfraProviderByNameFunc(ctx, providerName)
+}
+
+// SubnetsAPI is a configurable mock of ccp.SubnetsAPI. Each method calls its Func field,
+// or returns ErrNotConfigured if the field is nil
+type SubnetsAPI struct {
+	GetNetworkProviderSubnetsFunc      func(context.Context) ([]ccp.NetworkProviderSubnet, error)
+	ListNetworkProviderSubnetsFunc     func(context.Context) iter.Seq2[ccp.NetworkProviderSubnet, error]
+	GetNetworkProviderSubnetByNameFunc func(context.Context, string) (*ccp.NetworkProviderSubnet, error)
+}
+
+var _ ccp.SubnetsAPI = (*SubnetsAPI)(nil)
+
+// GetNetworkProviderSubnets calls GetNetworkProviderSubnetsWithContext with context.Background()
+func (m *SubnetsAPI) GetNetworkProviderSubnets() ([]ccp.NetworkProviderSubnet, error) {
+	return m.GetNetworkProviderSubnetsWithContext(context.Background())
+}
+
+// GetNetworkProviderSubnetsWithContext calls GetNetworkProviderSubnetsFunc
+func (m *SubnetsAPI) GetNetworkProviderSubnetsWithContext(ctx context.Context) ([]ccp.NetworkProviderSubnet, error) {
+	if m.GetNetworkProviderSubnetsFunc == nil {
+		var r0 []ccp.NetworkProviderSubnet
+		return r0, notConfigured("SubnetsAPI.GetNetworkProviderSubnets")
+	}
+	return m.GetNetworkProviderSubnetsFunc(ctx)
+}
+
+// ListNetworkProviderSubnets calls ListNetworkProviderSubnetsFunc
+func (m *SubnetsAPI) ListNetworkProviderSubnets(ctx context.Context) iter.Seq2[ccp.NetworkProviderSubnet, error] {
+	if m.ListNetworkProviderSubnetsFunc == nil {
+		return func(yield func(ccp.NetworkProviderSubnet, error) bool) {
+			var zero ccp.NetworkProviderSubnet
+			yield(zero, notConfigured("SubnetsAPI.ListNetworkProviderSubnets"))
+		}
+	}
+	return m.ListNetworkProviderSubnetsFunc(ctx)
+}
+
+// GetNetworkProviderSubnetByName calls GetNetworkProviderSubnetByNameWithContext with context.Background()
+func (m *SubnetsAPI) GetNetworkProviderSubnetByName(networkProviderName string) (*ccp.NetworkProviderSubnet, error) {
+	return m.GetNetworkProviderSubnetByNameWithContext(context.Background(), networkProviderName)
+}
+
+// GetNetworkProviderSubnetByNameWithContext calls GetNetworkProviderSubnetByNameFunc
+func (m *SubnetsAPI) GetNetworkProviderSubnetByNameWithContext(ctx context.Context, networkProviderName string) (*ccp.NetworkProviderSubnet, error) {
+	if m.GetNetworkProviderSubnetByNameFunc == nil {
+		var r0 *ccp.NetworkProviderSubnet
+		return r0, notConfigured("SubnetsAPI.GetNetworkProviderSubnetByName")
+	}
+	return m.GetNetworkProviderSubnetByNameFunc(ctx, networkProviderName)
+}
+
+// ACIProfilesAPI is a configurable mock of ccp.ACIProfilesAPI. Each method calls its Func field,
+// or returns ErrNotConfigured if the field is nil
+type ACIProfilesAPI struct {
+	GetACIProfilesFunc      func(context.Context) ([]ccp.ACIProfile, error)
+	ListACIProfilesFunc     func(context.Context) iter.Seq2[ccp.ACIProfile, error]
+	GetACIProfileByNameFunc func(context.Context, string) (*ccp.ACIProfile, error)
+	AddACIProfileFunc       func(context.Context, *ccp.ACIProfile) (*ccp.ACIProfile, error)
+	PatchACIProfileFunc     func(context.Context, *ccp.ACIProfile, string) (*ccp.ACIProfile, error)
+	DeleteACIProfileFunc    func(context.Context, string) error
+}
+
+var _ ccp.ACIProfilesAPI = (*ACIProfilesAPI)(nil)
+
+// GetACIProfiles calls GetACIProfilesWithContext with context.Background()
+func (m *ACIProfilesAPI) GetACIProfiles() ([]ccp.ACIProfile, error) {
+	return m.GetACIProfilesWithContext(context.Background())
+}
+
+// GetACIProfilesWithContext calls GetACIProfilesFunc
+func (m *ACIProfilesAPI) GetACIProfilesWithContext(ctx context.Context) ([]ccp.ACIProfile, error) {
+	if m.GetACIProfilesFunc == nil {
+		var r0 []ccp.ACIProfile
+		return r0, notConfigured("ACIProfilesAPI.GetACIProfiles")
+	}
+	return m.GetACIProfilesFunc(ctx)
+}
+
+// ListACIProfiles calls ListACIProfilesFunc
+func (m *ACIProfilesAPI) ListACIProfiles(ctx context.Context) iter.Seq2[ccp.ACIProfile, error] {
+	if m.ListACIProfilesFunc == nil {
+		return func(yield func(ccp.ACIProfile, error) bool) {
+			var zero ccp.ACIProfile
+			yield(zero, notConfigured("ACIProfilesAPI.ListACIProfiles"))
+		}
+	}
+	return m.ListACIProfilesFunc(ctx)
+}
+
+// GetACIProfileByName calls GetACIProfileByNameWithContext with context.Background()
+func (m *ACIProfilesAPI) GetACIProfileByName(profileName string) (*ccp.ACIProfile, error) {
+	return m.GetACIProfileByNameWithContext(context.Background(), profileName)
+}
+
+// GetACIProfileByNameWithContext calls GetACIProfileByNameFunc
+func (m *ACIProfilesAPI) GetACIProfileByNameWithContext(ctx context.Context, profileName string) (*ccp.ACIProfile, error) {
+	if m.GetACIProfileByNameFunc == nil {
+		var r0 *ccp.ACIProfile
+		return r0, notConfigured("ACIProfilesAPI.GetACIProfileByName")
+	}
+	return m.GetACIProfileByNameFunc(ctx, profileName)
+}
+
+// AddACIProfile calls AddACIProfileWithContext with context.Background()
+func (m *ACIProfilesAPI) AddACIProfile(aciProfile *ccp.ACIProfile) (*ccp.ACIProfile, error) {
+	return m.AddACIProfileWithContext(context.Background(), aciProfile)
+}
+
+// AddACIProfileWithContext calls AddACIProfileFunc
+func (m *ACIProfilesAPI) AddACIProfileWithContext(ctx context.Context, aciProfile *ccp.ACIProfile) (*ccp.ACIProfile, error) {
+	if m.AddACIProfileFunc == nil {
+		var r0 *ccp.ACIProfile
+		return r0, notConfigured("ACIProfilesAPI.AddACIProfile")
+	}
+	return m.AddACIProfileFunc(ctx, aciProfile)
+}
+
+// PatchACIProfile calls PatchACIProfileWithContext with context.Background()
+func (m *ACIProfilesAPI) PatchACIProfile(profile *ccp.ACIProfile, profileUUID string) (*ccp.ACIProfile, error) {
+	return m.PatchACIProfileWithContext(context.Background(), profile, profileUUID)
+}
+
+// PatchACIProfileWithContext calls PatchACIProfileFunc
+func (m *ACIProfilesAPI) PatchACIProfileWithContext(ctx context.Context, profile *ccp.ACIProfile, profileUUID string) (*ccp.ACIProfile, error) {
+	if m.PatchACIProfileFunc == nil {
+		var r0 *ccp.ACIProfile
+		return r0, notConfigured("ACIProfilesAPI.PatchACIProfile")
+	}
+	return m.PatchACIProfileFunc(ctx, profile, profileUUID)
+}
+
+// DeleteACIProfile calls DeleteACIProfileWithContext with context.Background()
+func (m *ACIProfilesAPI) DeleteACIProfile(profileUUID string) error {
+	return m.DeleteACIProfileWithContext(context.Background(), profileUUID)
+}
+
+// DeleteACIProfileWithContext calls DeleteACIProfileFunc
+func (m *ACIProfilesAPI) DeleteACIProfileWithContext(ctx context.Context, profileUUID string) error {
+	if m.DeleteACIProfileFunc == nil {
+		return notConfigured("ACIProfilesAPI.DeleteACIProfile")
+	}
+	return m.DeleteACIProfileFunc(ctx, profileUUID)
+}
+
+// UsersAPI is a configurable mock of ccp.UsersAPI. Each method calls its Func field,
+// or returns ErrNotConfigured if the field is nil
+type UsersAPI struct {
+	GetUsersFunc          func(context.Context) ([]ccp.User, error)
+	ListUsersFunc         func(context.Context) iter.Seq2[ccp.User, error]
+	GetUserFunc           func(context.Context, string) (*ccp.User, error)
+	AddUserFunc           func(context.Context, *ccp.User) (*ccp.User, error)
+	PatchUserFunc         func(context.Context, *ccp.User) (*ccp.User, error)
+	EnableUserFunc        func(context.Context, string) (*ccp.User, error)
+	DisableUserFunc       func(context.Context, string) (*ccp.User, error)
+	ResetUserPasswordFunc func(context.Context, string, string) (*ccp.User, error)
+	DeleteUserFunc        func(context.Context, string) error
+}
+
+var _ ccp.UsersAPI = (*UsersAPI)(nil)
+
+// GetUsers calls GetUsersWithContext with context.Background()
+func (m *UsersAPI) GetUsers() ([]ccp.User, error) {
+	return m.GetUsersWithContext(context.Background())
+}
+
+// GetUsersWithContext calls GetUsersFunc
+func (m *UsersAPI) GetUsersWithContext(ctx context.Context) ([]ccp.User, error) {
+	if m.GetUsersFunc == nil {
+		var r0 []ccp.User
+		return r0, notConfigured("UsersAPI.GetUsers")
+	}
+	return m.GetUsersFunc(ctx)
+}
+
+// ListUsers calls ListUsersFunc
+func (m *UsersAPI) ListUsers(ctx context.Context) iter.Seq2[ccp.User, error] {
+	if m.ListUsersFunc == nil {
+		return func(yield func(ccp.User, error) bool) {
+			var zero ccp.User
+			yield(zero, notConfigured("UsersAPI.ListUsers"))
+		}
+	}
+	return m.ListUsersFunc(ctx)
+}
+
+// GetUser calls GetUserWithContext with context.Background()
+func (m *UsersAPI) GetUser(username string) (*ccp.User, error) {
+	return m.GetUserWithContext(context.Background(), username)
+}
+
+// GetUserWithContext calls GetUserFunc
+func (m *UsersAPI) GetUserWithContext(ctx context.Context, username string) (*ccp.User, error) {
+	if m.GetUserFunc == nil {
+		var r0 *ccp.User
+		return r0, notConfigured("UsersAPI.GetUser")
+	}
+	return m.GetUserFunc(ctx, username)
+}
+
+// AddUser calls AddUserWithContext with context.Background()
+func (m *UsersAPI) AddUser(user *ccp.User) (*ccp.User, error) {
+	return m.AddUserWithContext(context.Background(), user)
+}
+
+// AddUserWithContext calls AddUserFunc
+func (m *UsersAPI) AddUserWithContext(ctx context.Context, user *ccp.User) (*ccp.User, error) {
+	if m.AddUserFunc == nil {
+		var r0 *ccp.User
+		return r0, notConfigured("UsersAPI.AddUser")
+	}
+	return m.AddUserFunc(ctx, user)
+}
+
+// PatchUser calls PatchUserWithContext with context.Background()
+func (m *UsersAPI) PatchUser(user *ccp.User) (*ccp.User, error) {
+	return m.PatchUserWithContext(context.Background(), user)
+}
+
+// PatchUserWithContext calls PatchUserFunc
+func (m *UsersAPI) PatchUserWithContext(ctx context.Context, user *ccp.User) (*ccp.User, error) {
+	if m.PatchUserFunc == nil {
+		var r0 *ccp.User
+		return r0, notConfigured("UsersAPI.PatchUser")
+	}
+	return m.PatchUserFunc(ctx, user)
+}
+
+// EnableUser calls EnableUserWithContext with context.Background()
+func (m *UsersAPI) EnableUser(username string) (*ccp.User, error) {
+	return m.EnableUserWithContext(context.Background(), username)
+}
+
+// EnableUserWithContext calls EnableUserFunc
+func (m *UsersAPI) EnableUserWithContext(ctx context.Context, username string) (*ccp.User, error) {
+	if m.EnableUserFunc == nil {
+		var r0 *ccp.User
+		return r0, notConfigured("UsersAPI.EnableUser")
+	}
+	return m.EnableUserFunc(ctx, username)
+}
+
+// DisableUser calls DisableUserWithContext with context.Background()
+func (m *UsersAPI) DisableUser(username string) (*ccp.User, error) {
+	return m.DisableUserWithContext(context.Background(), username)
+}
+
+// DisableUserWithContext calls DisableUserFunc
+func (m *UsersAPI) DisableUserWithContext(ctx context.Context, username string) (*ccp.User, error) {
+	if m.DisableUserFunc == nil {
+		var r0 *ccp.User
+		return r0, notConfigured("UsersAPI.DisableUser")
+	}
+	return m.DisableUserFunc(ctx, username)
+}
+
+// ResetUserPassword calls ResetUserPasswordWithContext with context.Background()
+func (m *UsersAPI) ResetUserPassword(username, password string) (*ccp.User, error) {
+	return m.ResetUserPasswordWithContext(context.Background(), username, password)
+}
+
+// ResetUserPasswordWithContext calls ResetUserPasswordFunc
+func (m *UsersAPI) ResetUserPasswordWithContext(ctx context.Context, username, password string) (*ccp.User, error) {
+	if m.ResetUserPasswordFunc == nil {
+		var r0 *ccp.User
+		return r0, notConfigured("UsersAPI.ResetUserPassword")
+	}
+	return m.ResetUserPasswordFunc(ctx, username, password)
+}
+
+// DeleteUser calls DeleteUserWithContext with context.Background()
+func (m *UsersAPI) DeleteUser(username string) error {
+	return m.DeleteUserWithContext(context.Background(), username)
+}
+
+// DeleteUserWithContext calls DeleteUserFunc
+func (m *UsersAPI) DeleteUserWithContext(ctx context.Context, username string) error {
+	if m.DeleteUserFunc == nil {
+		return notConfigured("UsersAPI.DeleteUser")
+	}
+	return m.DeleteUserFunc(ctx, username)
+}
+
+// LDAPAPI is a configurable mock of ccp.LDAPAPI. Each method calls its Func field,
+// or returns ErrNotConfigured if the field is nil
+type LDAPAPI struct {
+	GetLDAPSetupFunc    func(context.Context) (*ccp.LDAPSetup, error)
+	SetLDAPSetupFunc    func(context.Context, *ccp.LDAPSetup) (*ccp.LDAPSetup, error)
+	UpdateLDAPSetupFunc func(context.Context, *ccp.LDAPSetup) (*ccp.LDAPSetup, error)
+	DeleteLDAPSetupFunc func(context.Context) error
+	TestLDAPSetupFunc   func(context.Context, *ccp.LDAPSetup) (*ccp.LDAPTestResult, error)
+}
+
+var _ ccp.LDAPAPI = (*LDAPAPI)(nil)
+
+// GetLDAPSetup calls GetLDAPSetupWithContext with context.Background()
+func (m *LDAPAPI) GetLDAPSetup() (*ccp.LDAPSetup, error) {
+	return m.GetLDAPSetupWithContext(context.Background())
+}
+
+// GetLDAPSetupWithContext calls GetLDAPSetupFunc
+func (m *LDAPAPI) GetLDAPSetupWithContext(ctx context.Context) (*ccp.LDAPSetup, error) {
+	if m.GetLDAPSetupFunc == nil {
+		var r0 *ccp.LDAPSetup
+		return r0, notConfigured("LDAPAPI.GetLDAPSetup")
+	}
+	return m.GetLDAPSetupFunc(ctx)
+}
+
+// SetLDAPSetup calls SetLDAPSetupWithContext with context.Background()
+func (m *LDAPAPI) SetLDAPSetup(setup *ccp.LDAPSetup) (*ccp.LDAPSetup, error) {
+	return m.SetLDAPSetupWithContext(context.Background(), setup)
+}
+
+// SetLDAPSetupWithContext calls SetLDAPSetupFunc
+func (m *LDAPAPI) SetLDAPSetupWithContext(ctx context.Context, setup *ccp.LDAPSetup) (*ccp.LDAPSetup, error) {
+	if m.SetLDAPSetupFunc == nil {
+		var r0 *ccp.LDAPSetup
+		return r0, notConfigured("LDAPAPI.SetLDAPSetup")
+	}
+	return m.SetLDAPSetupFunc(ctx, setup)
+}
+
+// UpdateLDAPSetup calls UpdateLDAPSetupWithContext with context.Background()
+func (m *LDAPAPI) UpdateLDAPSetup(setup *ccp.LDAPSetup) (*ccp.LDAPSetup, error) {
+	return m.UpdateLDAPSetupWithContext(context.Background(), setup)
+}
+
+// UpdateLDAPSetupWithContext calls UpdateLDAPSetupFunc
+func (m *LDAPAPI) UpdateLDAPSetupWithContext(ctx context.Context, setup *ccp.LDAPSetup) (*ccp.LDAPSetup, error) {
+	if m.UpdateLDAPSetupFunc == nil {
+		var r0 *ccp.LDAPSetup
+		return r0, notConfigured("LDAPAPI.UpdateLDAPSetup")
+	}
+	return m.UpdateLDAPSetupFunc(ctx, setup)
+}
+
+// DeleteLDAPSetup calls DeleteLDAPSetupWithContext with context.Background()
+func (m *LDAPAPI) DeleteLDAPSetup() error {
+	return m.DeleteLDAPSetupWithContext(context.Background())
+}
+
+// DeleteLDAPSetupWithContext calls DeleteLDAPSetupFunc
+func (m *LDAPAPI) DeleteLDAPSetupWithContext(ctx context.Context) error {
+	if m.DeleteLDAPSetupFunc == nil {
+		return notConfigured("LDAPAPI.DeleteLDAPSetup")
+	}
+	return m.DeleteLDAPSetupFunc(ctx)
+}
+
+// TestLDAPSetup calls TestLDAPSetupWithContext with context.Background()
+func (m *LDAPAPI) TestLDAPSetup(setup *ccp.LDAPSetup) (*ccp.LDAPTestResult, error) {
+	return m.TestLDAPSetupWithContext(context.Background(), setup)
+}
+
+// TestLDAPSetupWithContext calls TestLDAPSetupFunc
+func (m *LDAPAPI) TestLDAPSetupWithContext(ctx context.Context, setup *ccp.LDAPSetup) (*ccp.LDAPTestResult, error) {
+	if m.TestLDAPSetupFunc == nil {
+		var r0 *ccp.LDAPTestResult
+		return r0, notConfigured("LDAPAPI.TestLDAPSetup")
+	}
+	return m.TestLDAPSetupFunc(ctx, setup)
+}
+
+// RBACAPI is a configurable mock of ccp.RBACAPI. Each method calls its Func field,
+// or returns ErrNotConfigured if the field is nil
+type RBACAPI struct {
+	GetRolesFunc            func(context.Context) ([]ccp.Role, error)
+	ListRolesFunc           func(context.Context) iter.Seq2[ccp.Role, error]
+	GetRoleFunc             func(context.Context, string) (*ccp.Role, error)
+	GetClusterAuthzFunc     func(context.Context, string) (*ccp.ClusterAuthz, error)
+	GrantClusterAccessFunc  func(context.Context, string, string, string) (*ccp.ClusterAuthz, error)
+	RevokeClusterAccessFunc func(context.Context, string, string, string) error
+}
+
+var _ ccp.RBACAPI = (*RBACAPI)(nil)
+
+// GetRoles calls GetRolesWithContext with context.Background()
+func (m *RBACAPI) GetRoles() ([]ccp.Role, error) {
+	return m.GetRolesWithContext(context.Background())
+}
+
+// GetRolesWithContext calls GetRolesFunc
+func (m *RBACAPI) GetRolesWithContext(ctx context.Context) ([]ccp.Role, error) {
+	if m.GetRolesFunc == nil {
+		var r0 []ccp.Role
+		return r0, notConfigured("RBACAPI.GetRoles")
+	}
+	return m.GetRolesFunc(ctx)
+}
+
+// ListRoles calls ListRolesFunc
+func (m *RBACAPI) ListRoles(ctx context.Context) iter.Seq2[ccp.Role, error] {
+	if m.ListRolesFunc == nil {
+		return func(yield func(ccp.Role, error) bool) {
+			var zero ccp.Role
+			yield(zero, notConfigured("RBACAPI.ListRoles"))
+		}
+	}
+	return m.ListRolesFunc(ctx)
+}
+
+// GetRole calls GetRoleWithContext with context.Background()
+func (m *RBACAPI) GetRole(role string) (*ccp.Role, error) {
+	return m.GetRoleWithContext(context.Background(), role)
+}
+
+// GetRoleWithContext calls GetRoleFunc
+func (m *RBACAPI) GetRoleWithContext(ctx context.Context, role string) (*ccp.Role, error) {
+	if m.GetRoleFunc == nil {
+		var r0 *ccp.Role
+		return r0, notConfigured("RBACAPI.GetRole")
+	}
+	return m.GetRoleFunc(ctx, role)
+}
+
+// GetClusterAuthz calls GetClusterAuthzWithContext with context.Background()
+func (m *RBACAPI) GetClusterAuthz(clusterUUID string) (*ccp.ClusterAuthz, error) {
+	return m.GetClusterAuthzWithContext(context.Background(), clusterUUID)
+}
+
+// GetClusterAuthzWithContext calls GetClusterAuthzFunc
+func (m *RBACAPI) GetClusterAuthzWithContext(ctx context.Context, clusterUUID string) (*ccp.ClusterAuthz, error) {
+	if m.GetClusterAuthzFunc == nil {
+		var r0 *ccp.ClusterAuthz
+		return r0, notConfigured("RBACAPI.GetClusterAuthz")
+	}
+	return m.GetClusterAuthzFunc(ctx, clusterUUID)
+}
+
+// GrantClusterAccess calls GrantClusterAccessWithContext with context.Background()
+func (m *RBACAPI) GrantClusterAccess(clusterUUID, authType, name string) (*ccp.ClusterAuthz, error) {
+	return m.GrantClusterAccessWithContext(context.Background(), clusterUUID, authType, name)
+}
+
+// GrantClusterAccessWithContext calls GrantClusterAccessFunc
+func (m *RBACAPI) GrantClusterAccessWithContext(ctx context.Context, clusterUUID, authType, name string) (*ccp.ClusterAuthz, error) {
+	if m.GrantClusterAccessFunc == nil {
+		var r0 *ccp.ClusterAuthz
+		return r0, notConfigured("RBACAPI.GrantClusterAccess")
+	}
+	return m.GrantClusterAccessFunc(ctx, clusterUUID, authType, name)
+}
+
+// RevokeClusterAccess calls RevokeClusterAccessWithContext with context.Background()
+func (m *RBACAPI) RevokeClusterAccess(clusterUUID, authType, name string) error {
+	return m.RevokeClusterAccessWithContext(context.Background(), clusterUUID, authType, name)
+}
+
+// RevokeClusterAccessWithContext calls RevokeClusterAccessFunc
+func (m *RBACAPI) RevokeClusterAccessWithContext(ctx context.Context, clusterUUID, authType, name string) error {
+	if m.RevokeClusterAccessFunc == nil {
+		return notConfigured("RBACAPI.RevokeClusterAccess")
+	}
+	return m.RevokeClusterAccessFunc(ctx, clusterUUID, authType, name)
+}
+
+// SystemAPI is a configurable mock of ccp.SystemAPI. Each method calls its Func field,
+// or returns ErrNotConfigured if the field is nil
+type SystemAPI struct {
+	LoginFunc             func(context.Context, *ccp.Client) error
+	LogoutFunc            func(context.Context) error
+	WhoAmIFunc            func(context.Context) (*ccp.Session, error)
+	ValidateSessionFunc   func(context.Context) (*ccp.Session, error)
+	ResumeSessionFunc     func(context.Context) (bool, error)
+	GetLivenessHealthFunc func(context.Context) (*ccp.LivenessHealth, error)
+	GetHealthFunc         func(context.Context) (*ccp.Health, error)
+	GetVersionFunc        func(context.Context) (*ccp.Version, error)
+	CapabilitiesFunc      func(context.Context) (*ccp.Capabilities, error)
+	TokenFunc             func() string
+	SetTokenFunc          func(string)
+}
+
+var _ ccp.SystemAPI = (*SystemAPI)(nil)
+
+// Login calls LoginWithContext with context.Background()
+func (m *SystemAPI) Login(client *ccp.Client) error {
+	return m.LoginWithContext(context.Background(), client)
+}
+
+// LoginWithContext calls LoginFunc
+func (m *SystemAPI) LoginWithContext(ctx context.Context, client *ccp.Client) error {
+	if m.LoginFunc == nil {
+		return notConfigured("SystemAPI.Login")
+	}
+	return m.LoginFunc(ctx, client)
+}
+
+// Logout calls LogoutWithContext with context.Background()
+func (m *SystemAPI) Logout() error {
+	return m.LogoutWithContext(context.Background())
+}
+
+// LogoutWithContext calls LogoutFunc
+func (m *SystemAPI) LogoutWithContext(ctx context.Context) error {
+	if m.LogoutFunc == nil {
+		return notConfigured("SystemAPI.Logout")
+	}
+	return m.LogoutFunc(ctx)
+}
+
+// WhoAmI calls WhoAmIWithContext with context.Background()
+func (m *SystemAPI) WhoAmI() (*ccp.Session, error) {
+	return m.WhoAmIWithContext(context.Background())
+}
+
+// WhoAmIWithContext calls WhoAmIFunc
+func (m *SystemAPI) WhoAmIWithContext(ctx context.Context) (*ccp.Session, error) {
+	if m.WhoAmIFunc == nil {
+		var r0 *ccp.Session
+		return r0, notConfigured("SystemAPI.WhoAmI")
+	}
+	return m.WhoAmIFunc(ctx)
+}
+
+// ValidateSession calls ValidateSessionWithContext with context.Background()
+func (m *SystemAPI) ValidateSession() (*ccp.Session, error) {
+	return m.ValidateSessionWithContext(context.Background())
+}
+
+// ValidateSessionWithContext calls ValidateSessionFunc
+func (m *SystemAPI) ValidateSessionWithContext(ctx context.Context) (*ccp.Session, error) {
+	if m.ValidateSessionFunc == nil {
+		var r0 *ccp.Session
+		return r0, notConfigured("SystemAPI.ValidateSession")
+	}
+	return m.ValidateSessionFunc(ctx)
+}
+
+// ResumeSession calls ResumeSessionWithContext with context.Background()
+func (m *SystemAPI) ResumeSession() (bool, error) {
+	return m.ResumeSessionWithContext(context.Background())
+}
+
+// ResumeSessionWithContext calls ResumeSessionFunc
+func (m *SystemAPI) ResumeSessionWithContext(ctx context.Context) (bool, error) {
+	if m.ResumeSessionFunc == nil {
+		var r0 bool
+		return r0, notConfigured("SystemAPI.ResumeSession")
+	}
+	return m.ResumeSessionFunc(ctx)
+}
+
+// GetLivenessHealth calls GetLivenessHealthWithContext with context.Background()
+func (m *SystemAPI) GetLivenessHealth() (*ccp.LivenessHealth, error) {
+	return m.GetLivenessHealthWithContext(context.Background())
+}
+
+// GetLivenessHealthWithContext calls GetLivenessHealthFunc
+func (m *SystemAPI) GetLivenessHealthWithContext(ctx context.Context) (*ccp.LivenessHealth, error) {
+	if m.GetLivenessHealthFunc == nil {
+		var r0 *ccp.LivenessHealth
+		return r0, notConfigured("SystemAPI.GetLivenessHealth")
+	}
+	return m.GetLivenessHealthFunc(ctx)
+}
+
+// GetHealth calls GetHealthWithContext with context.Background()
+func (m *SystemAPI) GetHealth() (*ccp.Health, error) {
+	return m.GetHealthWithContext(context.Background())
+}
+
+// GetHealthWithContext calls GetHealthFunc
+func (m *SystemAPI) GetHealthWithContext(ctx context.Context) (*ccp.Health, error) {
+	if m.GetHealthFunc == nil {
+		var r0 *ccp.Health
+		return r0, notConfigured("SystemAPI.GetHealth")
+	}
+	return m.GetHealthFunc(ctx)
+}
+
+// GetVersion calls GetVersionWithContext with context.Background()
+func (m *SystemAPI) GetVersion() (*ccp.Version, error) {
+	return m.GetVersionWithContext(context.Background())
+}
+
+// GetVersionWithContext calls GetVersionFunc
+func (m *SystemAPI) GetVersionWithContext(ctx context.Context) (*ccp.Version, error) {
+	if m.GetVersionFunc == nil {
+		var r0 *ccp.Version
+		return r0, notConfigured("SystemAPI.GetVersion")
+	}
+	return m.GetVersionFunc(ctx)
+}
+
+// Capabilities calls CapabilitiesFunc
+func (m *SystemAPI) Capabilities(ctx context.Context) (*ccp.Capabilities, error) {
+	if m.CapabilitiesFunc == nil {
+		var r0 *ccp.Capabilities
+		return r0, notConfigured("SystemAPI.Capabilities")
+	}
+	return m.CapabilitiesFunc(ctx)
+}
+
+// Token calls TokenFunc
+func (m *SystemAPI) Token() string {
+	if m.TokenFunc == nil {
+		var r0 string
+		return r0
+	}
+	return m.TokenFunc()
+}
+
+// SetToken calls SetTokenFunc
+func (m *SystemAPI) SetToken(token string) {
+	if m.SetTokenFunc == nil {
+		return
+	}
+	m.SetTokenFunc(token)
+}
+
+// Mock implements the whole ccp.API. Set the Func fields of the embedded mocks
+type Mock struct {
+	ClustersAPI
+	AddonsAPI
+	ProvidersAPI
+	SubnetsAPI
+	ACIProfilesAPI
+	UsersAPI
+	LDAPAPI
+	RBACAPI
+	SystemAPI
+}
+
+var _ ccp.API = (*Mock)(nil)
@@ -0,0 +1,112 @@
+/*Copyright (c) 2019 Cisco and/or its affiliates.
+
+This software is licensed to you under the terms of the Cisco Sample
+Code License, Version 1.0 (the "License"). You may obtain a copy of the
+License at
+
+               https://developer.cisco.com/docs/licenses
+
+All use of the material herein must be in accordance with the terms of
+the License. All rights not expressly granted by the License are
+reserved. Unless required by applicable law or agreed to separately in
+writing, software distributed under the License is distributed on an "AS
+IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+or implied.*/
+
+// Package ccpprom keeps Prometheus metrics for ccp clients. It lives apart from
+// the ccp package so only programs that want Prometheus depend on it.
+//
+//	metrics, err := ccpprom.NewMetrics(prometheus.DefaultRegisterer)
+//	client, err := ccp.NewClient(baseURL, ccp.WithMetrics(metrics))
+package ccpprom
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/rob-moss/ccp-clientlibrary-go/ccp"
+)
+
+// Metrics holds the Prometheus metrics for one or more clients, given to each with ccp.WithMetrics.
+// Every HTTP request is counted, Login included, labelled with the API operation, the
+// HTTP method and the status class: 2xx, 4xx, 5xx, or "error" when no response came back
+//
+//	ccp_client_requests_total{operation, method, status_class}
+//	ccp_client_request_errors_total{operation, method, status_class}
+//	ccp_client_request_duration_seconds{operation, method}
+//	ccp_client_token_refreshes_total
+//	ccp_client_polls_total{operation}
+type Metrics struct {
+	requests       *prometheus.CounterVec
+	errors         *prometheus.CounterVec
+	duration       *prometheus.HistogramVec
+	tokenRefreshes prometheus.Counter
+	polls          *prometheus.CounterVec
+}
+
+var _ ccp.MetricsRecorder = (*Metrics)(nil)
+
+// NewMetrics creates the client metrics and registers them with reg,
+// prometheus.DefaultRegisterer if reg is nil
+func NewMetrics(reg prometheus.Registerer) (*Metrics, error) {
+	if reg == nil {
+		reg = prometheus.DefaultRegisterer
+	}
+
+	m := &Metrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "ccp_client_requests_total",
+			Help: "HTTP requests sent to the CCP API.",
+		}, []string{"operation", "method", "status_class"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "ccp_client_request_errors_total",
+			Help: "HTTP requests to the CCP API that failed or got a 4xx or 5xx response.",
+		}, []string{"operation", "method", "status_class"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "ccp_client_request_duration_seconds",
+			Help:    "Time taken by HTTP requests to the CCP API.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"operation", "method"}),
+		tokenRefreshes: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "ccp_client_token_refreshes_total",
+			Help: "Logins made because the X-Auth-Token expired.",
+		}),
+		polls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "ccp_client_polls_total",
+			Help: "Status polls made while waiting on the CCP API.",
+		}, []string{"operation"}),
+	}
+
+	for _, c := range []prometheus.Collector{m.requests, m.errors, m.duration, m.tokenRefreshes, m.polls} {
+		if err := reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// ObserveRequest counts one HTTP request. status is 0 when err is set
+func (m *Metrics) ObserveRequest(operation, method string, status int, err error, took time.Duration) {
+	class := "error"
+	if err == nil {
+		class = strconv.Itoa(status/100) + "xx"
+	}
+
+	m.requests.WithLabelValues(operation, method, class).Inc()
+	m.duration.WithLabelValues(operation, method).Observe(took.Seconds())
+	if err != nil || status >= 400 {
+		m.errors.WithLabelValues(operation, method, class).Inc()
+	}
+}
+
+// ObserveTokenRefresh counts a login made because the token expired
+func (m *Metrics) ObserveTokenRefresh() {
+	m.tokenRefreshes.Inc()
+}
+
+// ObservePoll counts one status poll made by operation
+func (m *Metrics) ObservePoll(operation string) {
+	m.polls.WithLabelValues(operation).Inc()
+}
@@ -0,0 +1,111 @@
+/*Copyright (c) 2019 Cisco and/or its affiliates.
+
+This software is licensed to you under the terms of the Cisco Sample
+Code License, Version 1.0 (the "License"). You may obtain a copy of the
+License at
+
+               https://developer.cisco.com/docs/licenses
+
+All use of the material herein must be in accordance with the terms of
+the License. All rights not expressly granted by the License are
+reserved. Unless required by applicable law or agreed to separately in
+writing, software distributed under the License is distributed on an "AS
+IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+or implied.*/
+
+package ccpprom_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/rob-moss/ccp-clientlibrary-go/ccp"
+	"github.com/rob-moss/ccp-clientlibrary-go/ccp/ccpprom"
+	"github.com/rob-moss/ccp-clientlibrary-go/ccp/ccptest"
+)
+
+func TestMetrics(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+
+	reg := prometheus.NewPedanticRegistry()
+	metrics, err := ccpprom.NewMetrics(reg)
+	if err != nil {
+		t.Fatalf("NewMetrics: %v", err)
+	}
+	client, err := ccp.NewClient(srv.URL,
+		ccp.WithCredentials(ccptest.Username, ccptest.Password),
+		ccp.WithMetrics(metrics),
+		ccp.WithRetryPolicy(nil),
+	)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if err := client.Login(client); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+
+	if _, err := client.GetClusters(); err != nil {
+		t.Fatalf("GetClusters: %v", err)
+	}
+	srv.AddFault(ccptest.Fault{Method: http.MethodGet, Path: "/v3/clusters", Status: http.StatusServiceUnavailable, Times: 1})
+	if _, err := client.GetClusters(); err == nil {
+		t.Fatal("GetClusters succeeded, want the 503")
+	}
+	srv.ExpireTokens()
+	if _, err := client.GetClusters(); err != nil {
+		t.Fatalf("GetClusters after the token expired: %v", err)
+	}
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Gather: %v", err)
+	}
+
+	for _, c := range []struct {
+		name   string
+		labels map[string]string
+		want   float64
+	}{
+		{"ccp_client_requests_total", map[string]string{"operation": "GetClusters", "method": "GET", "status_class": "2xx"}, 2},
+		{"ccp_client_requests_total", map[string]string{"operation": "GetClusters", "method": "GET", "status_class": "4xx"}, 1},
+		{"ccp_client_requests_total", map[string]string{"operation": "GetClusters", "method": "GET", "status_class": "5xx"}, 1},
+		{"ccp_client_request_errors_total", map[string]string{"operation": "GetClusters", "method": "GET", "status_class": "5xx"}, 1},
+		{"ccp_client_request_errors_total", map[string]string{"operation": "GetClusters", "method": "GET", "status_class": "2xx"}, 0},
+		{"ccp_client_request_duration_seconds", map[string]string{"operation": "GetClusters", "method": "GET"}, 4},
+		{"ccp_client_token_refreshes_total", nil, 1},
+	} {
+		if got := value(families, c.name, c.labels); got != c.want {
+			t.Errorf("%s%v = %v, want %v", c.name, c.labels, got, c.want)
+		}
+	}
+}
+
+// value returns the counter value, or the histogram sample count, of the series with
+// exactly labels, 0 if there is none
+func value(families []*dto.MetricFamily, name string, labels map[string]string) float64 {
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+	series:
+		for _, m := range family.GetMetric() {
+			if len(m.GetLabel()) != len(labels) {
+				continue
+			}
+			for _, l := range m.GetLabel() {
+				if labels[l.GetName()] != l.GetValue() {
+					continue series
+				}
+			}
+			if h := m.GetHistogram(); h != nil {
+				return float64(h.GetSampleCount())
+			}
+			return m.GetCounter().GetValue()
+		}
+	}
+	return 0
+}
@@ -0,0 +1,389 @@
+/*Copyright (c) 2019 Cisco and/or its affiliates.
+
+This software is licensed to you under the terms of the Cisco Sample
+Code License, Version 1.0 (the "License"). You may obtain a copy of the
+License at
+
+               https://developer.cisco.com/docs/licenses
+
+All use of the material herein must be in accordance with the terms of
+the License. All rights not expressly granted by the License are
+reserved. Unless required by applicable law or agreed to separately in
+writing, software distributed under the License is distributed on an "AS
+IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+or implied.*/
+
+package ccptest
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/rob-moss/ccp-clientlibrary-go/ccp"
+)
+
+// cluster is a stored cluster with its lifecycle timers and installed addons
+type cluster struct {
+	ccp.Cluster
+	readyAt time.Time // when CREATING turns READY
+	goneAt  time.Time // when a DELETING cluster disappears, zero until deleted
+	addons  []addon
+	authz   []ccp.AuthListEntry
+}
+
+// addon is an installed addon as it was posted
+type addon struct {
+	Name        string `json:"name"`
+	Namespace   string `json:"namespace"`
+	DisplayName string `json:"displayName"`
+	Description string `json:"description"`
+}
+
+// handle registers h for the path with and without a trailing slash, the client uses both
+func handle(mux *http.ServeMux, method, path string, h http.HandlerFunc) {
+	mux.HandleFunc(method+" "+path, h)
+	mux.HandleFunc(method+" "+path+"/{$}", h)
+}
+
+func (s *Server) clusterRoutes(mux *http.ServeMux) {
+	handle(mux, "GET", "/v3/clusters", s.listClusters)
+	handle(mux, "POST", "/v3/clusters", s.createCluster)
+	handle(mux, "GET", "/v3/clusters/{id}", s.getCluster)
+	handle(mux, "PATCH", "/v3/clusters/{id}", s.patchCluster)
+	handle(mux, "DELETE", "/v3/clusters/{id}", s.deleteCluster)
+	handle(mux, "PATCH", "/v3/clusters/{id}/node-pools/{pool}", s.scaleNodePool)
+	handle(mux, "GET", "/v3/clusters/{id}/catalog", s.getCatalog)
+	handle(mux, "GET", "/v3/clusters/{id}/addons", s.listAddons)
+	handle(mux, "POST", "/v3/clusters/{id}/addons", s.installAddon)
+	handle(mux, "DELETE", "/v3/clusters/{id}/addons/{name}", s.deleteAddon)
+}
+
+// tick moves clusters along their lifecycle. s.mu must be held
+func (s *Server) tick() {
+	now := time.Now()
+	kept := s.clusters[:0]
+	for _, c := range s.clusters {
+		if !c.goneAt.IsZero() && !now.Before(c.goneAt) {
+			continue
+		}
+		if c.Status != nil && *c.Status == "CREATING" && !now.Before(c.readyAt) {
+			c.Status = ccp.String("READY")
+		}
+		kept = append(kept, c)
+	}
+	s.clusters = kept
+}
+
+// findCluster returns the cluster with the given UUID, or nil. s.mu must be held
+func (s *Server) findCluster(uuid string) *cluster {
+	s.tick()
+	for _, c := range s.clusters {
+		if c.UUID != nil && *c.UUID == uuid {
+			return c
+		}
+	}
+	return nil
+}
+
+// AddCluster stores c as a READY cluster, giving it a UUID if it has none, and returns the stored copy
+func (s *Server) AddCluster(c ccp.Cluster) ccp.Cluster {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c = clone(c)
+	if c.UUID == nil {
+		c.UUID = ccp.String(newID())
+	}
+	c.Status = ccp.String("READY")
+	s.clusters = append(s.clusters, &cluster{Cluster: c})
+	return clone(c)
+}
+
+// NewCluster returns a cluster called name that passes the checks the client makes in
+// AddCluster and AddClusterSynchronous, for tests that create clusters through the API
+func NewCluster(name string) *ccp.Cluster {
+	return &ccp.Cluster{
+		Name:               ccp.String(name),
+		KubernetesVersion:  ccp.String("1.16.3"),
+		IPAllocationMethod: ccp.String("dhcp"),
+		Infra: &ccp.Infra{
+			Datacenter: ccp.String("dc"),
+			Datastore:  ccp.String("ds"),
+			Cluster:    ccp.String("vsphere"),
+			Networks:   &[]string{"vm-network"},
+		},
+		MasterNodePool: &ccp.MasterNodePool{
+			Template: ccp.String("ccp-tenant-image"),
+			VCPUs:    ccp.Int64(2),
+			Memory:   ccp.Int64(16384),
+			Nodes:    &[]ccp.Node{},
+		},
+		WorkerNodePool: &[]ccp.WorkerNodePool{{
+			Name:     ccp.String("workers"),
+			Size:     ccp.Int64(1),
+			Template: ccp.String("ccp-tenant-image"),
+			VCPUs:    ccp.Int64(2),
+			Memory:   ccp.Int64(16384),
+			Nodes:    &[]ccp.Node{},
+		}},
+		NetworkPlugin:      &ccp.NetworkPlugin{Name: ccp.String("calico")},
+		NTPPools:           &[]string{},
+		NTPServers:         &[]string{},
+		DockerNoProxy:      &[]string{},
+		RegistriesRootCA:   &[]string{},
+		RegistriesInsecure: &[]string{},
+	}
+}
+
+// Cluster returns a copy of the stored cluster with the given UUID
+func (s *Server) Cluster(uuid string) (ccp.Cluster, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := s.findCluster(uuid)
+	if c == nil {
+		return ccp.Cluster{}, false
+	}
+	return clone(c.Cluster), true
+}
+
+// SetClusterStatus forces the status of a cluster, for example to ERROR
+func (s *Server) SetClusterStatus(uuid, status string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := s.findCluster(uuid)
+	if c == nil {
+		return false
+	}
+	c.Status = ccp.String(status)
+	return true
+}
+
+// InstalledAddons returns the names of the addons installed on a cluster
+func (s *Server) InstalledAddons(uuid string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := s.findCluster(uuid)
+	if c == nil {
+		return nil
+	}
+	var names []string
+	for _, a := range c.addons {
+		names = append(names, a.Name)
+	}
+	return names
+}
+
+func (s *Server) listClusters(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tick()
+	list := []ccp.Cluster{}
+	for _, c := range s.clusters {
+		list = append(list, c.Cluster)
+	}
+	writeList(w, r, list)
+}
+
+func (s *Server) createCluster(w http.ResponseWriter, r *http.Request) {
+	var c ccp.Cluster
+	if !decode(w, r, &c) {
+		return
+	}
+	if c.Name == nil || *c.Name == "" {
+		writeError(w, http.StatusBadRequest, "name: This field is required.")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tick()
+	for _, existing := range s.clusters {
+		if *existing.Name == *c.Name {
+			writeError(w, http.StatusConflict, "Cluster "+*c.Name+" already exists")
+			return
+		}
+	}
+
+	c.UUID = ccp.String(newID())
+	c.Status = ccp.String("CREATING")
+	s.clusters = append(s.clusters, &cluster{Cluster: c, readyAt: time.Now().Add(s.CreateDelay)})
+	writeJSON(w, http.StatusCreated, c)
+}
+
+func (s *Server) getCluster(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := s.findCluster(r.PathValue("id"))
+	if c == nil {
+		writeError(w, http.StatusNotFound, "Not found.")
+		return
+	}
+	writeJSON(w, http.StatusOK, c.Cluster)
+}
+
+func (s *Server) patchCluster(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := s.findCluster(r.PathValue("id"))
+	if c == nil {
+		writeError(w, http.StatusNotFound, "Not found.")
+		return
+	}
+
+	// decode over a copy so only the fields sent change, and the UUID stays put
+	patched := clone(c.Cluster)
+	if !decode(w, r, &patched) {
+		return
+	}
+	patched.UUID = c.UUID
+	patched.Status = c.Status
+	c.Cluster = patched
+	writeJSON(w, http.StatusOK, c.Cluster)
+}
+
+func (s *Server) deleteCluster(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := s.findCluster(r.PathValue("id"))
+	if c == nil {
+		writeError(w, http.StatusNotFound, "Not found.")
+		return
+	}
+	if c.goneAt.IsZero() {
+		c.Status = ccp.String("DELETING")
+		c.goneAt = time.Now().Add(s.DeleteDelay)
+	}
+	s.tick()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) scaleNodePool(w http.ResponseWriter, r *http.Request) {
+	var scale ccp.ScaleCluster
+	if !decode(w, r, &scale) {
+		return
+	}
+	if scale.Size == nil {
+		writeError(w, http.StatusBadRequest, "size: This field is required.")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := s.findCluster(r.PathValue("id"))
+	if c == nil {
+		writeError(w, http.StatusNotFound, "Not found.")
+		return
+	}
+	if c.WorkerNodePool != nil {
+		for i, pool := range *c.WorkerNodePool {
+			if pool.Name != nil && *pool.Name == r.PathValue("pool") {
+				(*c.WorkerNodePool)[i].Size = ccp.Int64(int64(*scale.Size))
+				writeJSON(w, http.StatusOK, c.Cluster)
+				return
+			}
+		}
+	}
+	writeError(w, http.StatusNotFound, "Node pool "+r.PathValue("pool")+" not found.")
+}
+
+func (s *Server) getCatalog(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.findCluster(r.PathValue("id")) == nil {
+		writeError(w, http.StatusNotFound, "Not found.")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(catalog))
+}
+
+func (s *Server) listAddons(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := s.findCluster(r.PathValue("id"))
+	if c == nil {
+		writeError(w, http.StatusNotFound, "Not found.")
+		return
+	}
+
+	type status struct {
+		Name       string `json:"name"`
+		HelmStatus string `json:"helmStatus"`
+		Status     string `json:"status"`
+	}
+	type result struct {
+		addon
+		Status status `json:"status"`
+	}
+	results := []result{}
+	for _, a := range c.addons {
+		results = append(results, result{addon: a, Status: status{Name: a.Name, HelmStatus: "DEPLOYED", Status: "INSTALLED"}})
+	}
+	writePage(w, r, results)
+}
+
+func (s *Server) installAddon(w http.ResponseWriter, r *http.Request) {
+	var a addon
+	if !decode(w, r, &a) {
+		return
+	}
+	if a.Name == "" {
+		writeError(w, http.StatusBadRequest, "name: This field is required.")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := s.findCluster(r.PathValue("id"))
+	if c == nil {
+		writeError(w, http.StatusNotFound, "Not found.")
+		return
+	}
+	for _, installed := range c.addons {
+		if installed.Name == a.Name {
+			writeError(w, http.StatusConflict, "Add-on "+a.Name+" is already installed")
+			return
+		}
+	}
+	c.addons = append(c.addons, a)
+	writeJSON(w, http.StatusCreated, a)
+}
+
+func (s *Server) deleteAddon(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := s.findCluster(r.PathValue("id"))
+	if c == nil {
+		writeError(w, http.StatusNotFound, "Not found.")
+		return
+	}
+	for i, a := range c.addons {
+		if a.Name == r.PathValue("name") {
+			c.addons = append(c.addons[:i:i], c.addons[i+1:]...)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "Add-on "+r.PathValue("name")+" is not installed")
+}
+
+// clone deep copies v through JSON so callers can't reach the server's state
+func clone[T any](v T) T {
+	var out T
+	j, _ := json.Marshal(v)
+	json.Unmarshal(j, &out)
+	return out
+}
+
+// catalog is the addon catalog of a CCP 6.x control plane
+const catalog = `{
+  "_ccp-monitor": {"displayName": "Monitoring", "name": "ccp-monitor", "namespace": "ccp", "description": "Prometheus and Grafana", "url": "/opt/ccp/charts/ccp-monitor.tgz"},
+  "_ccp-efk": {"displayName": "Logging", "name": "ccp-efk", "namespace": "ccp", "description": "Elasticsearch, Fluentd and Kibana", "url": "/opt/ccp/charts/ccp-efk.tgz"},
+  "_ccp-kubernetes-dashboard": {"displayName": "Kubernetes Dashboard", "name": "kubernetes-dashboard", "namespace": "ccp", "description": "Kubernetes Dashboard", "url": "/opt/ccp/charts/kubernetes-dashboard.tgz", "overrideFiles": []},
+  "_ccp-istio-operator": {"displayName": "Istio Operator", "name": "ccp-istio-operator", "namespace": "ccp", "description": "Istio Operator", "url": "/opt/ccp/charts/ccp-istio-operator.tgz", "conflicts": ["ccp-kubeflow", "ccp-harbor-operator"],
+    "dependencies": {"_ccp-istio": {"displayName": "Istio", "name": "ccp-istio-cr", "namespace": "ccp", "description": "Istio (REQUIRES ISTIO OPERATOR)", "url": "/opt/ccp/charts/ccp-istio-cr.tgz"}}},
+  "_ccp-harbor-operator": {"displayName": "Harbor Operator", "name": "ccp-harbor-operator", "namespace": "ccp", "description": "Harbor Operator", "url": "/opt/ccp/charts/ccp-harbor-operator.tgz", "conflicts": ["ccp-istio-operator"],
+    "dependencies": {"_ccp-harbor": {"displayName": "Harbor", "name": "ccp-harbor-cr", "namespace": "ccp", "description": "Harbor (REQUIRES HARBOR OPERATOR)", "url": "/opt/ccp/charts/ccp-harbor-cr.tgz"}}},
+  "_ccp-kubeflow": {"name": "ccp-kubeflow", "namespace": "ccp", "displayName": "Kubeflow", "description": "Kubeflow", "url": "/opt/ccp/charts/ccp-kubeflow.tgz", "conflicts": ["ccp-istio-operator"], "overrides": ""},
+  "_ccp-hxcsi": {"name": "ccp-hxcsi", "displayName": "HyperFlex CSI", "description": "HyperFlex CSI", "url": "/opt/ccp/charts/ccp-hxcsi.tgz", "overrides": ""}
+}`
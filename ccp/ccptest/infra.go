@@ -0,0 +1,151 @@
+/*Copyright (c) 2019 Cisco and/or its affiliates.
+
+This software is licensed to you under the terms of the Cisco Sample
+Code License, Version 1.0 (the "License"). You may obtain a copy of the
+License at
+
+               https://developer.cisco.com/docs/licenses
+
+All use of the material herein must be in accordance with the terms of
+the License. All rights not expressly granted by the License are
+reserved. Unless required by applicable law or agreed to separately in
+writing, software distributed under the License is distributed on an "AS
+IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+or implied.*/
+
+package ccptest
+
+import (
+	"net/http"
+
+	"github.com/rob-moss/ccp-clientlibrary-go/ccp"
+)
+
+type providerConfig = ccp.ProviderClientConfig
+type aciProfile = ccp.ACIProfile
+type subnet = ccp.NetworkProviderSubnet
+
+func (s *Server) infraRoutes(mux *http.ServeMux) {
+	handle(mux, "GET", "/v3/providers", s.listProviders)
+	handle(mux, "GET", "/v3/providers/{id}", s.getProvider)
+	handle(mux, "GET", "/v3/aci-profiles", s.listACIProfiles)
+	handle(mux, "POST", "/v3/aci-profiles", s.createACIProfile)
+	handle(mux, "PATCH", "/v3/aci-profiles/{id}", s.patchACIProfile)
+	handle(mux, "DELETE", "/v3/aci-profiles/{id}", s.deleteACIProfile)
+	handle(mux, "GET", "/2/network_service/subnets", s.listSubnets)
+}
+
+// AddProvider stores an infrastructure provider, giving it a UUID if it has none
+func (s *Server) AddProvider(p ccp.ProviderClientConfig) ccp.ProviderClientConfig {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p = clone(p)
+	if p.UUID == nil {
+		p.UUID = ccp.String(newID())
+	}
+	s.providers = append(s.providers, p)
+	return clone(p)
+}
+
+// AddACIProfile stores an ACI profile, giving it a UUID if it has none
+func (s *Server) AddACIProfile(p ccp.ACIProfile) ccp.ACIProfile {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p = clone(p)
+	if p.UUID == nil {
+		p.UUID = ccp.String(newID())
+	}
+	s.aciProfiles = append(s.aciProfiles, p)
+	return clone(p)
+}
+
+// AddSubnet stores a network provider subnet, giving it a UUID if it has none
+func (s *Server) AddSubnet(n ccp.NetworkProviderSubnet) ccp.NetworkProviderSubnet {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n = clone(n)
+	if n.UUID == nil {
+		n.UUID = ccp.String(newID())
+	}
+	s.subnets = append(s.subnets, n)
+	return clone(n)
+}
+
+func (s *Server) listProviders(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeList(w, r, append([]providerConfig{}, s.providers...))
+}
+
+func (s *Server) getProvider(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, p := range s.providers {
+		if *p.UUID == r.PathValue("id") {
+			writeJSON(w, http.StatusOK, p)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "Not found.")
+}
+
+func (s *Server) listACIProfiles(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeList(w, r, append([]aciProfile{}, s.aciProfiles...))
+}
+
+func (s *Server) createACIProfile(w http.ResponseWriter, r *http.Request) {
+	var p aciProfile
+	if !decode(w, r, &p) {
+		return
+	}
+	if p.Name == nil || *p.Name == "" {
+		writeError(w, http.StatusBadRequest, "name: This field is required.")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p.UUID = ccp.String(newID())
+	s.aciProfiles = append(s.aciProfiles, p)
+	writeJSON(w, http.StatusCreated, p)
+}
+
+func (s *Server) patchACIProfile(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, p := range s.aciProfiles {
+		if *p.UUID != r.PathValue("id") {
+			continue
+		}
+		patched := clone(p)
+		if !decode(w, r, &patched) {
+			return
+		}
+		patched.UUID = p.UUID
+		s.aciProfiles[i] = patched
+		writeJSON(w, http.StatusOK, patched)
+		return
+	}
+	writeError(w, http.StatusNotFound, "Not found.")
+}
+
+func (s *Server) deleteACIProfile(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, p := range s.aciProfiles {
+		if *p.UUID == r.PathValue("id") {
+			s.aciProfiles = append(s.aciProfiles[:i:i], s.aciProfiles[i+1:]...)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "Not found.")
+}
+
+func (s *Server) listSubnets(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeList(w, r, append([]subnet{}, s.subnets...))
+}
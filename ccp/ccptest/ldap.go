@@ -0,0 +1,121 @@
+/*Copyright (c) 2019 Cisco and/or its affiliates.
+
+This software is licensed to you under the terms of the Cisco Sample
+Code License, Version 1.0 (the "License"). You may obtain a copy of the
+License at
+
+               https://developer.cisco.com/docs/licenses
+
+All use of the material herein must be in accordance with the terms of
+the License. All rights not expressly granted by the License are
+reserved. Unless required by applicable law or agreed to separately in
+writing, software distributed under the License is distributed on an "AS
+IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+or implied.*/
+
+package ccptest
+
+import (
+	"net/http"
+
+	"github.com/rob-moss/ccp-clientlibrary-go/ccp"
+)
+
+func (s *Server) ldapRoutes(mux *http.ServeMux) {
+	handle(mux, "GET", "/v3/ldap", s.getLDAP)
+	handle(mux, "POST", "/v3/ldap", s.setLDAP)
+	handle(mux, "PATCH", "/v3/ldap", s.patchLDAP)
+	handle(mux, "DELETE", "/v3/ldap", s.deleteLDAP)
+	handle(mux, "POST", "/v3/ldap/test", s.testLDAP)
+}
+
+// LDAPSetup returns the stored LDAP setup, with its password, and whether there is one
+func (s *Server) LDAPSetup() (ccp.LDAPSetup, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ldap == nil {
+		return ccp.LDAPSetup{}, false
+	}
+	return clone(*s.ldap), true
+}
+
+// withoutPassword is the setup as the API shows it
+func withoutPassword(setup ccp.LDAPSetup) ccp.LDAPSetup {
+	setup.ServiceAccountPassword = nil
+	return setup
+}
+
+func (s *Server) getLDAP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ldap == nil {
+		writeError(w, http.StatusNotFound, "LDAP is not configured")
+		return
+	}
+	writeJSON(w, http.StatusOK, withoutPassword(*s.ldap))
+}
+
+func (s *Server) setLDAP(w http.ResponseWriter, r *http.Request) {
+	var setup ccp.LDAPSetup
+	if !decode(w, r, &setup) {
+		return
+	}
+	if setup.Server == nil || setup.BaseDN == nil {
+		writeError(w, http.StatusBadRequest, "server and baseDN: This field is required.")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ldap = &setup
+	writeJSON(w, http.StatusCreated, withoutPassword(setup))
+}
+
+func (s *Server) patchLDAP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ldap == nil {
+		writeError(w, http.StatusNotFound, "LDAP is not configured")
+		return
+	}
+	patched := clone(*s.ldap)
+	if !decode(w, r, &patched) {
+		return
+	}
+	s.ldap = &patched
+	writeJSON(w, http.StatusOK, withoutPassword(patched))
+}
+
+func (s *Server) deleteLDAP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ldap == nil {
+		writeError(w, http.StatusNotFound, "LDAP is not configured")
+		return
+	}
+	s.ldap = nil
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// testLDAP pretends to bind. Any complete setup connects, use a Fault to make it fail
+func (s *Server) testLDAP(w http.ResponseWriter, r *http.Request) {
+	var setup *ccp.LDAPSetup
+	if r.ContentLength != 0 && !decode(w, r, &setup) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if setup == nil {
+		setup = s.ldap
+	}
+	if setup == nil {
+		writeError(w, http.StatusNotFound, "LDAP is not configured")
+		return
+	}
+	if setup.Server == nil || setup.BaseDN == nil {
+		writeJSON(w, http.StatusOK, ccp.LDAPTestResult{Connected: ccp.Bool(false), Message: ccp.String("server and baseDN are required")})
+		return
+	}
+	writeJSON(w, http.StatusOK, ccp.LDAPTestResult{Connected: ccp.Bool(true), Message: ccp.String("Bound to " + *setup.Server)})
+}
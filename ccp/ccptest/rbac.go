@@ -0,0 +1,122 @@
+/*Copyright (c) 2019 Cisco and/or its affiliates.
+
+This software is licensed to you under the terms of the Cisco Sample
+Code License, Version 1.0 (the "License"). You may obtain a copy of the
+License at
+
+               https://developer.cisco.com/docs/licenses
+
+All use of the material herein must be in accordance with the terms of
+the License. All rights not expressly granted by the License are
+reserved. Unless required by applicable law or agreed to separately in
+writing, software distributed under the License is distributed on an "AS
+IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+or implied.*/
+
+package ccptest
+
+import (
+	"net/http"
+
+	"github.com/rob-moss/ccp-clientlibrary-go/ccp"
+)
+
+// roles are the RBAC roles every Server has
+var roles = []ccp.Role{
+	{Role: ccp.String(ccp.RoleAdministrator), Description: ccp.String("Manages the control plane, its users and every cluster"), Permissions: &[]string{"*"}},
+	{Role: ccp.String(ccp.RoleDevops), Description: ccp.String("Uses the clusters it has been granted"), Permissions: &[]string{"clusters:read", "clusters:kubeconfig"}},
+}
+
+func (s *Server) rbacRoutes(mux *http.ServeMux) {
+	handle(mux, "GET", "/v3/rbac/roles", s.listRoles)
+	handle(mux, "GET", "/v3/clusters/{id}/authz", s.getAuthz)
+	handle(mux, "POST", "/v3/clusters/{id}/authz", s.grantAuthz)
+	handle(mux, "DELETE", "/v3/clusters/{id}/authz", s.revokeAuthz)
+}
+
+// ClusterAuthz returns who has been granted access to a cluster
+func (s *Server) ClusterAuthz(uuid string) ([]ccp.AuthListEntry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := s.findCluster(uuid)
+	if c == nil {
+		return nil, false
+	}
+	return clone(c.authz), true
+}
+
+// authzList is the cluster's authorization list as the API shows it
+func (c *cluster) authzList() ccp.ClusterAuthz {
+	list := append([]ccp.AuthListEntry{}, c.authz...)
+	return ccp.ClusterAuthz{ClusterUUID: c.UUID, AuthList: &list}
+}
+
+func (s *Server) listRoles(w http.ResponseWriter, r *http.Request) {
+	writeList(w, r, clone(roles))
+}
+
+func (s *Server) getAuthz(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := s.findCluster(r.PathValue("id"))
+	if c == nil {
+		writeError(w, http.StatusNotFound, "Not found.")
+		return
+	}
+	writeJSON(w, http.StatusOK, c.authzList())
+}
+
+func (s *Server) grantAuthz(w http.ResponseWriter, r *http.Request) {
+	var e ccp.AuthListEntry
+	if !decode(w, r, &e) {
+		return
+	}
+	if e.Type == nil || e.Name == nil || (*e.Type != ccp.AuthTypeUser && *e.Type != ccp.AuthTypeGroup) {
+		writeError(w, http.StatusBadRequest, "type must be user or group, and name is required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := s.findCluster(r.PathValue("id"))
+	if c == nil {
+		writeError(w, http.StatusNotFound, "Not found.")
+		return
+	}
+	if _, ok := s.users[*e.Name]; *e.Type == ccp.AuthTypeUser && !ok {
+		writeError(w, http.StatusBadRequest, "User "+*e.Name+" does not exist")
+		return
+	}
+	if c.findAuthz(*e.Type, *e.Name) < 0 {
+		c.authz = append(c.authz, e)
+	}
+	writeJSON(w, http.StatusOK, c.authzList())
+}
+
+func (s *Server) revokeAuthz(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := s.findCluster(r.PathValue("id"))
+	if c == nil {
+		writeError(w, http.StatusNotFound, "Not found.")
+		return
+	}
+	q := r.URL.Query()
+	i := c.findAuthz(q.Get("type"), q.Get("name"))
+	if i < 0 {
+		writeError(w, http.StatusNotFound, q.Get("type")+" "+q.Get("name")+" has no access to this cluster")
+		return
+	}
+	c.authz = append(c.authz[:i:i], c.authz[i+1:]...)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// findAuthz returns the index of the grant to authType name, or -1
+func (c *cluster) findAuthz(authType, name string) int {
+	for i, e := range c.authz {
+		if *e.Type == authType && *e.Name == name {
+			return i
+		}
+	}
+	return -1
+}
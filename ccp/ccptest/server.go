@@ -0,0 +1,455 @@
+/*Copyright (c) 2019 Cisco and/or its affiliates.
+
+This software is licensed to you under the terms of the Cisco Sample
+Code License, Version 1.0 (the "License"). You may obtain a copy of the
+License at
+
+               https://developer.cisco.com/docs/licenses
+
+All use of the material herein must be in accordance with the terms of
+the License. All rights not expressly granted by the License are
+reserved. Unless required by applicable law or agreed to separately in
+writing, software distributed under the License is distributed on an "AS
+IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+or implied.*/
+
+// Package ccptest runs an in-process fake CCP control plane for tests.
+//
+//	srv := ccptest.NewServer()
+//	defer srv.Close()
+//
+//	client, err := ccp.NewClient(srv.URL, ccp.WithCredentials(ccptest.Username, ccptest.Password))
+//	err = client.Login(client)
+//
+// It serves login, logout, whoami, health and version, users, the LDAP setup,
+// RBAC roles, clusters and who may use them, node pools, addons and the
+// catalog, providers, ACI profiles and subnets from memory. New clusters go
+// from CREATING to READY after CreateDelay and deleted ones show as DELETING
+// for DeleteDelay. Faults inject errors and latency, and Requests returns
+// everything the server received. Lists are paginated when the request asks
+// for a page, as ccp.WithPageSize does. SetAPIVersion turns it into an older
+// control plane that only has the v2 API.
+package ccptest
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rob-moss/ccp-clientlibrary-go/ccp"
+)
+
+// Default credentials accepted by a new Server
+const (
+	Username = "admin"
+	Password = "password"
+)
+
+// DefaultVersion is the CCP version a new Server reports
+const DefaultVersion = "6.1.0"
+
+// DefaultPageSize is the page size of paginated lists when the request doesn't give page_size
+const DefaultPageSize = 20
+
+// Server is a fake CCP control plane. Its state is safe to change from the test
+// while clients are talking to it
+type Server struct {
+	*httptest.Server
+
+	// CreateDelay is how long a new cluster stays CREATING before it is READY
+	CreateDelay time.Duration
+	// DeleteDelay is how long a deleted cluster shows as DELETING before it is gone
+	DeleteDelay time.Duration
+
+	mu          sync.Mutex
+	users       map[string]*account
+	tokens      map[string]string // X-Auth-Token to username
+	clusters    []*cluster
+	providers   []providerConfig
+	aciProfiles []aciProfile
+	subnets     []subnet
+	faults      []*Fault
+	requests    []Request
+	health      ccp.Health
+	version     string
+	apiVersion  ccp.APIVersion
+	ldap        *ccp.LDAPSetup
+}
+
+// Request is a request received by the Server
+type Request struct {
+	Method string
+	Path   string
+	Header http.Header
+	Body   []byte
+}
+
+// Fault makes matching requests fail or respond slowly
+type Fault struct {
+	Method string        // "" matches every method
+	Path   string        // path prefix, "" matches every path
+	Status int           // respond with this status instead of handling the request, 0 to only delay
+	Body   string        // response body sent with Status
+	Delay  time.Duration // wait before responding
+	Times  int           // how many requests to affect, 0 for all of them
+}
+
+// NewServer starts a fake control plane that accepts Username and Password
+func NewServer() *Server {
+	s := newServer()
+	s.Server = httptest.NewServer(s.handler())
+	return s
+}
+
+// NewTLSServer is NewServer over HTTPS with a self-signed certificate,
+// use srv.Client().Transport with ccp.WithTransport to trust it
+func NewTLSServer() *Server {
+	s := newServer()
+	s.Server = httptest.NewTLSServer(s.handler())
+	return s
+}
+
+// NewMutualTLSServer is NewTLSServer that also requires a client certificate signed by
+// one of clientCAs, for testing mutual TLS
+func NewMutualTLSServer(clientCAs *x509.CertPool) *Server {
+	s := newServer()
+	s.Server = httptest.NewUnstartedServer(s.handler())
+	s.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	s.StartTLS()
+	return s
+}
+
+// account is a user that can log in unless disabled
+type account struct {
+	id        string
+	password  string
+	role      string
+	firstName string
+	lastName  string
+	disabled  bool
+}
+
+func newServer() *Server {
+	return &Server{
+		users:      map[string]*account{Username: {id: newID(), password: Password, role: ccp.RoleAdministrator}},
+		tokens:     map[string]string{},
+		health:     healthy(),
+		version:    DefaultVersion,
+		apiVersion: ccp.APIv3,
+	}
+}
+
+// handler routes every CCP endpoint the fake implements
+func (s *Server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v3/system/login", s.login)
+	mux.HandleFunc("POST /v3/system/logout", s.logout)
+	mux.HandleFunc("GET /v3/system/whoami", s.whoami)
+	mux.HandleFunc("GET /v3/system/livenessHealth", s.livenessHealth)
+	mux.HandleFunc("GET /v3/system/health", s.getHealth)
+	mux.HandleFunc("GET /v3/system/version", s.getVersion)
+	s.clusterRoutes(mux)
+	s.infraRoutes(mux)
+	s.userRoutes(mux)
+	s.ldapRoutes(mux)
+	s.rbacRoutes(mux)
+	s.v2Routes(mux)
+	return s.middleware(mux)
+}
+
+// middleware records the request, applies faults, hides the endpoints of the other
+// API version and checks the X-Auth-Token or the v2 session cookie
+func (s *Server) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		s.mu.Lock()
+		s.requests = append(s.requests, Request{Method: r.Method, Path: r.URL.Path, Header: r.Header.Clone(), Body: body})
+		fault := s.matchFault(r)
+		_, authorized := s.tokens[r.Header.Get("X-Auth-Token")]
+		if c, err := r.Cookie(sessionCookie); err == nil && !authorized {
+			_, authorized = s.tokens[c.Value]
+		}
+		served := s.served(r.URL.Path)
+		s.mu.Unlock()
+
+		if fault != nil {
+			if fault.Delay > 0 {
+				select {
+				case <-time.After(fault.Delay):
+				case <-r.Context().Done():
+					return
+				}
+			}
+			if fault.Status != 0 {
+				w.WriteHeader(fault.Status)
+				w.Write([]byte(fault.Body))
+				return
+			}
+		}
+
+		if !served {
+			writeError(w, http.StatusNotFound, "Not found")
+			return
+		}
+
+		if !public(r.URL.Path) && !authorized {
+			writeError(w, http.StatusUnauthorized, "Invalid or expired X-Auth-Token")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// public reports whether path answers without a session
+func public(path string) bool {
+	switch strings.TrimSuffix(path, "/") {
+	case "/v3/system/login", "/v3/system/livenessHealth", "/2/system/login", "/2/system/livenessHealth":
+		return true
+	}
+	return false
+}
+
+// matchFault returns the first fault for r, using up one of its Times. s.mu must be held
+func (s *Server) matchFault(r *http.Request) *Fault {
+	for i, f := range s.faults {
+		if f.Method != "" && !strings.EqualFold(f.Method, r.Method) {
+			continue
+		}
+		if !strings.HasPrefix(r.URL.Path, f.Path) {
+			continue
+		}
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				s.faults = append(s.faults[:i:i], s.faults[i+1:]...)
+			}
+		}
+		return f
+	}
+	return nil
+}
+
+// AddFault makes requests matching f fail or slow down
+func (s *Server) AddFault(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &f)
+}
+
+// ClearFaults removes every fault
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// Requests returns every request received so far, oldest first
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// ResetRequests forgets the requests received so far
+func (s *Server) ResetRequests() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = nil
+}
+
+// AddUser lets username log in with password, with the Devops role
+func (s *Server) AddUser(username, password string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.users[username] = &account{id: newID(), password: password, role: ccp.RoleDevops}
+}
+
+// ExpireTokens invalidates every X-Auth-Token handed out so far, as if the sessions timed out
+func (s *Server) ExpireTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens = map[string]string{}
+}
+
+// login checks the JSON credentials and hands out a new X-Auth-Token
+func (s *Server) login(w http.ResponseWriter, r *http.Request) {
+	var creds struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&creds); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if a, ok := s.users[creds.Username]; !ok || a.password != creds.Password || a.disabled {
+		writeError(w, http.StatusUnauthorized, "Invalid username or password")
+		return
+	}
+	token := newID()
+	s.tokens[token] = creds.Username
+	w.Header().Set("X-Auth-Token", token)
+	w.WriteHeader(http.StatusOK)
+}
+
+// logout invalidates the request's X-Auth-Token
+func (s *Server) logout(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.tokens, r.Header.Get("X-Auth-Token"))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// whoami describes the user the X-Auth-Token belongs to
+func (s *Server) whoami(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	username := s.tokens[r.Header.Get("X-Auth-Token")]
+	a, ok := s.users[username]
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "Invalid or expired X-Auth-Token")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"id": a.id, "username": username, "role": a.role})
+}
+
+// SetHealth changes what the health endpoint reports, the Server starts out healthy
+func (s *Server) SetHealth(h ccp.Health) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.health = clone(h)
+}
+
+// SetVersion changes the CCP version the Server reports
+func (s *Server) SetVersion(version string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.version = version
+}
+
+// healthy is a three node control plane with nothing wrong
+func healthy() ccp.Health {
+	var nodes []ccp.NodeStatus
+	for _, name := range []string{"master-0", "master-1", "master-2"} {
+		nodes = append(nodes, ccp.NodeStatus{NodeName: ccp.String(name), NodeCondition: ccp.String("Ready"), NodeStatus: ccp.String("True")})
+	}
+	return ccp.Health{
+		TotalSystemHealth: ccp.String("Healthy"),
+		CurrentNodes:      ccp.Int64(3),
+		ExpectedNodes:     ccp.Int64(3),
+		NodesStatus:       &nodes,
+		PodStatusList:     &[]ccp.PodStatusList{},
+	}
+}
+
+func (s *Server) livenessHealth(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeJSON(w, http.StatusOK, ccp.LivenessHealth{CXVersion: ccp.String(s.version), TimeOnMgmtHost: ccp.String(time.Now().UTC().Format(time.RFC3339))})
+}
+
+func (s *Server) getHealth(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeJSON(w, http.StatusOK, s.health)
+}
+
+func (s *Server) getVersion(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeJSON(w, http.StatusOK, ccp.Version{CXVersion: ccp.String(s.version)})
+}
+
+// newID returns a random UUID like the ones CCP hands out
+func newID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	h := hex.EncodeToString(b)
+	return fmt.Sprintf("%s-%s-%s-%s-%s", h[0:8], h[8:12], h[12:16], h[16:20], h[20:32])
+}
+
+// decode reads the JSON request body into v, answering 400 if it can't
+func decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return false
+	}
+	return true
+}
+
+// writeJSON sends v as the JSON response body
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeList sends items as a bare JSON array, like CCP does, unless the client asked
+// for a page with page or page_size. Then it sends a {count, next, previous, results}
+// page whose links are full URLs
+func writeList[T any](w http.ResponseWriter, r *http.Request, items []T) {
+	q := r.URL.Query()
+	if !q.Has("page") && !q.Has("page_size") {
+		writeJSON(w, http.StatusOK, items)
+		return
+	}
+	writePage(w, r, items)
+}
+
+// writePage sends one page of items, DefaultPageSize of them unless page_size says otherwise
+func writePage[T any](w http.ResponseWriter, r *http.Request, items []T) {
+	q := r.URL.Query()
+	size, err := strconv.Atoi(q.Get("page_size"))
+	if err != nil || size <= 0 {
+		size = DefaultPageSize
+	}
+	n, err := strconv.Atoi(q.Get("page"))
+	if err != nil || n <= 0 {
+		n = 1
+	}
+
+	link := func(page int) interface{} {
+		if page < 1 || (page-1)*size >= len(items) {
+			return nil
+		}
+		u := *r.URL
+		u.Scheme, u.Host = "http", r.Host
+		if r.TLS != nil {
+			u.Scheme = "https"
+		}
+		q := u.Query()
+		q.Set("page", strconv.Itoa(page))
+		u.RawQuery = q.Encode()
+		return u.String()
+	}
+
+	start := min((n-1)*size, len(items))
+	end := min(start+size, len(items))
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"count":    len(items),
+		"next":     link(n + 1),
+		"previous": link(n - 1),
+		"results":  append([]T{}, items[start:end]...),
+	})
+}
+
+// writeError sends a CCP style JSON error
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]interface{}{"code": status, "message": message})
+}
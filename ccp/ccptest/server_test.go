@@ -0,0 +1,190 @@
+/*Copyright (c) 2019 Cisco and/or its affiliates.
+
+This software is licensed to you under the terms of the Cisco Sample
+Code License, Version 1.0 (the "License"). You may obtain a copy of the
+License at
+
+               https://developer.cisco.com/docs/licenses
+
+All use of the material herein must be in accordance with the terms of
+the License. All rights not expressly granted by the License are
+reserved. Unless required by applicable law or agreed to separately in
+writing, software distributed under the License is distributed on an "AS
+IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+or implied.*/
+
+package ccptest_test
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rob-moss/ccp-clientlibrary-go/ccp"
+	"github.com/rob-moss/ccp-clientlibrary-go/ccp/ccptest"
+)
+
+// newClient returns a client logged in to srv that doesn't retry, so faults show as they are
+func newClient(t *testing.T, srv *ccptest.Server) *ccp.Client {
+	t.Helper()
+
+	client, err := ccp.NewClient(srv.URL, ccp.WithCredentials(ccptest.Username, ccptest.Password), ccp.WithRetryPolicy(nil))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if err := client.Login(client); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	return client
+}
+
+// status returns the status of the cluster as the client sees it, "" once it is gone
+func status(t *testing.T, client *ccp.Client, uuid string) string {
+	t.Helper()
+
+	c, err := client.GetClusterByUUID(uuid)
+	if errors.Is(err, ccp.ErrNotFound) {
+		return ""
+	}
+	if err != nil {
+		t.Fatalf("GetClusterByUUID: %v", err)
+	}
+	return *c.Status
+}
+
+func TestClusterLifecycle(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	srv.CreateDelay = 50 * time.Millisecond
+	srv.DeleteDelay = 50 * time.Millisecond
+	client := newClient(t, srv)
+
+	c, err := client.AddCluster(ccptest.NewCluster("lifecycle"))
+	if err != nil {
+		t.Fatalf("AddCluster: %v", err)
+	}
+	if got := status(t, client, *c.UUID); got != "CREATING" {
+		t.Errorf("status right after AddCluster = %q, want CREATING", got)
+	}
+	time.Sleep(srv.CreateDelay)
+	if got := status(t, client, *c.UUID); got != "READY" {
+		t.Errorf("status after CreateDelay = %q, want READY", got)
+	}
+
+	if _, err := client.AddCluster(ccptest.NewCluster("lifecycle")); !errors.Is(err, ccp.ErrConflict) {
+		t.Errorf("AddCluster with a taken name error = %v, want ErrConflict", err)
+	}
+
+	if err := client.DeleteCluster(*c.UUID); err != nil {
+		t.Fatalf("DeleteCluster: %v", err)
+	}
+	if got := status(t, client, *c.UUID); got != "DELETING" {
+		t.Errorf("status right after DeleteCluster = %q, want DELETING", got)
+	}
+	time.Sleep(srv.DeleteDelay)
+	if got := status(t, client, *c.UUID); got != "" {
+		t.Errorf("status after DeleteDelay = %q, want the cluster gone", got)
+	}
+}
+
+func TestFaults(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	client := newClient(t, srv)
+
+	// Times runs out, and the fault only matches its method
+	srv.AddFault(ccptest.Fault{Method: http.MethodGet, Path: "/v3/clusters", Status: http.StatusServiceUnavailable, Body: `{"detail": "upgrading"}`, Times: 2})
+	for i := 0; i < 2; i++ {
+		var apiErr *ccp.APIError
+		if _, err := client.GetClusters(); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+			t.Fatalf("GetClusters %d error = %v, want the 503 fault", i+1, err)
+		}
+	}
+	if _, err := client.GetClusters(); err != nil {
+		t.Errorf("GetClusters after the fault ran out: %v", err)
+	}
+
+	// a delay with no status slows the request down and then serves it
+	srv.AddFault(ccptest.Fault{Path: "/v3/system/whoami", Delay: 50 * time.Millisecond})
+	start := time.Now()
+	if _, err := client.WhoAmI(); err != nil {
+		t.Errorf("WhoAmI with a delay: %v", err)
+	}
+	if took := time.Since(start); took < 50*time.Millisecond {
+		t.Errorf("WhoAmI took %s, want the 50ms delay", took)
+	}
+
+	srv.AddFault(ccptest.Fault{Status: http.StatusInternalServerError})
+	srv.ClearFaults()
+	if _, err := client.GetClusters(); err != nil {
+		t.Errorf("GetClusters after ClearFaults: %v", err)
+	}
+}
+
+func TestRequests(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	client := newClient(t, srv)
+	srv.ResetRequests()
+
+	c, err := client.AddCluster(ccptest.NewCluster("recorded"))
+	if err != nil {
+		t.Fatalf("AddCluster: %v", err)
+	}
+	if err := client.DeleteCluster(*c.UUID); err != nil {
+		t.Fatalf("DeleteCluster: %v", err)
+	}
+
+	requests := srv.Requests()
+	if len(requests) != 2 {
+		t.Fatalf("got %d requests, want 2", len(requests))
+	}
+	if r := requests[0]; r.Method != http.MethodPost || r.Path != "/v3/clusters/" || !strings.Contains(string(r.Body), `"name":"recorded"`) {
+		t.Errorf("first request = %s %s %s, want the POST creating the cluster", r.Method, r.Path, r.Body)
+	}
+	if r := requests[1]; r.Method != http.MethodDelete || r.Path != "/v3/clusters/"+*c.UUID+"/" {
+		t.Errorf("second request = %s %s, want the DELETE", r.Method, r.Path)
+	}
+	for _, r := range requests {
+		if r.Header.Get("X-Auth-Token") != client.Token() {
+			t.Errorf("%s %s went without the session token", r.Method, r.Path)
+		}
+	}
+
+	srv.ResetRequests()
+	if n := len(srv.Requests()); n != 0 {
+		t.Errorf("got %d requests after ResetRequests, want 0", n)
+	}
+}
+
+func TestAuth(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+
+	client, err := ccp.NewClient(srv.URL, ccp.WithCredentials(ccptest.Username, "wrong"))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	var authErr *ccp.AuthError
+	if err := client.Login(client); !errors.As(err, &authErr) {
+		t.Errorf("Login with a wrong password error = %v, want an *ccp.AuthError", err)
+	}
+
+	client = newClient(t, srv)
+	token := client.Token()
+	srv.ExpireTokens()
+
+	anonymous, err := ccp.NewClient(srv.URL)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	anonymous.SetToken(token)
+	if _, err := anonymous.GetClusters(); !errors.Is(err, ccp.ErrUnauthorized) {
+		t.Errorf("GetClusters with an expired token error = %v, want ErrUnauthorized", err)
+	}
+	if _, err := anonymous.GetLivenessHealth(); err != nil {
+		t.Errorf("GetLivenessHealth without a session: %v", err)
+	}
+}
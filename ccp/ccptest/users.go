@@ -0,0 +1,188 @@
+/*Copyright (c) 2019 Cisco and/or its affiliates.
+
+This software is licensed to you under the terms of the Cisco Sample
+Code License, Version 1.0 (the "License"). You may obtain a copy of the
+License at
+
+               https://developer.cisco.com/docs/licenses
+
+All use of the material herein must be in accordance with the terms of
+the License. All rights not expressly granted by the License are
+reserved. Unless required by applicable law or agreed to separately in
+writing, software distributed under the License is distributed on an "AS
+IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+or implied.*/
+
+package ccptest
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/rob-moss/ccp-clientlibrary-go/ccp"
+)
+
+func (s *Server) userRoutes(mux *http.ServeMux) {
+	handle(mux, "GET", "/v3/users", s.listUsers)
+	handle(mux, "POST", "/v3/users", s.createUser)
+	handle(mux, "GET", "/v3/users/{id}", s.getUser)
+	handle(mux, "PATCH", "/v3/users/{id}", s.patchUser)
+	handle(mux, "DELETE", "/v3/users/{id}", s.deleteUser)
+}
+
+// User returns the stored user called username, without its password
+func (s *Server) User(username string) (ccp.User, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a, ok := s.users[username]
+	if !ok {
+		return ccp.User{}, false
+	}
+	return a.user(username), true
+}
+
+// user is the account as the users API shows it, the password is never sent back
+func (a *account) user(username string) ccp.User {
+	return ccp.User{
+		UUID:      ccp.String(a.id),
+		Username:  ccp.String(username),
+		Disable:   ccp.Bool(a.disabled),
+		Role:      ccp.String(a.role),
+		FirstName: ccp.String(a.firstName),
+		LastName:  ccp.String(a.lastName),
+	}
+}
+
+// userByID returns the username and account with the given id. s.mu must be held
+func (s *Server) userByID(id string) (string, *account) {
+	for username, a := range s.users {
+		if a.id == id {
+			return username, a
+		}
+	}
+	return "", nil
+}
+
+// endSessions drops every token handed out to username. s.mu must be held
+func (s *Server) endSessions(username string) {
+	for token, u := range s.tokens {
+		if u == username {
+			delete(s.tokens, token)
+		}
+	}
+}
+
+// validRole answers 400 unless role is one CCP knows
+func validRole(w http.ResponseWriter, role string) bool {
+	if role != ccp.RoleAdministrator && role != ccp.RoleDevops {
+		writeError(w, http.StatusBadRequest, "role: \""+role+"\" is not a valid choice.")
+		return false
+	}
+	return true
+}
+
+func (s *Server) listUsers(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := []ccp.User{}
+	for username, a := range s.users {
+		list = append(list, a.user(username))
+	}
+	sort.Slice(list, func(i, j int) bool { return *list[i].Username < *list[j].Username })
+	writeList(w, r, list)
+}
+
+func (s *Server) createUser(w http.ResponseWriter, r *http.Request) {
+	var u ccp.User
+	if !decode(w, r, &u) {
+		return
+	}
+	if u.Username == nil || *u.Username == "" {
+		writeError(w, http.StatusBadRequest, "username: This field is required.")
+		return
+	}
+	if u.Role == nil {
+		writeError(w, http.StatusBadRequest, "role: This field is required.")
+		return
+	}
+	if !validRole(w, *u.Role) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.users[*u.Username]; ok {
+		writeError(w, http.StatusConflict, "User "+*u.Username+" already exists")
+		return
+	}
+	a := &account{id: newID(), role: *u.Role}
+	a.update(u)
+	s.users[*u.Username] = a
+	writeJSON(w, http.StatusCreated, a.user(*u.Username))
+}
+
+func (s *Server) getUser(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	username, a := s.userByID(r.PathValue("id"))
+	if a == nil {
+		writeError(w, http.StatusNotFound, "Not found.")
+		return
+	}
+	writeJSON(w, http.StatusOK, a.user(username))
+}
+
+func (s *Server) patchUser(w http.ResponseWriter, r *http.Request) {
+	var u ccp.User
+	if !decode(w, r, &u) {
+		return
+	}
+	if u.Role != nil && !validRole(w, *u.Role) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	username, a := s.userByID(r.PathValue("id"))
+	if a == nil {
+		writeError(w, http.StatusNotFound, "Not found.")
+		return
+	}
+	a.update(u)
+	if a.disabled {
+		s.endSessions(username)
+	}
+	writeJSON(w, http.StatusOK, a.user(username))
+}
+
+func (s *Server) deleteUser(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	username, a := s.userByID(r.PathValue("id"))
+	if a == nil {
+		writeError(w, http.StatusNotFound, "Not found.")
+		return
+	}
+	delete(s.users, username)
+	s.endSessions(username)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// update copies the fields set in u to the account, the username can't change
+func (a *account) update(u ccp.User) {
+	if u.Password != nil {
+		a.password = *u.Password
+	}
+	if u.Role != nil {
+		a.role = *u.Role
+	}
+	if u.FirstName != nil {
+		a.firstName = *u.FirstName
+	}
+	if u.LastName != nil {
+		a.lastName = *u.LastName
+	}
+	if u.Disable != nil {
+		a.disabled = *u.Disable
+	}
+}
@@ -0,0 +1,126 @@
+/*Copyright (c) 2019 Cisco and/or its affiliates.
+
+This software is licensed to you under the terms of the Cisco Sample
+Code License, Version 1.0 (the "License"). You may obtain a copy of the
+License at
+
+               https://developer.cisco.com/docs/licenses
+
+All use of the material herein must be in accordance with the terms of
+the License. All rights not expressly granted by the License are
+reserved. Unless required by applicable law or agreed to separately in
+writing, software distributed under the License is distributed on an "AS
+IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+or implied.*/
+
+package ccptest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/rob-moss/ccp-clientlibrary-go/ccp"
+)
+
+// sessionCookie is the cookie the v2 login hands out instead of an X-Auth-Token
+const sessionCookie = "sessionid"
+
+// v2Routes serves the part of the v2 API the client falls back to. The v2 objects
+// carry their UUID under "uuid" where v3 uses "id"
+func (s *Server) v2Routes(mux *http.ServeMux) {
+	handle(mux, "POST", "/2/system/login", s.loginV2)
+	handle(mux, "GET", "/2/system/livenessHealth", s.livenessHealth)
+	handle(mux, "GET", "/2/system/health", s.getHealth)
+	handle(mux, "GET", "/2/clusters", v2(s.listClusters))
+	handle(mux, "GET", "/2/clusters/{id}", v2(s.getCluster))
+	handle(mux, "DELETE", "/2/clusters/{id}", s.deleteCluster)
+	handle(mux, "GET", "/2/providerclientconfigs", v2(s.listProviders))
+	handle(mux, "GET", "/2/providerclientconfigs/{id}", v2(s.getProvider))
+}
+
+// SetAPIVersion makes the Server an older control plane that only serves the v2 API,
+// or back into a v3 one. A new Server speaks v3, as CCP 6.x does
+func (s *Server) SetAPIVersion(v ccp.APIVersion) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.apiVersion = v
+}
+
+// served reports whether the API version the Server speaks has path. s.mu must be held
+func (s *Server) served(path string) bool {
+	// the subnets are part of the v2 API on every release
+	if strings.HasPrefix(path, "/2/network_service/") {
+		return true
+	}
+	if s.apiVersion == ccp.APIv2 {
+		return !strings.HasPrefix(path, "/v3/")
+	}
+	return !strings.HasPrefix(path, "/2/")
+}
+
+// loginV2 checks the form credentials and starts a cookie session
+func (s *Server) loginV2(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	username := r.PostForm.Get("username")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if a, ok := s.users[username]; !ok || a.password != r.PostForm.Get("password") || a.disabled {
+		writeError(w, http.StatusUnauthorized, "Invalid username or password")
+		return
+	}
+	token := newID()
+	s.tokens[token] = username
+	http.SetCookie(w, &http.Cookie{Name: sessionCookie, Value: token, Path: "/", HttpOnly: true})
+	w.WriteHeader(http.StatusOK)
+}
+
+// v2 serves a v3 handler's response in the v2 shape, with "id" renamed to "uuid"
+func v2(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		rec := httptest.NewRecorder()
+		h(rec, r)
+
+		body := rec.Body.Bytes()
+		var v interface{}
+		if json.Unmarshal(body, &v) == nil {
+			switch t := v.(type) {
+			case []interface{}:
+				for _, item := range t {
+					renameID(item)
+				}
+			case map[string]interface{}:
+				renameID(t)
+				if results, ok := t["results"].([]interface{}); ok {
+					for _, item := range results {
+						renameID(item)
+					}
+				}
+			}
+			body, _ = json.Marshal(v)
+		}
+
+		for k, vs := range rec.Header() {
+			w.Header()[k] = vs
+		}
+		w.WriteHeader(rec.Code)
+		w.Write(body)
+	}
+}
+
+// renameID moves an object's "id" to "uuid"
+func renameID(v interface{}) {
+	o, ok := v.(map[string]interface{})
+	if !ok {
+		return
+	}
+	if id, ok := o["id"]; ok {
+		o["uuid"] = id
+		delete(o, "id")
+	}
+}
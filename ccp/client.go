@@ -16,57 +16,160 @@ or implied.*/
 package ccp
 
 import (
+	"context"
 	"crypto/tls"
-	"fmt"
+	"errors"
 	"io/ioutil"
-	"log"
+	"log/slog"
+	"net"
 	"net/http"
 	"net/http/cookiejar"
+	"net/url"
 	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 //import "encoding/json"
 
-// Client struct
+// Client struct. A Client is safe for concurrent use by multiple goroutines, and each
+// Client keeps its own session (token and cookies), so one process can drive many
+// control planes, or one control plane as several users
 type Client struct {
-	Username   string
-	Password   string
-	BaseURL    string
-	XAuthToken string
+	Username string
+	Password string
+	BaseURL  string
+
+	xAuthToken string // the session token from Login, read and written with Token and SetToken
+	tokenMu    sync.RWMutex
+
+	// RetryPolicy retries transient failures such as a 503 during a control plane upgrade.
+	// NewClient sets DefaultRetryPolicy, nil disables retries
+	RetryPolicy *RetryPolicy
+
+	// OnTokenRefresh is called with the new X-Auth-Token whenever the client
+	// logs in again because the old token expired. Use it to persist the token
+	OnTokenRefresh func(token string)
+
+	logger atomic.Pointer[slog.Logger] // set with WithLogger or SetDebug, nil logs nothing
+
+	tlsConfig *tls.Config // shared by Login and every other request, see the With* options in tls.go
+
+	// one pooled http.Client per Client so connections are reused, built on first use from the options below
+	httpClient          *http.Client
+	httpClientOnce      sync.Once
+	transport           http.RoundTripper
+	timeout             time.Duration
+	dialTimeout         time.Duration
+	proxyURL            *url.URL
+	userAgent           string
+	maxIdleConnsPerHost int
+	maxConnsPerHost     int
+
+	loginMu sync.Mutex // serialises re-logins so goroutines don't stampede /v3/system/login
+
+	interceptors   []Interceptor        // set with WithInterceptors
+	tracerProvider trace.TracerProvider // set with WithTracerProvider, nil uses the global one
+	metrics        MetricsRecorder      // set with WithMetrics, nil records nothing
+	limits         *limiter             // set with WithRateLimit and WithMaxInFlight
+	methodLimits   map[string]*limiter  // per HTTP verb, set with WithMethodRateLimit and WithMethodMaxInFlight
+	pageSize       int                  // set with WithPageSize, 0 lets the control plane choose
+	cache          *cache               // set with WithCache, nil caches nothing
+	tokenStore     TokenStore           // set with WithTokenStore, nil keeps the token in memory only
+
+	capabilitiesMu sync.Mutex
+	capabilities   *Capabilities // probed on first use, or set with WithAPIVersion
+	probeErr       error         // the last failed probe, returned again until probeRetry
+	probeRetry     time.Time
 }
 
-var jar, err = cookiejar.New(nil)
+func (s *Client) doRequest(req *http.Request) ([]byte, error) {
+
+	for attempt := 1; ; attempt++ {
+		body, err := s.doAuthenticatedRequest(req)
+		if err == nil || !s.RetryPolicy.shouldRetry(req, err, attempt) {
+			return body, err
+		}
 
-// NewClient returns the Client struct pointer
-func NewClient(username, password, baseURL string) *Client {
+		wait := s.RetryPolicy.backoff(err, attempt)
+		s.log().WarnContext(req.Context(), "ccp request failed, retrying",
+			"method", req.Method, "url", req.URL.String(), "attempt", attempt, "wait", wait, "error", err)
+		if err := sleepContext(req.Context(), wait); err != nil {
+			return nil, err
+		}
 
-	return &Client{
-		Username: username,
-		Password: password,
-		BaseURL:  baseURL,
-		// Populate XAuthToken after initialising
+		req, err = cloneRequest(req)
+		if err != nil {
+			return nil, err
+		}
 	}
 }
 
-func (s *Client) doRequest(req *http.Request) ([]byte, error) {
+// Token returns the current X-Auth-Token
+func (s *Client) Token() string {
+	s.tokenMu.RLock()
+	defer s.tokenMu.RUnlock()
+	return s.xAuthToken
+}
+
+// SetToken sets the X-Auth-Token sent with every request, for example one saved from an earlier Login
+func (s *Client) SetToken(token string) {
+	s.tokenMu.Lock()
+	defer s.tokenMu.Unlock()
+	s.xAuthToken = token
+}
+
+// doAuthenticatedRequest sends req, logging in again and replaying it once if the token was rejected
+func (s *Client) doAuthenticatedRequest(req *http.Request) ([]byte, error) {
 
-	var client *http.Client
+	token := s.Token()
+	body, err := s.sendRequest(req, token)
+
+	// the token has expired or been revoked: log in again and replay the request once
+	if errors.Is(err, ErrUnauthorized) && s.canRelogin(req) {
+		s.log().WarnContext(req.Context(), "X-Auth-Token rejected, logging in again", "user", s.Username)
+		if err := s.relogin(req.Context(), token); err != nil {
+			return nil, err
+		}
+
+		retry, err := cloneRequest(req)
+		if err != nil {
+			return nil, err
+		}
+		return s.sendRequest(retry, s.Token())
+	}
+
+	return body, err
+}
+
+// sendRequest sends a single request with the given X-Auth-Token
+func (s *Client) sendRequest(req *http.Request, token string) ([]byte, error) {
+
+	// v2 control planes get the v2 endpoint, or ErrUnsupported
+	req, v2, err := s.route(req)
+	if err != nil {
+		return nil, err
+	}
 
 	// set to JSON
-	req.Header.Add("Content-Type", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", s.userAgentString())
 	// set X-Auth-Token header to xauthtoken from Login
-	req.Header.Add("X-Auth-Token", s.XAuthToken)
-	tr := &http.Transport{
-		// below needed to use Proxy from environment
-		Proxy: http.ProxyFromEnvironment,
-		// TLS checking disabled as most CCP instances use self-signed certs
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	req.Header.Set("X-Auth-Token", token)
+
+	ctx := req.Context()
+	if s.log().Enabled(ctx, LevelTrace) {
+		s.log().Log(ctx, LevelTrace, "ccp request body", "method", req.Method, "url", req.URL.String(), "body", string(redactBody(requestBody(req))))
 	}
-	client = &http.Client{Transport: tr, Jar: jar}
 
-	resp, err := client.Do(req)
+	start := time.Now()
+	resp, err := s.roundTrip(req)
 
 	if err != nil {
+		s.log().DebugContext(ctx, "ccp request failed", "method", req.Method, "url", req.URL.String(), "duration", time.Since(start), "error", err)
 		return nil, err
 	}
 	defer resp.Body.Close()
@@ -75,20 +178,144 @@ func (s *Client) doRequest(req *http.Request) ([]byte, error) {
 		return nil, err
 	}
 
+	s.log().DebugContext(ctx, "ccp request", "method", req.Method, "url", req.URL.String(), "status", resp.StatusCode, "duration", time.Since(start))
+	s.log().Log(ctx, LevelTrace, "ccp response body", "method", req.Method, "url", req.URL.String(), "body", string(redactJSON(body)))
+
 	if 200 != resp.StatusCode && 201 != resp.StatusCode && 202 != resp.StatusCode && 204 != resp.StatusCode {
-		return nil, fmt.Errorf("%s", body)
+		return nil, newAPIError(req, resp, body)
+	}
+
+	if v2 {
+		body = adaptV2(body)
 	}
+	return body, nil
+}
 
+// canRelogin reports whether a rejected request can be retried after logging in again.
+// We need credentials, and a request body that can be read a second time
+func (s *Client) canRelogin(req *http.Request) bool {
+	if s.Username == "" || s.Password == "" {
+		return false
+	}
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// relogin logs in again unless another goroutine already replaced staleToken, or the
+// token store holds a newer token the control plane accepts
+func (s *Client) relogin(ctx context.Context, staleToken string) error {
+	s.loginMu.Lock()
+	defer s.loginMu.Unlock()
+
+	if s.Token() != staleToken {
+		s.log().DebugContext(ctx, "X-Auth-Token already refreshed by another request")
+		return nil
+	}
+
+	// another client sharing the token store may have logged in already
+	resumed, err := s.resumeSession(ctx, staleToken)
 	if err != nil {
-		return nil, err
+		s.log().DebugContext(ctx, "cannot check saved X-Auth-Token", "user", s.Username, "error", err)
 	}
+	if !resumed {
+		if err := s.LoginWithContext(ctx, s); err != nil {
+			return err
+		}
+	}
+	s.meter().ObserveTokenRefresh()
 
-	return body, nil
+	if s.OnTokenRefresh != nil {
+		s.OnTokenRefresh(s.Token())
+	}
+	return nil
+}
+
+// requestBody returns a copy of the request body without consuming it
+func requestBody(req *http.Request) []byte {
+	if req.GetBody == nil {
+		return nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil
+	}
+	defer body.Close()
+	b, _ := ioutil.ReadAll(body)
+	return b
+}
+
+// cloneRequest copies req with a fresh body so it can be sent again
+func cloneRequest(req *http.Request) (*http.Request, error) {
+	clone := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		clone.Body = body
+	}
+	return clone, nil
+}
+
+// DefaultUserAgent is sent with every request unless WithUserAgent is used
+const DefaultUserAgent = "ccp-clientlibrary-go"
+
+// getHTTPClient returns the http.Client shared by every request, building it on first use
+func (s *Client) getHTTPClient() *http.Client {
+	s.httpClientOnce.Do(func() {
+		if s.httpClient != nil { // supplied with WithHTTPClient
+			return
+		}
+
+		rt := s.transport
+		if rt == nil {
+			tr := http.DefaultTransport.(*http.Transport).Clone()
+			tr.TLSClientConfig = s.tlsConfig
+			tr.MaxIdleConnsPerHost = 10 // the net/http default of 2 is too low for fan-out across clusters
+			if s.proxyURL != nil {
+				tr.Proxy = http.ProxyURL(s.proxyURL)
+			}
+			if s.dialTimeout > 0 {
+				tr.DialContext = (&net.Dialer{Timeout: s.dialTimeout, KeepAlive: 30 * time.Second}).DialContext
+			}
+			if s.maxIdleConnsPerHost > 0 {
+				tr.MaxIdleConnsPerHost = s.maxIdleConnsPerHost
+			}
+			if s.maxConnsPerHost > 0 {
+				tr.MaxConnsPerHost = s.maxConnsPerHost
+			}
+			rt = tr
+		}
+
+		// every Client gets its own cookie jar so sessions never leak between clients
+		jar, _ := cookiejar.New(nil)
+		s.httpClient = &http.Client{Transport: rt, Jar: jar, Timeout: s.timeout}
+	})
+	return s.httpClient
+}
+
+// userAgentString returns the User-Agent header value
+func (s *Client) userAgentString() string {
+	if s.userAgent != "" {
+		return s.userAgent
+	}
+	return DefaultUserAgent
+}
+
+// sleepContext waits for d to pass, returning early with the context error if ctx is done first
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
 }
 
 // Bool - Helper routine used to return pointer - will used to simplify the use of the clientlibrary
 func Bool(value bool) *bool {
-	log.Printf("[DEBUG] ******* bool %+v", value)
 	return &value
 }
 
@@ -120,8 +347,8 @@ func Float64(value float64) *float64 {
 	return &value
 }
 
-//modified from unexported nonzero function in the validtor package
-//https://github.com/go-validator/validator/blob/v2/builtins.go
+// modified from unexported nonzero function in the validtor package
+// https://github.com/go-validator/validator/blob/v2/builtins.go
 // nonzero
 func nonzero(v interface{}) bool {
 	st := reflect.ValueOf(v)
@@ -0,0 +1,299 @@
+/*Copyright (c) 2019 Cisco and/or its affiliates.
+
+This software is licensed to you under the terms of the Cisco Sample
+Code License, Version 1.0 (the "License"). You may obtain a copy of the
+License at
+
+               https://developer.cisco.com/docs/licenses
+
+All use of the material herein must be in accordance with the terms of
+the License. All rights not expressly granted by the License are
+reserved. Unless required by applicable law or agreed to separately in
+writing, software distributed under the License is distributed on an "AS
+IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+or implied.*/
+
+package ccp_test
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/rob-moss/ccp-clientlibrary-go/ccp"
+	"github.com/rob-moss/ccp-clientlibrary-go/ccp/ccptest"
+)
+
+// newTestClient returns a client logged in to srv as ccptest.Username
+func newTestClient(t *testing.T, srv *ccptest.Server, opts ...ccp.Option) *ccp.Client {
+	t.Helper()
+
+	opts = append([]ccp.Option{ccp.WithCredentials(ccptest.Username, ccptest.Password)}, opts...)
+	client, err := ccp.NewClient(srv.URL, opts...)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if err := client.Login(client); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	srv.ResetRequests()
+	return client
+}
+
+// countRequests returns how many requests srv received for method and path
+func countRequests(srv *ccptest.Server, method, path string) int {
+	n := 0
+	for _, r := range srv.Requests() {
+		if r.Method == method && r.Path == path {
+			n++
+		}
+	}
+	return n
+}
+
+func TestReloginOnExpiredToken(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+
+	var refreshed []string
+	client := newTestClient(t, srv)
+	client.OnTokenRefresh = func(token string) { refreshed = append(refreshed, token) }
+	oldToken := client.Token()
+
+	srv.ExpireTokens()
+	if _, err := client.GetClusters(); err != nil {
+		t.Fatalf("GetClusters after the token expired: %v", err)
+	}
+
+	if n := countRequests(srv, http.MethodPost, "/v3/system/login"); n != 1 {
+		t.Errorf("logged in %d times, want 1", n)
+	}
+	if n := countRequests(srv, http.MethodGet, "/v3/clusters"); n != 2 {
+		t.Errorf("GET /v3/clusters sent %d times, want 2: rejected, then replayed", n)
+	}
+	if client.Token() == oldToken {
+		t.Error("the token was not replaced")
+	}
+	if len(refreshed) != 1 || refreshed[0] != client.Token() {
+		t.Errorf("OnTokenRefresh got %q, want the new token once", refreshed)
+	}
+}
+
+func TestReloginGivesUpOnBadCredentials(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+
+	client := newTestClient(t, srv)
+	client.Password = "wrong"
+
+	srv.ExpireTokens()
+	_, err := client.GetClusters()
+
+	var authErr *ccp.AuthError
+	if !errors.As(err, &authErr) {
+		t.Fatalf("GetClusters error = %v, want an *ccp.AuthError", err)
+	}
+	if n := countRequests(srv, http.MethodPost, "/v3/system/login"); n != 1 {
+		t.Errorf("logged in %d times, want 1", n)
+	}
+}
+
+func TestNoReloginWithoutCredentials(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+
+	client := newTestClient(t, srv)
+	token := client.Token()
+
+	anonymous, err := ccp.NewClient(srv.URL)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	anonymous.SetToken(token)
+
+	srv.ExpireTokens()
+	if _, err := anonymous.GetClusters(); !errors.Is(err, ccp.ErrUnauthorized) {
+		t.Fatalf("GetClusters error = %v, want ErrUnauthorized", err)
+	}
+	if n := countRequests(srv, http.MethodPost, "/v3/system/login"); n != 0 {
+		t.Errorf("logged in %d times without credentials, want 0", n)
+	}
+}
+
+// TestConcurrentRelogin shares one client between many goroutines and expires the
+// token part way through. Run it with go test -race
+func TestConcurrentRelogin(t *testing.T) {
+	const goroutines, calls = 16, 5
+
+	srv := ccptest.NewServer()
+	defer srv.Close()
+
+	// expire every token once the 20th cluster list is on its way
+	var sent atomic.Int32
+	expire := func(next ccp.RoundTripFunc) ccp.RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			if req.Method == http.MethodGet && sent.Add(1) == 20 {
+				srv.ExpireTokens()
+			}
+			return next(req)
+		}
+	}
+
+	var refreshes atomic.Int32
+	client := newTestClient(t, srv, ccp.WithInterceptors(expire))
+	client.OnTokenRefresh = func(string) { refreshes.Add(1) }
+
+	var wg sync.WaitGroup
+	errs := make(chan error, goroutines*calls)
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < calls; j++ {
+				if _, err := client.GetClusters(); err != nil {
+					errs <- err
+				}
+				_ = client.Token()
+				client.SetDebug(0)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("GetClusters: %v", err)
+	}
+	if n := countRequests(srv, http.MethodPost, "/v3/system/login"); n != 1 {
+		t.Errorf("logged in %d times, want 1", n)
+	}
+	if n := refreshes.Load(); n != 1 {
+		t.Errorf("OnTokenRefresh called %d times, want 1", n)
+	}
+}
+
+// newUserClient returns a client logged in to srv as username, marking its requests with its name
+func newUserClient(t *testing.T, srv *ccptest.Server, username, password string) *ccp.Client {
+	t.Helper()
+
+	client, err := ccp.NewClient(srv.URL, ccp.WithCredentials(username, password), ccp.WithUserAgent(username))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if err := client.Login(client); err != nil {
+		t.Fatalf("Login as %s: %v", username, err)
+	}
+	return client
+}
+
+// sessionsSent returns the X-Auth-Tokens and cookies each client sent, keyed by User-Agent
+func sessionsSent(srv *ccptest.Server) (tokens, cookies map[string]map[string]bool) {
+	tokens, cookies = map[string]map[string]bool{}, map[string]map[string]bool{}
+	for _, r := range srv.Requests() {
+		agent := r.Header.Get("User-Agent")
+		if tokens[agent] == nil {
+			tokens[agent], cookies[agent] = map[string]bool{}, map[string]bool{}
+		}
+		if token := r.Header.Get("X-Auth-Token"); token != "" {
+			tokens[agent][token] = true
+		}
+		if cookie := r.Header.Get("Cookie"); cookie != "" {
+			cookies[agent][cookie] = true
+		}
+	}
+	return tokens, cookies
+}
+
+// TestClientSessionsIsolated runs two clients logged in as different users against one
+// control plane at the same time. Run it with go test -race
+func TestClientSessionsIsolated(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	srv.AddUser("tenant", "tenant-Pa55")
+
+	admin := newUserClient(t, srv, ccptest.Username, ccptest.Password)
+	tenant := newUserClient(t, srv, "tenant", "tenant-Pa55")
+	if admin.Token() == "" || admin.Token() == tenant.Token() {
+		t.Fatalf("tokens %q and %q, want two different sessions", admin.Token(), tenant.Token())
+	}
+
+	var wg sync.WaitGroup
+	for _, c := range []struct {
+		client *ccp.Client
+		user   string
+	}{{admin, ccptest.Username}, {tenant, "tenant"}} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 10; i++ {
+				session, err := c.client.WhoAmI()
+				if err != nil {
+					t.Errorf("WhoAmI as %s: %v", c.user, err)
+					return
+				}
+				if session.Username == nil || *session.Username != c.user {
+					t.Errorf("WhoAmI as %s = %v", c.user, session.Username)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	tokens, cookies := sessionsSent(srv)
+	for user, client := range map[string]*ccp.Client{ccptest.Username: admin, "tenant": tenant} {
+		if len(tokens[user]) != 1 || !tokens[user][client.Token()] {
+			t.Errorf("%s sent tokens %v, want only its own %q", user, tokens[user], client.Token())
+		}
+		if len(cookies[user]) != 0 {
+			t.Errorf("%s sent cookies %v, want none", user, cookies[user])
+		}
+	}
+}
+
+func TestClientCookieJarsIsolated(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	srv.SetAPIVersion(ccp.APIv2)
+	srv.AddUser("tenant", "tenant-Pa55")
+
+	// v2 sessions live in a cookie, not a token
+	admin := newUserClient(t, srv, ccptest.Username, ccptest.Password)
+	tenant := newUserClient(t, srv, "tenant", "tenant-Pa55")
+	srv.ResetRequests()
+	for _, client := range []*ccp.Client{admin, tenant} {
+		if _, err := client.GetClusters(); err != nil {
+			t.Fatalf("GetClusters: %v", err)
+		}
+	}
+	_, cookies := sessionsSent(srv)
+	if len(cookies[ccptest.Username]) != 1 || len(cookies["tenant"]) != 1 {
+		t.Fatalf("cookies sent %v, want one session per client", cookies)
+	}
+	for cookie := range cookies["tenant"] {
+		if cookies[ccptest.Username][cookie] {
+			t.Errorf("both clients sent cookie %q", cookie)
+		}
+	}
+	tenantCookies := cookies["tenant"]
+
+	// dropping one session leaves the other alone
+	if err := admin.Logout(); err != nil {
+		t.Fatalf("Logout: %v", err)
+	}
+	srv.ResetRequests()
+	if _, err := tenant.GetClusters(); err != nil {
+		t.Fatalf("GetClusters after the other client logged out: %v", err)
+	}
+	if n := countRequests(srv, http.MethodPost, "/2/system/login/"); n != 0 {
+		t.Errorf("tenant logged in %d times after the other client logged out, want 0", n)
+	}
+
+	_, cookies = sessionsSent(srv)
+	for cookie := range cookies["tenant"] {
+		if !tenantCookies[cookie] {
+			t.Errorf("tenant sent cookie %q after the other client logged out, want its own session", cookie)
+		}
+	}
+}
@@ -17,16 +17,16 @@ package ccp
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
-	"log"
 	"net/http"
-	"strconv"
 	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
 	validator "gopkg.in/validator.v2"
 )
 
@@ -73,6 +73,13 @@ Done:
 -- kubeflow: done
 */
 
+// How long AddClusterSynchronous waits between status polls, and the addon calls wait
+// between installing or deleting an operator and its instance
+var (
+	clusterPollInterval = 10 * time.Second
+	addonInstallGap     = 2 * time.Second
+)
+
 // Cluster v3 cluster
 type Cluster struct {
 	//  Cluster Variable Name in Struct
@@ -293,104 +300,107 @@ type AddonsCatalogue struct {
 
 // ClusterInstalledAddons list of installed AddOn
 type ClusterInstalledAddons struct {
-	Count    int64      `json:"count"`
-	Next     int64      `json:"next"`
-	Previous int64      `json:"previous"`
-	Results  []struct { // results
-		Name        string   `json:"name"`
-		Namespace   string   `json:"namespace"`
-		DisplayName string   `json:"displayName"`
-		Description string   `json:"description"`
-		AddonStatus struct { // status
-			Name       string `json:"name"`
-			HelmStatus string `json:"helmStatus"`
-			Status     string `json:"status"`
-		} `json:"status"`
-	} `json:"results"`
+	Count    int64            `json:"count"`
+	Next     int64            `json:"next"`
+	Previous int64            `json:"previous"`
+	Results  []InstalledAddon `json:"results"`
+}
+
+// InstalledAddon is one addon in ClusterInstalledAddons
+type InstalledAddon struct {
+	Name        string   `json:"name"`
+	Namespace   string   `json:"namespace"`
+	DisplayName string   `json:"displayName"`
+	Description string   `json:"description"`
+	AddonStatus struct { // status
+		Name       string `json:"name"`
+		HelmStatus string `json:"helmStatus"`
+		Status     string `json:"status"`
+	} `json:"status"`
 }
 
 // GetClusters function for v3
 func (s *Client) GetClusters() ([]Cluster, error) {
-	Debug(1, "GetClusters")
-
-	url := s.BaseURL + "/v3/clusters"
-
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, err
-	}
-	bytes, err := s.doRequest(req)
-	if err != nil {
-		return nil, err
-	}
-
-	// Print out the Println of bytes
-	// to debug: uncomment below. Prints JSON payload
-	// fmt.Println(string(bytes))
-	Debug(3, "Cluster JSON Payload:\n"+string(bytes))
+	return s.GetClustersWithContext(context.Background())
+}
 
-	// Create an Array of Clusters
-	var data []Cluster
+// GetClustersWithContext is GetClusters with a context that can cancel the call
+func (s *Client) GetClustersWithContext(ctx context.Context) (_ []Cluster, err error) {
+	ctx, span := s.startOperation(ctx, "GetClusters")
+	defer func() { endOperation(span, err) }()
+	s.log().DebugContext(ctx, "GetClusters")
 
-	err = json.Unmarshal(bytes, &data)
+	// every page, so large control planes aren't cut short
+	data, err := Collect(paginate[Cluster](ctx, s, "/v3/clusters"))
 	if err != nil {
 		return nil, err
 	}
 
-	// Print out list of Clusters and their index
-	Debug(2, "Found "+strconv.Itoa(len(data))+" clusters")
-	for i, cl := range data {
-		Debug(2, "Found cluster "+strconv.Itoa(i)+" named "+*cl.Name+" with UUID "+*cl.UUID)
-	}
+	s.log().DebugContext(ctx, "found clusters", "count", len(data))
 
 	return data, nil
 }
 
 // GetClusterStatusByName get all clusters, iterate through to find slice matching clusterName
 func (s *Client) GetClusterStatusByName(clusterName string) (*string, error) {
-	Debug(1, "GetClusterStatusByName")
+	return s.GetClusterStatusByNameWithContext(context.Background(), clusterName)
+}
 
-	clusters, err := s.GetClusters()
+// GetClusterStatusByNameWithContext is GetClusterStatusByName with a context that can cancel the call
+func (s *Client) GetClusterStatusByNameWithContext(ctx context.Context, clusterName string) (_ *string, err error) {
+	ctx, span := s.startOperation(ctx, "GetClusterStatusByName", attribute.String("ccp.cluster.name", clusterName))
+	defer func() { endOperation(span, err) }()
+	s.log().DebugContext(ctx, "GetClusterStatusByName", "cluster", clusterName)
+
+	x, err := findByName(ctx, s, cacheClusters, clusterName, s.GetClustersWithContext)
 	if err != nil {
 		return nil, err
 	}
-
-	for i, x := range clusters {
-		Debug(3, "Iteration "+strconv.Itoa(i)+" Cluster found: "+string(*x.Name)+"\n")
-		if string(clusterName) == string(*x.Name) {
-			Debug(2, "Found matching cluster "+clusterName+" = "+*x.Name)
-			return x.Status, nil
-		}
+	if x == nil {
+		return nil, fmt.Errorf("Cannot find cluster %s: %w", clusterName, ErrNotFound)
 	}
-	return nil, errors.New("Cannot find cluster " + clusterName)
+
+	s.log().DebugContext(ctx, "found matching cluster", "cluster", clusterName, "cluster_uuid", *x.UUID)
+	return x.Status, nil
 }
 
 // GetClusterByName get all clusters, iterate through to find slice matching clusterName
 func (s *Client) GetClusterByName(clusterName string) (*Cluster, error) {
-	Debug(1, "GetClusterByName")
+	return s.GetClusterByNameWithContext(context.Background(), clusterName)
+}
 
-	clusters, err := s.GetClusters()
+// GetClusterByNameWithContext is GetClusterByName with a context that can cancel the call
+func (s *Client) GetClusterByNameWithContext(ctx context.Context, clusterName string) (_ *Cluster, err error) {
+	ctx, span := s.startOperation(ctx, "GetClusterByName", attribute.String("ccp.cluster.name", clusterName))
+	defer func() { endOperation(span, err) }()
+	s.log().DebugContext(ctx, "GetClusterByName", "cluster", clusterName)
+
+	x, err := findByName(ctx, s, cacheClusters, clusterName, s.GetClustersWithContext)
 	if err != nil {
 		return nil, err
 	}
-
-	for i, x := range clusters {
-		Debug(3, "Iteration "+strconv.Itoa(i)+" Cluster found: "+string(*x.Name)+"\n")
-		if string(clusterName) == string(*x.Name) {
-			Debug(2, "Found matching cluster "+clusterName+" = "+*x.Name)
-			return &x, nil
-		}
+	if x == nil {
+		return nil, fmt.Errorf("Cannot find cluster %s: %w", clusterName, ErrNotFound)
 	}
-	return nil, errors.New("Cannot find cluster " + clusterName)
+
+	s.log().DebugContext(ctx, "found matching cluster", "cluster", clusterName, "cluster_uuid", *x.UUID)
+	return x, nil
 }
 
 // GetClusterByUUID v3 cluster by UUID
 func (s *Client) GetClusterByUUID(clusterUUID string) (*Cluster, error) {
-	Debug(1, "GetClusterByUUID")
+	return s.GetClusterByUUIDWithContext(context.Background(), clusterUUID)
+}
+
+// GetClusterByUUIDWithContext is GetClusterByUUID with a context that can cancel the call
+func (s *Client) GetClusterByUUIDWithContext(ctx context.Context, clusterUUID string) (_ *Cluster, err error) {
+	ctx, span := s.startOperation(ctx, "GetClusterByUUID", attribute.String("ccp.cluster.uuid", clusterUUID))
+	defer func() { endOperation(span, err) }()
+	s.log().DebugContext(ctx, "GetClusterByUUID", "cluster_uuid", clusterUUID)
 
 	url := fmt.Sprintf(s.BaseURL + "/v3/clusters/" + clusterUUID)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -416,10 +426,17 @@ type ScaleCluster struct {
 
 // ScaleCluster scales an existing cluster
 func (s *Client) ScaleCluster(clusterUUID, workerPoolName string, size int) (*Cluster, error) {
-	Debug(1, "Func: ScaleCluster")
+	return s.ScaleClusterWithContext(context.Background(), clusterUUID, workerPoolName, size)
+}
+
+// ScaleClusterWithContext is ScaleCluster with a context that can cancel the call
+func (s *Client) ScaleClusterWithContext(ctx context.Context, clusterUUID, workerPoolName string, size int) (_ *Cluster, err error) {
+	ctx, span := s.startOperation(ctx, "ScaleCluster", attribute.String("ccp.cluster.uuid", clusterUUID), attribute.String("ccp.node_pool.name", workerPoolName))
+	defer func() { endOperation(span, err) }()
+	defer s.cache.forget(cacheClusters, "", clusterUUID)
+	s.log().DebugContext(ctx, "ScaleCluster", "cluster_uuid", clusterUUID, "node_pool", workerPoolName, "size", size)
 
 	url := s.BaseURL + "/v3/clusters/" + clusterUUID + "/node-pools/" + workerPoolName + "/"
-	Debug(2, "PATCH URL: "+url)
 
 	cluserScale := ScaleCluster{
 		Name: String(workerPoolName),
@@ -430,18 +447,13 @@ func (s *Client) ScaleCluster(clusterUUID, workerPoolName string, size int) (*Cl
 		return nil, err
 	}
 
-	Debug(3, "Sending JSON patch: "+string(j))
-
-	req, err := http.NewRequest("PATCH", url, bytes.NewBuffer(j))
+	req, err := http.NewRequestWithContext(ctx, "PATCH", url, bytes.NewBuffer(j))
 	if err != nil {
-		Debug(1, "http.NewRequest PATCH error "+err.Error())
 		return nil, err
 	}
 
 	bytes, err := s.doRequest(req)
 	if err != nil {
-		Debug(1, "http.doRequest error "+err.Error())
-		Debug(1, string(bytes))
 		return nil, err
 	}
 
@@ -456,93 +468,86 @@ func (s *Client) ScaleCluster(clusterUUID, workerPoolName string, size int) (*Cl
 
 // ConvertJSONToCluster convers JSON
 func (s *Client) ConvertJSONToCluster(jsonFile string) (*Cluster, error) {
-	Debug(1, "Entered ConvertJSONToCluster")
+	s.log().Debug("ConvertJSONToCluster", "file", jsonFile)
 
-	// Debug(2, "Cluster Struct for cluster named "+string(*cluster.Name))
 	jsonBody, err := ioutil.ReadFile(jsonFile)
 	if err != nil {
-		fmt.Println(err)
 		return nil, err
 	}
 
 	var newCluster Cluster
 	err = json.Unmarshal([]byte(jsonBody), &newCluster)
 	if err != nil {
-		fmt.Println("error:", err)
-	} else {
-		fmt.Println("Success")
+		return nil, err
 	}
-	fmt.Printf("Struct: %+v\n", newCluster)
 
 	return &newCluster, nil
 }
 
 // AddClusterOld creates a new cluster without much error checking
 func (s *Client) AddClusterOld(cluster *Cluster) (*Cluster, error) {
-	Debug(1, "Entered AddCluster for "+string(*cluster.Name))
+	return s.AddClusterOldWithContext(context.Background(), cluster)
+}
+
+// AddClusterOldWithContext is AddClusterOld with a context that can cancel the call
+func (s *Client) AddClusterOldWithContext(ctx context.Context, cluster *Cluster) (_ *Cluster, err error) {
+	ctx, span := s.startOperation(ctx, "AddClusterOld", clusterNameAttr(cluster))
+	defer func() { endOperation(span, err) }()
+	defer s.cache.forget(cacheClusters, nameOf(cluster), "")
+	s.log().DebugContext(ctx, "AddCluster", "cluster", *cluster.Name)
 
-	Debug(2, "Start validating Cluster struct")
 	errs := validator.Validate(cluster)
 	if errs != nil {
-		Debug(1, "Errors validating Cluster struct with validator.Validate(): "+string(errs.Error()))
+		s.log().DebugContext(ctx, "cluster failed validation", "cluster", *cluster.Name, "error", errs)
 		return nil, errs
 	}
-	Debug(3, "No Errors validating Cluster struct")
 
 	url := s.BaseURL + "/v3/clusters/"
 
 	j, err := json.Marshal(cluster)
 	if err != nil {
-		Debug(1, "Errors marshaling with json.Marshal(): "+string(err.Error()))
 		return nil, err
 	}
-	Debug(3, "No errors Marshaling JSON")
 
-	Debug(2, "About to POST to url "+url)
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(j))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(j))
 	if err != nil {
-		Debug(1, "Errors POSTing with http.NewRequest: "+string(err.Error()))
 		return nil, err
 	}
 
 	bytes, err := s.doRequest(req)
 	if err != nil {
-		Debug(1, "Errors POSTing with s.doRequest: "+string(err.Error()))
-		Debug(1, "POST response: "+string(bytes))
 		return nil, err
 	}
-	Debug(3, "POST response: "+string(bytes))
 
 	var data Cluster
 
-	// err = json.Unmarshal(bytes, &data)
-	Debug(2, "Unmarshaling response")
 	err = json.Unmarshal(bytes, &data)
 	if err != nil {
-		Debug(1, "Errors unmarshaling with json.Unmarshal: "+string(err.Error()))
 		return nil, err
 	}
-	Debug(2, "Unmarshaled response successfully")
 
-	Debug(2, "CCP API responded with JSON payload for cluster named "+*data.Name+" with UUID "+*data.UUID)
-	if *data.UUID == "" {
-		Debug(1, "CCP API created cluster named "+*data.Name+" with UUID "+*data.UUID)
-	}
+	s.log().DebugContext(ctx, "cluster created", "cluster", *data.Name, "cluster_uuid", *data.UUID)
 	return &data, nil
 }
 
 // AddCluster creates a new cluster with error checking (Conor Murphy updates)
 func (s *Client) AddCluster(cluster *Cluster) (*Cluster, error) {
+	return s.AddClusterWithContext(context.Background(), cluster)
+}
+
+// AddClusterWithContext is AddCluster with a context that can cancel the call
+func (s *Client) AddClusterWithContext(ctx context.Context, cluster *Cluster) (_ *Cluster, err error) {
+	ctx, span := s.startOperation(ctx, "AddCluster", clusterNameAttr(cluster))
+	defer func() { endOperation(span, err) }()
+	defer s.cache.forget(cacheClusters, nameOf(cluster), "")
 
-	Debug(1, "Entered AddCluster for "+string(*cluster.Name))
+	s.log().DebugContext(ctx, "AddCluster", "cluster", *cluster.Name)
 
-	Debug(2, "Start validating Cluster struct")
 	errs := validator.Validate(cluster)
 	if errs != nil {
-		Debug(1, "Errors validating Cluster struct with validator.Validate(): "+string(errs.Error()))
+		s.log().DebugContext(ctx, "cluster failed validation", "cluster", *cluster.Name, "error", errs)
 		return nil, errs
 	}
-	Debug(3, "No Errors validating Cluster struct")
 
 	// https://stackoverflow.com/questions/44320960/omitempty-doesnt-omit-interface-nil-values-in-json
 	// *cluster.MasterNodePool.Nodes returns &[] and since this is not nil, omitempty, won't omit it when we marshal. Instead it includes nodes: null
@@ -605,47 +610,41 @@ func (s *Client) AddCluster(cluster *Cluster) (*Cluster, error) {
 	j, err := json.Marshal(&cluster)
 
 	if err != nil {
-		Debug(1, "Errors marshaling with json.Marshal(): "+string(err.Error()))
 		return nil, err
 	}
-	Debug(3, "No errors Marshaling JSON")
 
-	Debug(2, "About to POST to url "+url)
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(j))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(j))
 	if err != nil {
-		Debug(1, "Errors POSTing with http.NewRequest: "+string(err.Error()))
 		return nil, err
 	}
 
 	bytes, err := s.doRequest(req)
 	if err != nil {
-		Debug(1, "Errors POSTing with s.doRequest: "+string(err.Error()))
-		Debug(1, "POST response: "+string(bytes))
 		return nil, err
 	}
-	Debug(3, "POST response: "+string(bytes))
 
 	var data Cluster
 
-	// err = json.Unmarshal(bytes, &data)
-	Debug(2, "Unmarshaling response")
 	err = json.Unmarshal(bytes, &data)
 	if err != nil {
-		Debug(1, "Errors unmarshaling with json.Unmarshal: "+string(err.Error()))
 		return nil, err
 	}
-	Debug(2, "Unmarshaled response successfully")
 
-	Debug(2, "CCP API responded with JSON payload for cluster named "+*data.Name+" with UUID "+*data.UUID)
-	if *data.UUID == "" {
-		Debug(1, "CCP API created cluster named "+*data.Name+" with UUID "+*data.UUID)
-	}
+	s.log().DebugContext(ctx, "cluster created", "cluster", *data.Name, "cluster_uuid", *data.UUID)
 
 	return &data, nil
 }
 
 // AddClusterSynchronous creates a new cluster but waits until the cluster is created before returning
 func (s *Client) AddClusterSynchronous(cluster *Cluster) (*Cluster, error) {
+	return s.AddClusterSynchronousWithContext(context.Background(), cluster)
+}
+
+// AddClusterSynchronousWithContext is AddClusterSynchronous with a context that can cancel the call
+func (s *Client) AddClusterSynchronousWithContext(ctx context.Context, cluster *Cluster) (_ *Cluster, err error) {
+	ctx, span := s.startOperation(ctx, "AddClusterSynchronous", clusterNameAttr(cluster))
+	defer func() { endOperation(span, err) }()
+	defer s.cache.forget(cacheClusters, nameOf(cluster), "")
 
 	errs := validator.Validate(cluster)
 	if errs != nil {
@@ -707,15 +706,10 @@ func (s *Client) AddClusterSynchronous(cluster *Cluster) (*Cluster, error) {
 	url := s.BaseURL + "/v3/clusters/"
 
 	j, err := json.Marshal(&cluster)
-
-	log.Printf("[DEBUGGING] ******** before sending %s", string(j))
-
-	fmt.Println(string(j))
-
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(j))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(j))
 	if err != nil {
 		return nil, err
 	}
@@ -726,17 +720,30 @@ func (s *Client) AddClusterSynchronous(cluster *Cluster) (*Cluster, error) {
 	}
 	var data Cluster
 
-	fmt.Print(string(bytes))
-
-	// err = json.Unmarshal(bytes, &data)
 	err = json.Unmarshal(bytes, &data)
 	if err != nil {
 		return nil, err
 	}
 
-	time.Sleep(10 * time.Second)
+	// poll the new cluster by UUID, one small GET rather than listing every cluster
+	pollStatus := func() (*string, error) {
+		s.meter().ObservePoll("AddClusterSynchronous")
+		if data.UUID == nil {
+			s.cache.forget(cacheClusters, *cluster.Name, "") // the cached status would never change
+			return s.GetClusterStatusByNameWithContext(ctx, *cluster.Name)
+		}
+		c, err := s.GetClusterByUUIDWithContext(ctx, *data.UUID)
+		if err != nil {
+			return nil, err
+		}
+		return c.Status, nil
+	}
 
-	status, err := s.GetClusterStatusByName(*cluster.Name)
+	if err := sleepContext(ctx, clusterPollInterval); err != nil {
+		return nil, err
+	}
+
+	status, err := pollStatus()
 
 	if err != nil {
 		return nil, err
@@ -744,13 +751,15 @@ func (s *Client) AddClusterSynchronous(cluster *Cluster) (*Cluster, error) {
 
 	for *status == "CREATING" {
 
-		status, err = s.GetClusterStatusByName(*cluster.Name)
+		status, err = pollStatus()
 
 		if err != nil {
 			return nil, err
 		}
 
-		time.Sleep(10 * time.Second)
+		if err := sleepContext(ctx, clusterPollInterval); err != nil {
+			return nil, err
+		}
 
 	}
 
@@ -760,7 +769,15 @@ func (s *Client) AddClusterSynchronous(cluster *Cluster) (*Cluster, error) {
 
 // DeleteCluster deletes a cluster
 func (s *Client) DeleteCluster(clusterUUID string) error {
-	Debug(1, "Entered DeleteCluster for UUID "+clusterUUID)
+	return s.DeleteClusterWithContext(context.Background(), clusterUUID)
+}
+
+// DeleteClusterWithContext is DeleteCluster with a context that can cancel the call
+func (s *Client) DeleteClusterWithContext(ctx context.Context, clusterUUID string) (err error) {
+	ctx, span := s.startOperation(ctx, "DeleteCluster", attribute.String("ccp.cluster.uuid", clusterUUID))
+	defer func() { endOperation(span, err) }()
+	defer s.cache.forget(cacheClusters, "", clusterUUID)
+	s.log().DebugContext(ctx, "DeleteCluster", "cluster_uuid", clusterUUID)
 
 	if clusterUUID == "" {
 		return errors.New("Cluster UUID to delete is required")
@@ -768,7 +785,7 @@ func (s *Client) DeleteCluster(clusterUUID string) error {
 
 	url := s.BaseURL + "/v3/clusters/" + clusterUUID + "/"
 
-	req, err := http.NewRequest("DELETE", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
 	if err != nil {
 		return err
 	}
@@ -777,16 +794,9 @@ func (s *Client) DeleteCluster(clusterUUID string) error {
 		return err
 	}
 
-	Debug(2, "Request sent to API with success response")
 	return nil
 }
 
-// SetDebug sets the debug level
-func (s *Client) SetDebug(debug int) {
-	debuglvl = debug
-	Debug(1, "Debug level set to "+string(debuglvl))
-}
-
 // GetKubeVerFromImage splits the image name and gets the kube ver
 func GetKubeVerFromImage(value string) string {
 	// https://www.dotnetperls.com/between-before-after-go
@@ -810,7 +820,15 @@ func GetKubeVerFromImage(value string) string {
 
 // AddClusterBasic add a v3 cluster the easy way
 func (s *Client) AddClusterBasic(cluster *Cluster) (*Cluster, error) {
-	Debug(1, "Entered AddClusterBasic for cluster "+string(*cluster.Name))
+	return s.AddClusterBasicWithContext(context.Background(), cluster)
+}
+
+// AddClusterBasicWithContext is AddClusterBasic with a context that can cancel the call
+func (s *Client) AddClusterBasicWithContext(ctx context.Context, cluster *Cluster) (_ *Cluster, err error) {
+	ctx, span := s.startOperation(ctx, "AddClusterBasic", clusterNameAttr(cluster))
+	defer func() { endOperation(span, err) }()
+	defer s.cache.forget(cacheClusters, nameOf(cluster), "")
+	s.log().DebugContext(ctx, "AddClusterBasic", "cluster", *cluster.Name)
 	/*
 
 		This function was added in order to provide users a better experience with adding clusters. The list of required
@@ -864,9 +882,7 @@ func (s *Client) AddClusterBasic(cluster *Cluster) (*Cluster, error) {
 	}
 
 	// loop over array of WorkerNodePool
-	for k, v := range *cluster.WorkerNodePool {
-		fmt.Printf("k=%s, v=%+v", k, v)
-
+	for _, v := range *cluster.WorkerNodePool {
 		if nonzero(v.SSHUser) {
 			return nil, errors.New("v.SSHUser is missing")
 		}
@@ -894,7 +910,7 @@ func (s *Client) AddClusterBasic(cluster *Cluster) (*Cluster, error) {
 
 	// Retrieve the provider client config UUID rather than have the user need to provide this themselves.
 	// This is also built for a single provider client config and as of CCP 1.5 this wll be Vsphere
-	providerClientConfigs, err := s.GetInfraProviderByName("vsphere")
+	providerClientConfigs, err := s.GetInfraProviderByNameWithContext(ctx, "vsphere")
 	if err != nil {
 		return nil, err
 	}
@@ -951,7 +967,7 @@ func (s *Client) AddClusterBasic(cluster *Cluster) (*Cluster, error) {
 		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(j))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(j))
 	if err != nil {
 		return nil, err
 	}
@@ -988,7 +1004,14 @@ func (s *Client) AddClusterBasic(cluster *Cluster) (*Cluster, error) {
 
 // InstallAddonIstioOp Installs the Istio Operator
 func (s *Client) InstallAddonIstioOp(clusterUUID string) error {
-	Debug(1, "Entered InstallAddonIstio for UUID "+clusterUUID)
+	return s.InstallAddonIstioOpWithContext(context.Background(), clusterUUID)
+}
+
+// InstallAddonIstioOpWithContext is InstallAddonIstioOp with a context that can cancel the call
+func (s *Client) InstallAddonIstioOpWithContext(ctx context.Context, clusterUUID string) (err error) {
+	ctx, span := s.startOperation(ctx, "InstallAddonIstioOp", attribute.String("ccp.cluster.uuid", clusterUUID))
+	defer func() { endOperation(span, err) }()
+	s.log().DebugContext(ctx, "InstallAddonIstio", "cluster_uuid", clusterUUID)
 
 	if clusterUUID == "" {
 		return errors.New("Cluster UUID is required")
@@ -1018,25 +1041,28 @@ func (s *Client) InstallAddonIstioOp(clusterUUID string) error {
 		}
 	}`)
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonBody))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonBody))
 	if err != nil {
 		return err
 	}
 
-	resp, err := s.doRequest(req)
+	_, err = s.doRequest(req)
 	if err != nil {
 		return err
 	}
-	Debug(3, "Response is:")
-	Debug(3, string(resp))
-
-	Debug(2, "Request sent to API with success response")
 	return nil
 }
 
 // InstallAddonIstioInstance Installs the Istio Instance (install the Operator first)
 func (s *Client) InstallAddonIstioInstance(clusterUUID string) error {
-	Debug(1, "Entered InstallAddonIstioInstance for UUID "+clusterUUID)
+	return s.InstallAddonIstioInstanceWithContext(context.Background(), clusterUUID)
+}
+
+// InstallAddonIstioInstanceWithContext is InstallAddonIstioInstance with a context that can cancel the call
+func (s *Client) InstallAddonIstioInstanceWithContext(ctx context.Context, clusterUUID string) (err error) {
+	ctx, span := s.startOperation(ctx, "InstallAddonIstioInstance", attribute.String("ccp.cluster.uuid", clusterUUID))
+	defer func() { endOperation(span, err) }()
+	s.log().DebugContext(ctx, "InstallAddonIstioInstance", "cluster_uuid", clusterUUID)
 
 	if clusterUUID == "" {
 		return errors.New("Cluster UUID is required")
@@ -1053,33 +1079,39 @@ func (s *Client) InstallAddonIstioInstance(clusterUUID string) error {
 		"url": "/opt/ccp/charts/ccp-istio-cr.tgz"
 	}`)
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonBody))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonBody))
 	if err != nil {
 		return err
 	}
 
-	resp, err := s.doRequest(req)
+	_, err = s.doRequest(req)
 	if err != nil {
 		return err
 	}
-	Debug(3, "Response is:")
-	Debug(3, string(resp))
-
-	Debug(2, "Request sent to API with success response")
 	return nil
 }
 
 // InstallAddonIstio install both
 func (s *Client) InstallAddonIstio(clusterUUID string) error {
-	err := s.InstallAddonIstioOp(clusterUUID)
+	return s.InstallAddonIstioWithContext(context.Background(), clusterUUID)
+}
+
+// InstallAddonIstioWithContext is InstallAddonIstio with a context that can cancel the call
+func (s *Client) InstallAddonIstioWithContext(ctx context.Context, clusterUUID string) (err error) {
+	ctx, span := s.startOperation(ctx, "InstallAddonIstio", attribute.String("ccp.cluster.uuid", clusterUUID))
+	defer func() { endOperation(span, err) }()
+	err = s.InstallAddonIstioOpWithContext(ctx, clusterUUID)
 	if err != nil {
-		Debug(1, "Failed to add Add-On Istio Operator: "+string(err.Error()))
+		s.log().DebugContext(ctx, "failed to add Add-On Istio Operator", "cluster_uuid", clusterUUID, "error", err)
+		return err
+	}
+	// wait 2 seconds before sending the next request
+	if err := sleepContext(ctx, addonInstallGap); err != nil {
 		return err
 	}
-	time.Sleep(2 * time.Second) // wait 2 seconds before sending the next request
-	err = s.InstallAddonIstioInstance(clusterUUID)
+	err = s.InstallAddonIstioInstanceWithContext(ctx, clusterUUID)
 	if err != nil {
-		Debug(1, "Failed to add Add-On Istio Instance: "+string(err.Error()))
+		s.log().DebugContext(ctx, "failed to add Add-On Istio Instance", "cluster_uuid", clusterUUID, "error", err)
 		return err
 	}
 	return nil
@@ -1087,7 +1119,14 @@ func (s *Client) InstallAddonIstio(clusterUUID string) error {
 
 // InstallAddonDashboard Installs the Istio Instance (install the Operator first)
 func (s *Client) InstallAddonDashboard(clusterUUID string) error {
-	Debug(1, "Entered InstallAddonDashboard for UUID "+clusterUUID)
+	return s.InstallAddonDashboardWithContext(context.Background(), clusterUUID)
+}
+
+// InstallAddonDashboardWithContext is InstallAddonDashboard with a context that can cancel the call
+func (s *Client) InstallAddonDashboardWithContext(ctx context.Context, clusterUUID string) (err error) {
+	ctx, span := s.startOperation(ctx, "InstallAddonDashboard", attribute.String("ccp.cluster.uuid", clusterUUID))
+	defer func() { endOperation(span, err) }()
+	s.log().DebugContext(ctx, "InstallAddonDashboard", "cluster_uuid", clusterUUID)
 
 	if clusterUUID == "" {
 		return errors.New("Cluster UUID is required")
@@ -1107,25 +1146,28 @@ func (s *Client) InstallAddonDashboard(clusterUUID string) error {
 		]
 	}`)
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonBody))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonBody))
 	if err != nil {
 		return err
 	}
 
-	resp, err := s.doRequest(req)
+	_, err = s.doRequest(req)
 	if err != nil {
 		return err
 	}
-	Debug(3, "Response is:")
-	Debug(3, string(resp))
-
-	Debug(2, "Request sent to API with success response")
 	return nil
 }
 
 // InstallAddonMonitoring Installs the Istio Instance (install the Operator first)
 func (s *Client) InstallAddonMonitoring(clusterUUID string) error {
-	Debug(1, "Entered InstallAddonMonitoring for UUID "+clusterUUID)
+	return s.InstallAddonMonitoringWithContext(context.Background(), clusterUUID)
+}
+
+// InstallAddonMonitoringWithContext is InstallAddonMonitoring with a context that can cancel the call
+func (s *Client) InstallAddonMonitoringWithContext(ctx context.Context, clusterUUID string) (err error) {
+	ctx, span := s.startOperation(ctx, "InstallAddonMonitoring", attribute.String("ccp.cluster.uuid", clusterUUID))
+	defer func() { endOperation(span, err) }()
+	s.log().DebugContext(ctx, "InstallAddonMonitoring", "cluster_uuid", clusterUUID)
 
 	if clusterUUID == "" {
 		return errors.New("Cluster UUID is required")
@@ -1142,25 +1184,28 @@ func (s *Client) InstallAddonMonitoring(clusterUUID string) error {
 		"url": "/opt/ccp/charts/ccp-monitor.tgz"
 	}`)
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonBody))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonBody))
 	if err != nil {
 		return err
 	}
 
-	resp, err := s.doRequest(req)
+	_, err = s.doRequest(req)
 	if err != nil {
 		return err
 	}
-	Debug(3, "Response is:")
-	Debug(3, string(resp))
-
-	Debug(2, "Request sent to API with success response")
 	return nil
 }
 
 // InstallAddonLogging Installs the Istio Instance (install the Operator first)
 func (s *Client) InstallAddonLogging(clusterUUID string) error {
-	Debug(1, "Entered InstallAddonLogging for UUID "+clusterUUID)
+	return s.InstallAddonLoggingWithContext(context.Background(), clusterUUID)
+}
+
+// InstallAddonLoggingWithContext is InstallAddonLogging with a context that can cancel the call
+func (s *Client) InstallAddonLoggingWithContext(ctx context.Context, clusterUUID string) (err error) {
+	ctx, span := s.startOperation(ctx, "InstallAddonLogging", attribute.String("ccp.cluster.uuid", clusterUUID))
+	defer func() { endOperation(span, err) }()
+	s.log().DebugContext(ctx, "InstallAddonLogging", "cluster_uuid", clusterUUID)
 
 	if clusterUUID == "" {
 		return errors.New("Cluster UUID is required")
@@ -1177,25 +1222,28 @@ func (s *Client) InstallAddonLogging(clusterUUID string) error {
 		"url": "/opt/ccp/charts/ccp-efk.tgz"
 	}`)
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonBody))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonBody))
 	if err != nil {
 		return err
 	}
 
-	resp, err := s.doRequest(req)
+	_, err = s.doRequest(req)
 	if err != nil {
 		return err
 	}
-	Debug(3, "Response is:")
-	Debug(3, string(resp))
-
-	Debug(2, "Request sent to API with success response")
 	return nil
 }
 
 // InstallAddonHarborOp Installs the Istio Instance (install the Operator first)
 func (s *Client) InstallAddonHarborOp(clusterUUID string) error {
-	Debug(1, "Entered InstallAddonHarborOp for UUID "+clusterUUID)
+	return s.InstallAddonHarborOpWithContext(context.Background(), clusterUUID)
+}
+
+// InstallAddonHarborOpWithContext is InstallAddonHarborOp with a context that can cancel the call
+func (s *Client) InstallAddonHarborOpWithContext(ctx context.Context, clusterUUID string) (err error) {
+	ctx, span := s.startOperation(ctx, "InstallAddonHarborOp", attribute.String("ccp.cluster.uuid", clusterUUID))
+	defer func() { endOperation(span, err) }()
+	s.log().DebugContext(ctx, "InstallAddonHarborOp", "cluster_uuid", clusterUUID)
 
 	if clusterUUID == "" {
 		return errors.New("Cluster UUID is required")
@@ -1215,25 +1263,28 @@ func (s *Client) InstallAddonHarborOp(clusterUUID string) error {
 		]
 	}`)
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonBody))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonBody))
 	if err != nil {
 		return err
 	}
 
-	resp, err := s.doRequest(req)
+	_, err = s.doRequest(req)
 	if err != nil {
 		return err
 	}
-	Debug(3, "Response is:")
-	Debug(3, string(resp))
-
-	Debug(2, "Request sent to API with success response")
 	return nil
 }
 
 // InstallAddonHarborInstance Installs the Istio Instance (install the Operator first)
 func (s *Client) InstallAddonHarborInstance(clusterUUID string) error {
-	Debug(1, "Entered InstallAddonHarborInstance for UUID "+clusterUUID)
+	return s.InstallAddonHarborInstanceWithContext(context.Background(), clusterUUID)
+}
+
+// InstallAddonHarborInstanceWithContext is InstallAddonHarborInstance with a context that can cancel the call
+func (s *Client) InstallAddonHarborInstanceWithContext(ctx context.Context, clusterUUID string) (err error) {
+	ctx, span := s.startOperation(ctx, "InstallAddonHarborInstance", attribute.String("ccp.cluster.uuid", clusterUUID))
+	defer func() { endOperation(span, err) }()
+	s.log().DebugContext(ctx, "InstallAddonHarborInstance", "cluster_uuid", clusterUUID)
 
 	if clusterUUID == "" {
 		return errors.New("Cluster UUID is required")
@@ -1250,33 +1301,39 @@ func (s *Client) InstallAddonHarborInstance(clusterUUID string) error {
 		"url": "/opt/ccp/charts/ccp-harbor-cr.tgz"
 	}`)
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonBody))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonBody))
 	if err != nil {
 		return err
 	}
 
-	resp, err := s.doRequest(req)
+	_, err = s.doRequest(req)
 	if err != nil {
 		return err
 	}
-	Debug(3, "Response is:")
-	Debug(3, string(resp))
-
-	Debug(2, "Request sent to API with success response")
 	return nil
 }
 
 // InstallAddonHarbor install both
 func (s *Client) InstallAddonHarbor(clusterUUID string) error {
-	err := s.InstallAddonHarborOp(clusterUUID)
+	return s.InstallAddonHarborWithContext(context.Background(), clusterUUID)
+}
+
+// InstallAddonHarborWithContext is InstallAddonHarbor with a context that can cancel the call
+func (s *Client) InstallAddonHarborWithContext(ctx context.Context, clusterUUID string) (err error) {
+	ctx, span := s.startOperation(ctx, "InstallAddonHarbor", attribute.String("ccp.cluster.uuid", clusterUUID))
+	defer func() { endOperation(span, err) }()
+	err = s.InstallAddonHarborOpWithContext(ctx, clusterUUID)
 	if err != nil {
-		Debug(1, "Failed to add Add-On Istio Operator: "+string(err.Error()))
+		s.log().DebugContext(ctx, "failed to add Add-On Istio Operator", "cluster_uuid", clusterUUID, "error", err)
 		return err
 	}
-	time.Sleep(2 * time.Second) // wait 2 seconds before sending the next request
-	err = s.InstallAddonHarborInstance(clusterUUID)
+	// wait 2 seconds before sending the next request
+	if err := sleepContext(ctx, addonInstallGap); err != nil {
+		return err
+	}
+	err = s.InstallAddonHarborInstanceWithContext(ctx, clusterUUID)
 	if err != nil {
-		Debug(1, "Failed to add Add-On Istio Instance: "+string(err.Error()))
+		s.log().DebugContext(ctx, "failed to add Add-On Istio Instance", "cluster_uuid", clusterUUID, "error", err)
 		return err
 	}
 	return nil
@@ -1284,15 +1341,21 @@ func (s *Client) InstallAddonHarbor(clusterUUID string) error {
 
 // DeleteAddonLogging deletes the addon
 func (s *Client) DeleteAddonLogging(clusterUUID string) error {
-	Debug(1, "Entered DeleteAddonLogging for UUID "+clusterUUID)
+	return s.DeleteAddonLoggingWithContext(context.Background(), clusterUUID)
+}
+
+// DeleteAddonLoggingWithContext is DeleteAddonLogging with a context that can cancel the call
+func (s *Client) DeleteAddonLoggingWithContext(ctx context.Context, clusterUUID string) (err error) {
+	ctx, span := s.startOperation(ctx, "DeleteAddonLogging", attribute.String("ccp.cluster.uuid", clusterUUID))
+	defer func() { endOperation(span, err) }()
+	s.log().DebugContext(ctx, "DeleteAddonLogging", "cluster_uuid", clusterUUID)
 
 	if clusterUUID == "" {
 		return errors.New("Cluster UUID to delete is required")
 	}
 
 	url := s.BaseURL + "/v3/clusters/" + clusterUUID + "/addons/ccp-efk/"
-	Debug(2, "Sending HTTP delte to "+url)
-	req, err := http.NewRequest("DELETE", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
 	if err != nil {
 		return err
 	}
@@ -1301,22 +1364,27 @@ func (s *Client) DeleteAddonLogging(clusterUUID string) error {
 		return err
 	}
 
-	Debug(2, "Request sent to API with success response")
 	return nil
 }
 
 // DeleteAddonMonitor deletes the addon
 func (s *Client) DeleteAddonMonitor(clusterUUID string) error {
-	Debug(1, "Entered DeleteAddonMonitor for UUID "+clusterUUID)
+	return s.DeleteAddonMonitorWithContext(context.Background(), clusterUUID)
+}
+
+// DeleteAddonMonitorWithContext is DeleteAddonMonitor with a context that can cancel the call
+func (s *Client) DeleteAddonMonitorWithContext(ctx context.Context, clusterUUID string) (err error) {
+	ctx, span := s.startOperation(ctx, "DeleteAddonMonitor", attribute.String("ccp.cluster.uuid", clusterUUID))
+	defer func() { endOperation(span, err) }()
+	s.log().DebugContext(ctx, "DeleteAddonMonitor", "cluster_uuid", clusterUUID)
 
 	if clusterUUID == "" {
 		return errors.New("Cluster UUID to delete is required")
 	}
 
 	url := s.BaseURL + "/v3/clusters/" + clusterUUID + "/addons/ccp-monitor/"
-	Debug(2, "Sending HTTP delte to "+url)
 
-	req, err := http.NewRequest("DELETE", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
 	if err != nil {
 		return err
 	}
@@ -1325,22 +1393,27 @@ func (s *Client) DeleteAddonMonitor(clusterUUID string) error {
 		return err
 	}
 
-	Debug(2, "Request sent to API with success response")
 	return nil
 }
 
 // DeleteAddonIstioInstance deletes the addon
 func (s *Client) DeleteAddonIstioInstance(clusterUUID string) error {
-	Debug(1, "Entered DeleteAddonIstioInstance for UUID "+clusterUUID)
+	return s.DeleteAddonIstioInstanceWithContext(context.Background(), clusterUUID)
+}
+
+// DeleteAddonIstioInstanceWithContext is DeleteAddonIstioInstance with a context that can cancel the call
+func (s *Client) DeleteAddonIstioInstanceWithContext(ctx context.Context, clusterUUID string) (err error) {
+	ctx, span := s.startOperation(ctx, "DeleteAddonIstioInstance", attribute.String("ccp.cluster.uuid", clusterUUID))
+	defer func() { endOperation(span, err) }()
+	s.log().DebugContext(ctx, "DeleteAddonIstioInstance", "cluster_uuid", clusterUUID)
 
 	if clusterUUID == "" {
 		return errors.New("Cluster UUID to delete is required")
 	}
 
 	url := s.BaseURL + "/v3/clusters/" + clusterUUID + "/addons/ccp-istio-cr/"
-	Debug(2, "Sending HTTP delte to "+url)
 
-	req, err := http.NewRequest("DELETE", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
 	if err != nil {
 		return err
 	}
@@ -1349,22 +1422,27 @@ func (s *Client) DeleteAddonIstioInstance(clusterUUID string) error {
 		return err
 	}
 
-	Debug(2, "Request sent to API with success response")
 	return nil
 }
 
 // DeleteAddonIstioOp deletes the addon
 func (s *Client) DeleteAddonIstioOp(clusterUUID string) error {
-	Debug(1, "Entered DeleteAddonIstioOp for UUID "+clusterUUID)
+	return s.DeleteAddonIstioOpWithContext(context.Background(), clusterUUID)
+}
+
+// DeleteAddonIstioOpWithContext is DeleteAddonIstioOp with a context that can cancel the call
+func (s *Client) DeleteAddonIstioOpWithContext(ctx context.Context, clusterUUID string) (err error) {
+	ctx, span := s.startOperation(ctx, "DeleteAddonIstioOp", attribute.String("ccp.cluster.uuid", clusterUUID))
+	defer func() { endOperation(span, err) }()
+	s.log().DebugContext(ctx, "DeleteAddonIstioOp", "cluster_uuid", clusterUUID)
 
 	if clusterUUID == "" {
 		return errors.New("Cluster UUID to delete is required")
 	}
 
 	url := s.BaseURL + "/v3/clusters/" + clusterUUID + "/addons/ccp-istio-operator/"
-	Debug(2, "Sending HTTP delte to "+url)
 
-	req, err := http.NewRequest("DELETE", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
 	if err != nil {
 		return err
 	}
@@ -1373,22 +1451,27 @@ func (s *Client) DeleteAddonIstioOp(clusterUUID string) error {
 		return err
 	}
 
-	Debug(2, "Request sent to API with success response")
 	return nil
 }
 
 // DeleteAddonDashboard deletes the addon
 func (s *Client) DeleteAddonDashboard(clusterUUID string) error {
-	Debug(1, "Entered DeleteAddonDashboard for UUID "+clusterUUID)
+	return s.DeleteAddonDashboardWithContext(context.Background(), clusterUUID)
+}
+
+// DeleteAddonDashboardWithContext is DeleteAddonDashboard with a context that can cancel the call
+func (s *Client) DeleteAddonDashboardWithContext(ctx context.Context, clusterUUID string) (err error) {
+	ctx, span := s.startOperation(ctx, "DeleteAddonDashboard", attribute.String("ccp.cluster.uuid", clusterUUID))
+	defer func() { endOperation(span, err) }()
+	s.log().DebugContext(ctx, "DeleteAddonDashboard", "cluster_uuid", clusterUUID)
 
 	if clusterUUID == "" {
 		return errors.New("Cluster UUID to delete is required")
 	}
 
 	url := s.BaseURL + "/v3/clusters/" + clusterUUID + "/addons/kubernetes-dashboard/"
-	Debug(2, "Sending HTTP delte to "+url)
 
-	req, err := http.NewRequest("DELETE", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
 	if err != nil {
 		return err
 	}
@@ -1397,21 +1480,30 @@ func (s *Client) DeleteAddonDashboard(clusterUUID string) error {
 		return err
 	}
 
-	Debug(2, "Request sent to API with success response")
 	return nil
 }
 
 // DeleteAddonIstio install both
 func (s *Client) DeleteAddonIstio(clusterUUID string) error {
-	err := s.DeleteAddonIstioInstance(clusterUUID)
+	return s.DeleteAddonIstioWithContext(context.Background(), clusterUUID)
+}
+
+// DeleteAddonIstioWithContext is DeleteAddonIstio with a context that can cancel the call
+func (s *Client) DeleteAddonIstioWithContext(ctx context.Context, clusterUUID string) (err error) {
+	ctx, span := s.startOperation(ctx, "DeleteAddonIstio", attribute.String("ccp.cluster.uuid", clusterUUID))
+	defer func() { endOperation(span, err) }()
+	err = s.DeleteAddonIstioInstanceWithContext(ctx, clusterUUID)
 	if err != nil {
-		Debug(1, "Failed to delete Add-On Istio Instance: "+string(err.Error()))
+		s.log().DebugContext(ctx, "failed to delete Add-On Istio Instance", "cluster_uuid", clusterUUID, "error", err)
+		return err
+	}
+	// wait 2 seconds before sending the next request
+	if err := sleepContext(ctx, addonInstallGap); err != nil {
 		return err
 	}
-	time.Sleep(2 * time.Second) // wait 2 seconds before sending the next request
-	err = s.DeleteAddonIstioOp(clusterUUID)
+	err = s.DeleteAddonIstioOpWithContext(ctx, clusterUUID)
 	if err != nil {
-		Debug(1, "Failed to delete Add-On Istio Operator: "+string(err.Error()))
+		s.log().DebugContext(ctx, "failed to delete Add-On Istio Operator", "cluster_uuid", clusterUUID, "error", err)
 		return err
 	}
 	return nil
@@ -1419,16 +1511,22 @@ func (s *Client) DeleteAddonIstio(clusterUUID string) error {
 
 // DeleteAddonHarborInstance deletes the addon
 func (s *Client) DeleteAddonHarborInstance(clusterUUID string) error {
-	Debug(1, "Entered DeleteAddonHarborInstance for UUID "+clusterUUID)
+	return s.DeleteAddonHarborInstanceWithContext(context.Background(), clusterUUID)
+}
+
+// DeleteAddonHarborInstanceWithContext is DeleteAddonHarborInstance with a context that can cancel the call
+func (s *Client) DeleteAddonHarborInstanceWithContext(ctx context.Context, clusterUUID string) (err error) {
+	ctx, span := s.startOperation(ctx, "DeleteAddonHarborInstance", attribute.String("ccp.cluster.uuid", clusterUUID))
+	defer func() { endOperation(span, err) }()
+	s.log().DebugContext(ctx, "DeleteAddonHarborInstance", "cluster_uuid", clusterUUID)
 
 	if clusterUUID == "" {
 		return errors.New("Cluster UUID to delete is required")
 	}
 
 	url := s.BaseURL + "/v3/clusters/" + clusterUUID + "/addons/ccp-harbor-cr/"
-	Debug(2, "Sending HTTP delte to "+url)
 
-	req, err := http.NewRequest("DELETE", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
 	if err != nil {
 		return err
 	}
@@ -1437,22 +1535,27 @@ func (s *Client) DeleteAddonHarborInstance(clusterUUID string) error {
 		return err
 	}
 
-	Debug(2, "Request sent to API with success response")
 	return nil
 }
 
 // DeleteAddonHarborOp deletes the addon
 func (s *Client) DeleteAddonHarborOp(clusterUUID string) error {
-	Debug(1, "Entered DeleteAddonHarborOp for UUID "+clusterUUID)
+	return s.DeleteAddonHarborOpWithContext(context.Background(), clusterUUID)
+}
+
+// DeleteAddonHarborOpWithContext is DeleteAddonHarborOp with a context that can cancel the call
+func (s *Client) DeleteAddonHarborOpWithContext(ctx context.Context, clusterUUID string) (err error) {
+	ctx, span := s.startOperation(ctx, "DeleteAddonHarborOp", attribute.String("ccp.cluster.uuid", clusterUUID))
+	defer func() { endOperation(span, err) }()
+	s.log().DebugContext(ctx, "DeleteAddonHarborOp", "cluster_uuid", clusterUUID)
 
 	if clusterUUID == "" {
 		return errors.New("Cluster UUID to delete is required")
 	}
 
 	url := s.BaseURL + "/v3/clusters/" + clusterUUID + "/addons/ccp-harbor-operator/"
-	Debug(2, "Sending HTTP delte to "+url)
 
-	req, err := http.NewRequest("DELETE", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
 	if err != nil {
 		return err
 	}
@@ -1461,21 +1564,30 @@ func (s *Client) DeleteAddonHarborOp(clusterUUID string) error {
 		return err
 	}
 
-	Debug(2, "Request sent to API with success response")
 	return nil
 }
 
 // DeleteAddonHarbor delete both
 func (s *Client) DeleteAddonHarbor(clusterUUID string) error {
-	err := s.DeleteAddonHarborInstance(clusterUUID)
+	return s.DeleteAddonHarborWithContext(context.Background(), clusterUUID)
+}
+
+// DeleteAddonHarborWithContext is DeleteAddonHarbor with a context that can cancel the call
+func (s *Client) DeleteAddonHarborWithContext(ctx context.Context, clusterUUID string) (err error) {
+	ctx, span := s.startOperation(ctx, "DeleteAddonHarbor", attribute.String("ccp.cluster.uuid", clusterUUID))
+	defer func() { endOperation(span, err) }()
+	err = s.DeleteAddonHarborInstanceWithContext(ctx, clusterUUID)
 	if err != nil {
-		Debug(1, "Failed to delete Add-On Harbor Instance: "+string(err.Error()))
+		s.log().DebugContext(ctx, "failed to delete Add-On Harbor Instance", "cluster_uuid", clusterUUID, "error", err)
 		return err
 	}
-	time.Sleep(2 * time.Second) // wait 2 seconds before sending the next request
-	err = s.DeleteAddonHarborOp(clusterUUID)
+	// wait 2 seconds before sending the next request
+	if err := sleepContext(ctx, addonInstallGap); err != nil {
+		return err
+	}
+	err = s.DeleteAddonHarborOpWithContext(ctx, clusterUUID)
 	if err != nil {
-		Debug(1, "Failed to delete Add-On Harbor Operator: "+string(err.Error()))
+		s.log().DebugContext(ctx, "failed to delete Add-On Harbor Operator", "cluster_uuid", clusterUUID, "error", err)
 		return err
 	}
 	return nil
@@ -1483,12 +1595,19 @@ func (s *Client) DeleteAddonHarbor(clusterUUID string) error {
 
 // GetAddonsCatalogue returns a list of Addons
 func (s *Client) GetAddonsCatalogue(clusterUUID string) (*AddonsCatalogue, error) {
+	return s.GetAddonsCatalogueWithContext(context.Background(), clusterUUID)
+}
+
+// GetAddonsCatalogueWithContext is GetAddonsCatalogue with a context that can cancel the call
+func (s *Client) GetAddonsCatalogueWithContext(ctx context.Context, clusterUUID string) (_ *AddonsCatalogue, err error) {
+	ctx, span := s.startOperation(ctx, "GetAddonsCatalogue", attribute.String("ccp.cluster.uuid", clusterUUID))
+	defer func() { endOperation(span, err) }()
 	// https://mholt.github.io/json-to-go/
-	Debug(3, "GetAddonsCatalogue for cluster "+clusterUUID)
+	s.log().DebugContext(ctx, "GetAddonsCatalogue", "cluster_uuid", clusterUUID)
 
 	url := s.BaseURL + "/v3/clusters/" + clusterUUID + "/catalog"
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -1496,7 +1615,6 @@ func (s *Client) GetAddonsCatalogue(clusterUUID string) (*AddonsCatalogue, error
 	if err != nil {
 		return nil, err
 	}
-	Debug(3, string(bytes))
 	var data *AddonsCatalogue
 
 	err = json.Unmarshal(bytes, &data)
@@ -1509,27 +1627,22 @@ func (s *Client) GetAddonsCatalogue(clusterUUID string) (*AddonsCatalogue, error
 
 // GetClusterInstalledAddons returns a list of Addons
 func (s *Client) GetClusterInstalledAddons(clusterUUID string) (*ClusterInstalledAddons, error) {
-	Debug(3, "GetClusterInstalledAddons for cluster "+clusterUUID)
-
-	url := s.BaseURL + "/v3/clusters/" + clusterUUID + "/addons/"
+	return s.GetClusterInstalledAddonsWithContext(context.Background(), clusterUUID)
+}
 
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, err
-	}
-	bytes, err := s.doRequest(req)
-	if err != nil {
-		return nil, err
-	}
-	Debug(3, string(bytes))
-	var data *ClusterInstalledAddons
+// GetClusterInstalledAddonsWithContext is GetClusterInstalledAddons with a context that can cancel the call
+func (s *Client) GetClusterInstalledAddonsWithContext(ctx context.Context, clusterUUID string) (_ *ClusterInstalledAddons, err error) {
+	ctx, span := s.startOperation(ctx, "GetClusterInstalledAddons", attribute.String("ccp.cluster.uuid", clusterUUID))
+	defer func() { endOperation(span, err) }()
+	s.log().DebugContext(ctx, "GetClusterInstalledAddons", "cluster_uuid", clusterUUID)
 
-	err = json.Unmarshal(bytes, &data)
+	// gather every page into Results, so Next and Previous are always 0
+	addons, err := Collect(paginate[InstalledAddon](ctx, s, "/v3/clusters/"+clusterUUID+"/addons/"))
 	if err != nil {
 		return nil, err
 	}
 
-	return data, nil
+	return &ClusterInstalledAddons{Count: int64(len(addons)), Results: addons}, nil
 }
 
 // err = json.Unmarshal([]byte(jsonBody), &data)
@@ -1556,7 +1669,14 @@ func (s *Client) GetClusterInstalledAddons(clusterUUID string) (*ClusterInstalle
 
 // InstallAddonHXCSI Installs the Istio Operator
 func (s *Client) InstallAddonHXCSI(clusterUUID string) error {
-	Debug(1, "Entered InstallAddonHXCSI for UUID "+clusterUUID)
+	return s.InstallAddonHXCSIWithContext(context.Background(), clusterUUID)
+}
+
+// InstallAddonHXCSIWithContext is InstallAddonHXCSI with a context that can cancel the call
+func (s *Client) InstallAddonHXCSIWithContext(ctx context.Context, clusterUUID string) (err error) {
+	ctx, span := s.startOperation(ctx, "InstallAddonHXCSI", attribute.String("ccp.cluster.uuid", clusterUUID))
+	defer func() { endOperation(span, err) }()
+	s.log().DebugContext(ctx, "InstallAddonHXCSI", "cluster_uuid", clusterUUID)
 
 	if clusterUUID == "" {
 		return errors.New("Cluster UUID is required")
@@ -1564,10 +1684,8 @@ func (s *Client) InstallAddonHXCSI(clusterUUID string) error {
 
 	url := s.BaseURL + "/v3/clusters/" + clusterUUID + "/addons/"
 
-	Debug(2, "Getting Add-Ons catalog for UUID "+clusterUUID)
-	addons, err := s.GetAddonsCatalogue(clusterUUID)
+	addons, err := s.GetAddonsCatalogueWithContext(ctx, clusterUUID)
 	if err != nil {
-		Debug(2, err.Error())
 		return err
 	}
 
@@ -1576,58 +1694,59 @@ func (s *Client) InstallAddonHXCSI(clusterUUID string) error {
 	// now prepare the JSON body
 	jsonBody, err := json.Marshal(addons.CcpHxcsi)
 	if err != nil {
-		fmt.Println(err)
 		return err
 	}
 
-	Debug(2, "POSTing the JSON body:")
-	Debug(2, string(jsonBody))
-
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonBody))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonBody))
 	if err != nil {
 		return err
 	}
 
-	resp, err := s.doRequest(req)
+	_, err = s.doRequest(req)
 	if err != nil {
 		return err
 	}
-	Debug(3, "Response is:")
-	Debug(3, string(resp))
-
-	Debug(2, "Request sent to API with success response")
 	return nil
 }
 
 // DeleteAddonHXCSI deletes the addon
 func (s *Client) DeleteAddonHXCSI(clusterUUID string) error {
-	Debug(1, "Entered DeleteAddonHXCSI for UUID "+clusterUUID)
+	return s.DeleteAddonHXCSIWithContext(context.Background(), clusterUUID)
+}
+
+// DeleteAddonHXCSIWithContext is DeleteAddonHXCSI with a context that can cancel the call
+func (s *Client) DeleteAddonHXCSIWithContext(ctx context.Context, clusterUUID string) (err error) {
+	ctx, span := s.startOperation(ctx, "DeleteAddonHXCSI", attribute.String("ccp.cluster.uuid", clusterUUID))
+	defer func() { endOperation(span, err) }()
+	s.log().DebugContext(ctx, "DeleteAddonHXCSI", "cluster_uuid", clusterUUID)
 
 	if clusterUUID == "" {
 		return errors.New("Cluster UUID to delete is required")
 	}
 
 	url := s.BaseURL + "/v3/clusters/" + clusterUUID + "/addons/ccp-hxcsi/"
-	Debug(2, "Sending HTTP delete to "+url)
 
-	req, err := http.NewRequest("DELETE", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
 	if err != nil {
 		return err
 	}
-	resp, err := s.doRequest(req)
+	_, err = s.doRequest(req)
 	if err != nil {
 		return err
 	}
-	Debug(2, "Response:")
-	Debug(2, string(resp))
-
-	Debug(2, "Request sent to API with success response")
 	return nil
 }
 
 // InstallAddonKubeflow Installs the Istio Operator
 func (s *Client) InstallAddonKubeflow(clusterUUID string) error {
-	Debug(1, "Entered InstallAddonKubeflow for UUID "+clusterUUID)
+	return s.InstallAddonKubeflowWithContext(context.Background(), clusterUUID)
+}
+
+// InstallAddonKubeflowWithContext is InstallAddonKubeflow with a context that can cancel the call
+func (s *Client) InstallAddonKubeflowWithContext(ctx context.Context, clusterUUID string) (err error) {
+	ctx, span := s.startOperation(ctx, "InstallAddonKubeflow", attribute.String("ccp.cluster.uuid", clusterUUID))
+	defer func() { endOperation(span, err) }()
+	s.log().DebugContext(ctx, "InstallAddonKubeflow", "cluster_uuid", clusterUUID)
 
 	if clusterUUID == "" {
 		return errors.New("Cluster UUID is required")
@@ -1635,48 +1754,45 @@ func (s *Client) InstallAddonKubeflow(clusterUUID string) error {
 
 	url := s.BaseURL + "/v3/clusters/" + clusterUUID + "/addons/"
 
-	Debug(2, "Getting Add-Ons catalog for UUID "+clusterUUID)
-	addons, err := s.GetAddonsCatalogue(clusterUUID)
+	addons, err := s.GetAddonsCatalogueWithContext(ctx, clusterUUID)
 	if err != nil {
-		Debug(2, err.Error())
 		return err
 	}
 	jsonBody, err := json.Marshal(addons.CcpKubeflow)
 	if err != nil {
-		fmt.Println(err)
 		return err
 	}
 
-	Debug(2, string(jsonBody))
-
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonBody))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonBody))
 	if err != nil {
 		return err
 	}
 
-	resp, err := s.doRequest(req)
+	_, err = s.doRequest(req)
 	if err != nil {
 		return err
 	}
-	Debug(3, "Response is:")
-	Debug(3, string(resp))
-
-	Debug(2, "Request sent to API with success response")
 	return nil
 }
 
 // DeleteAddonKubeflow deletes the addon
 func (s *Client) DeleteAddonKubeflow(clusterUUID string) error {
-	Debug(1, "Entered DeleteAddonKubeflow for UUID "+clusterUUID)
+	return s.DeleteAddonKubeflowWithContext(context.Background(), clusterUUID)
+}
+
+// DeleteAddonKubeflowWithContext is DeleteAddonKubeflow with a context that can cancel the call
+func (s *Client) DeleteAddonKubeflowWithContext(ctx context.Context, clusterUUID string) (err error) {
+	ctx, span := s.startOperation(ctx, "DeleteAddonKubeflow", attribute.String("ccp.cluster.uuid", clusterUUID))
+	defer func() { endOperation(span, err) }()
+	s.log().DebugContext(ctx, "DeleteAddonKubeflow", "cluster_uuid", clusterUUID)
 
 	if clusterUUID == "" {
 		return errors.New("Cluster UUID to delete is required")
 	}
 
 	url := s.BaseURL + "/v3/clusters/" + clusterUUID + "/addons/ccp-kubeflow/"
-	Debug(2, "Sending HTTP delete to "+url)
 
-	req, err := http.NewRequest("DELETE", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
 	if err != nil {
 		return err
 	}
@@ -1685,12 +1801,19 @@ func (s *Client) DeleteAddonKubeflow(clusterUUID string) error {
 		return err
 	}
 
-	Debug(2, "Request sent to API with success response")
 	return nil
 }
 
 // PatchCluster does the things
 func (s *Client) PatchCluster(cluster *Cluster, clusterUUID string) (*Cluster, error) {
+	return s.PatchClusterWithContext(context.Background(), cluster, clusterUUID)
+}
+
+// PatchClusterWithContext is PatchCluster with a context that can cancel the call
+func (s *Client) PatchClusterWithContext(ctx context.Context, cluster *Cluster, clusterUUID string) (_ *Cluster, err error) {
+	ctx, span := s.startOperation(ctx, "PatchCluster", attribute.String("ccp.cluster.uuid", clusterUUID), clusterNameAttr(cluster))
+	defer func() { endOperation(span, err) }()
+	defer s.cache.forget(cacheClusters, "", clusterUUID)
 
 	var data Cluster
 
@@ -1705,7 +1828,7 @@ func (s *Client) PatchCluster(cluster *Cluster, clusterUUID string) (*Cluster, e
 		return nil, err
 	}
 
-	req, err := http.NewRequest("PATCH", url, bytes.NewBuffer(j))
+	req, err := http.NewRequestWithContext(ctx, "PATCH", url, bytes.NewBuffer(j))
 	if err != nil {
 		return nil, err
 	}
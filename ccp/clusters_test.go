@@ -0,0 +1,113 @@
+/*Copyright (c) 2019 Cisco and/or its affiliates.
+
+This software is licensed to you under the terms of the Cisco Sample
+Code License, Version 1.0 (the "License"). You may obtain a copy of the
+License at
+
+               https://developer.cisco.com/docs/licenses
+
+All use of the material herein must be in accordance with the terms of
+the License. All rights not expressly granted by the License are
+reserved. Unless required by applicable law or agreed to separately in
+writing, software distributed under the License is distributed on an "AS
+IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+or implied.*/
+
+package ccp_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rob-moss/ccp-clientlibrary-go/ccp/ccptest"
+)
+
+// countPolls returns how many times srv was asked for a single cluster
+func countPolls(srv *ccptest.Server) int {
+	n := 0
+	for _, r := range srv.Requests() {
+		if r.Method == http.MethodGet && strings.HasPrefix(r.Path, "/v3/clusters/") {
+			n++
+		}
+	}
+	return n
+}
+
+func TestAddClusterSynchronous(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	srv.CreateDelay = 30 * time.Millisecond
+	client := newTestClient(t, srv)
+
+	c, err := client.AddClusterSynchronous(ccptest.NewCluster("synchronous"))
+	if err != nil {
+		t.Fatalf("AddClusterSynchronous: %v", err)
+	}
+	if stored, ok := srv.Cluster(*c.UUID); !ok || *stored.Status != "READY" {
+		t.Errorf("cluster found %v, %+v once AddClusterSynchronous returns, want READY", ok, stored)
+	}
+	if countPolls(srv) < 2 {
+		t.Errorf("polled %d times, want the cluster polled until it was READY", countPolls(srv))
+	}
+}
+
+func TestAddClusterSynchronousCancelled(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	srv.CreateDelay = time.Hour
+	client := newTestClient(t, srv)
+
+	// cancel once the cluster has been polled a few times
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		for countPolls(srv) < 3 {
+			time.Sleep(time.Millisecond)
+		}
+		cancel()
+	}()
+
+	_, err := client.AddClusterSynchronousWithContext(ctx, ccptest.NewCluster("cancelled"))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("AddClusterSynchronousWithContext error = %v, want context.Canceled", err)
+	}
+
+	polls := countPolls(srv)
+	time.Sleep(50 * time.Millisecond)
+	if n := countPolls(srv); n != polls {
+		t.Errorf("polled %d more times after returning", n-polls)
+	}
+}
+
+func TestAddClusterSynchronousDeadline(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	srv.CreateDelay = time.Hour
+	client := newTestClient(t, srv)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	_, err := client.AddClusterSynchronousWithContext(ctx, ccptest.NewCluster("deadline"))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("AddClusterSynchronousWithContext error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestAddClusterSynchronousCancelledBeforeStart(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	client := newTestClient(t, srv)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.AddClusterSynchronousWithContext(ctx, ccptest.NewCluster("never")); !errors.Is(err, context.Canceled) {
+		t.Fatalf("AddClusterSynchronousWithContext error = %v, want context.Canceled", err)
+	}
+	if n := countRequests(srv, http.MethodPost, "/v3/clusters/"); n != 0 {
+		t.Errorf("POSTed the cluster %d times with a cancelled context, want 0", n)
+	}
+}
@@ -16,21 +16,62 @@ or implied.*/
 package ccp
 
 import (
-	"fmt"
+	"context"
+	"log/slog"
+	"os"
 )
 
-// debug levels
-// var debuglvl int64 = int64(0) // debug off
-// var debuglvl int64 = int64(1) // 1 basic debugging, errors and warnings
-// var debuglvl int64 = int64(2) // 2 medium debugging, above + some data
-// var debuglvl int64 = int64(3) // 3 high debugging, above + all json input/output and structs
+// The library logs through a *slog.Logger set per Client with WithLogger.
+// Without one nothing is logged, the library never writes to stdout itself.
+//
+// Levels used:
+//	slog.LevelWarn  - retries and re-logins
+//	slog.LevelDebug - every request with method, url, status and duration, plus what each call is doing
+//	LevelTrace      - full JSON request and response bodies
 
-var debuglvl = 0
+// LevelTrace is below slog.LevelDebug and carries full JSON payloads
+const LevelTrace = slog.LevelDebug - 4
 
-// Debug messages
-func Debug(level int, errmsg string) {
+var discardLogger = slog.New(slog.DiscardHandler)
+
+// log returns the client's logger, never nil
+func (s *Client) log() *slog.Logger {
+	if l := s.logger.Load(); l != nil {
+		return l
+	}
+	return discardLogger
+}
+
+// debugLevel maps the old numeric debug levels on to slog levels
+//
+//	0 off
+//	1 basic debugging, errors and warnings
+//	2 medium debugging, above + some data
+//	3 high debugging, above + all json input/output and structs
+func debugLevel(level int) slog.Level {
+	if level >= 3 {
+		return LevelTrace
+	}
+	return slog.LevelDebug
+}
 
-	if level <= debuglvl {
-		fmt.Println("Debug: " + errmsg)
+// SetDebug sets the debug level, logging as text to stderr. 0 turns logging off.
+// It is safe to call while requests are in flight, they pick up the new logger as they go.
+//
+// Deprecated: use WithLogger
+func (s *Client) SetDebug(debug int) {
+	if debug <= 0 {
+		s.logger.Store(nil)
+		return
 	}
+	l := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: debugLevel(debug)}))
+	s.logger.Store(l)
+	l.Debug("debug level set", "level", debug)
+}
+
+// Debug messages, logged through slog.Default()
+//
+// Deprecated: use WithLogger and log through your own *slog.Logger
+func Debug(level int, errmsg string) {
+	slog.Default().Log(context.Background(), debugLevel(level), errmsg)
 }
@@ -0,0 +1,151 @@
+/*Copyright (c) 2019 Cisco and/or its affiliates.
+
+This software is licensed to you under the terms of the Cisco Sample
+Code License, Version 1.0 (the "License"). You may obtain a copy of the
+License at
+
+               https://developer.cisco.com/docs/licenses
+
+All use of the material herein must be in accordance with the terms of
+the License. All rights not expressly granted by the License are
+reserved. Unless required by applicable law or agreed to separately in
+writing, software distributed under the License is distributed on an "AS
+IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+or implied.*/
+
+package ccp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Sentinel errors, match them with errors.Is
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrConflict     = errors.New("conflict")
+	ErrValidation   = errors.New("validation failed")
+	ErrUnsupported  = errors.New("not supported")
+)
+
+// ErrorResponse is the JSON error body sent back by the CCP API.
+// Message and Detail carry the human readable error, Fields holds
+// every top level key so per-field validation errors are not lost
+type ErrorResponse struct {
+	Code    *int                   `json:"code,omitempty"`
+	Message *string                `json:"message,omitempty"`
+	Detail  *string                `json:"detail,omitempty"`
+	Fields  map[string]interface{} `json:"-"`
+}
+
+// APIError is returned for any non-2xx response from the CCP API.
+// Body and Response hold the response as sent, Error() masks any secrets in it
+type APIError struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Response   *ErrorResponse // nil when the body is not a JSON object
+	Body       []byte
+	Header     http.Header
+}
+
+// newAPIError builds an APIError, parsing the body if it is a JSON object
+func newAPIError(req *http.Request, resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{
+		Method:     req.Method,
+		URL:        req.URL.String(),
+		StatusCode: resp.StatusCode,
+		Body:       body,
+		Header:     resp.Header,
+	}
+
+	var data ErrorResponse
+	if err := json.Unmarshal(body, &data); err == nil {
+		if err := json.Unmarshal(body, &data.Fields); err == nil {
+			apiErr.Response = &data
+		}
+	}
+
+	return apiErr
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s %s: %d %s: %s", e.Method, e.URL, e.StatusCode, http.StatusText(e.StatusCode), e.message())
+}
+
+// message picks the most useful text out of the error body
+func (e *APIError) message() string {
+	if e.Response != nil {
+		if e.Response.Message != nil && *e.Response.Message != "" {
+			return *e.Response.Message
+		}
+		if e.Response.Detail != nil && *e.Response.Detail != "" {
+			return *e.Response.Detail
+		}
+		if len(e.Response.Fields) > 0 {
+			// validation errors come back keyed by field name.
+			// Decode a fresh copy so masking secrets leaves Response.Fields as sent
+			var masked map[string]interface{}
+			json.Unmarshal(e.Body, &masked)
+			redactValue(masked)
+			var fields []string
+			for k, v := range masked {
+				fields = append(fields, fmt.Sprintf("%s: %v", k, v))
+			}
+			sort.Strings(fields)
+			return strings.Join(fields, "; ")
+		}
+	}
+	return strings.TrimSpace(string(redactJSON(e.Body)))
+}
+
+// Is matches the APIError against the sentinel errors by status code
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrValidation:
+		return e.StatusCode == http.StatusBadRequest || e.StatusCode == http.StatusUnprocessableEntity
+	}
+	return false
+}
+
+// AuthError is returned by Login when the control plane does not hand out a session,
+// such as for a wrong password. It matches ErrUnauthorized when the login was refused
+type AuthError struct {
+	Username string
+	URL      string
+	Reason   string
+	Err      error // the *APIError for a non-2xx response, nil otherwise
+}
+
+// Error implements the error interface
+func (e *AuthError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("login as %s to %s failed: %s: %v", e.Username, e.URL, e.Reason, e.Err)
+	}
+	return fmt.Sprintf("login as %s to %s failed: %s", e.Username, e.URL, e.Reason)
+}
+
+// Unwrap returns the APIError, so errors.As and the status code sentinels see it
+func (e *AuthError) Unwrap() error {
+	return e.Err
+}
+
+// Is matches ErrUnauthorized for a login that succeeded without a token
+func (e *AuthError) Is(target error) bool {
+	return target == ErrUnauthorized && e.Err == nil
+}
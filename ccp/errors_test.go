@@ -0,0 +1,113 @@
+/*Copyright (c) 2019 Cisco and/or its affiliates.
+
+This software is licensed to you under the terms of the Cisco Sample
+Code License, Version 1.0 (the "License"). You may obtain a copy of the
+License at
+
+               https://developer.cisco.com/docs/licenses
+
+All use of the material herein must be in accordance with the terms of
+the License. All rights not expressly granted by the License are
+reserved. Unless required by applicable law or agreed to separately in
+writing, software distributed under the License is distributed on an "AS
+IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+or implied.*/
+
+package ccp_test
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/rob-moss/ccp-clientlibrary-go/ccp"
+	"github.com/rob-moss/ccp-clientlibrary-go/ccp/ccptest"
+)
+
+func TestAPIErrorSentinels(t *testing.T) {
+	sentinels := []error{ccp.ErrNotFound, ccp.ErrUnauthorized, ccp.ErrForbidden, ccp.ErrConflict, ccp.ErrValidation}
+	tests := []struct {
+		status int
+		want   error // nil when no sentinel matches
+	}{
+		{http.StatusBadRequest, ccp.ErrValidation},
+		{http.StatusUnauthorized, ccp.ErrUnauthorized},
+		{http.StatusForbidden, ccp.ErrForbidden},
+		{http.StatusNotFound, ccp.ErrNotFound},
+		{http.StatusConflict, ccp.ErrConflict},
+		{http.StatusUnprocessableEntity, ccp.ErrValidation},
+		{http.StatusInternalServerError, nil},
+		{http.StatusServiceUnavailable, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			srv := ccptest.NewServer()
+			defer srv.Close()
+			client := newTestClient(t, srv, ccp.WithRetryPolicy(nil))
+
+			srv.AddFault(ccptest.Fault{Method: http.MethodGet, Path: "/v3/clusters", Status: tt.status, Body: `{"message": "refused"}`})
+			_, err := client.GetClusters()
+
+			var apiErr *ccp.APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("GetClusters error = %v, want an *ccp.APIError", err)
+			}
+			if apiErr.StatusCode != tt.status || apiErr.Method != http.MethodGet || !strings.HasSuffix(apiErr.URL, "/v3/clusters") {
+				t.Errorf("APIError = %s %s %d, want GET .../v3/clusters %d", apiErr.Method, apiErr.URL, apiErr.StatusCode, tt.status)
+			}
+			if apiErr.Response == nil || apiErr.Response.Message == nil || *apiErr.Response.Message != "refused" {
+				t.Errorf("APIError.Response = %+v, want the parsed message", apiErr.Response)
+			}
+			for _, sentinel := range sentinels {
+				if got := errors.Is(err, sentinel); got != (sentinel == tt.want) {
+					t.Errorf("errors.Is(err, %v) = %v", sentinel, got)
+				}
+			}
+		})
+	}
+}
+
+func TestAPIErrorFromControlPlane(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	client := newTestClient(t, srv, ccp.WithRetryPolicy(nil))
+
+	// answered by the fake control plane itself, no fault injected
+	_, err := client.GetClusterByUUID("no-such-cluster")
+	var apiErr *ccp.APIError
+	if !errors.Is(err, ccp.ErrNotFound) || !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("GetClusterByUUID of a missing cluster error = %v, want a 404 APIError matching ErrNotFound", err)
+	}
+
+	if _, err := client.AddCluster(ccptest.NewCluster("taken")); err != nil {
+		t.Fatalf("AddCluster: %v", err)
+	}
+	if _, err := client.AddCluster(ccptest.NewCluster("taken")); !errors.Is(err, ccp.ErrConflict) || !errors.As(err, &apiErr) {
+		t.Errorf("AddCluster with a taken name error = %v, want an APIError matching ErrConflict", err)
+	}
+}
+
+func TestAuthErrorSentinels(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+
+	client, err := ccp.NewClient(srv.URL, ccp.WithCredentials(ccptest.Username, "wrong"))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	err = client.Login(client)
+
+	var authErr *ccp.AuthError
+	var apiErr *ccp.APIError
+	if !errors.As(err, &authErr) || authErr.Username != ccptest.Username {
+		t.Fatalf("Login error = %v, want an *ccp.AuthError for %s", err, ccptest.Username)
+	}
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Login error = %v, want it to wrap the 401 APIError", err)
+	}
+	if !errors.Is(err, ccp.ErrUnauthorized) {
+		t.Errorf("Login error = %v, want it to match ErrUnauthorized", err)
+	}
+}
@@ -0,0 +1,40 @@
+/*Copyright (c) 2019 Cisco and/or its affiliates.
+
+This software is licensed to you under the terms of the Cisco Sample
+Code License, Version 1.0 (the "License"). You may obtain a copy of the
+License at
+
+               https://developer.cisco.com/docs/licenses
+
+All use of the material herein must be in accordance with the terms of
+the License. All rights not expressly granted by the License are
+reserved. Unless required by applicable law or agreed to separately in
+writing, software distributed under the License is distributed on an "AS
+IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+or implied.*/
+
+package ccp
+
+import "time"
+
+// the tests run against ccptest, which doesn't need the waits a real control plane does
+func init() {
+	clusterPollInterval = 5 * time.Millisecond
+	addonInstallGap = time.Millisecond
+}
+
+// SecretKeys are the JSON keys the client masks, so the tests can cover every one
+var SecretKeys = secretKeys
+
+// SetProbeFailureTTL changes how long a failed API version probe is kept, until the returned restore is called
+func SetProbeFailureTTL(d time.Duration) (restore func()) {
+	old := probeFailureTTL
+	probeFailureTTL = d
+	return func() { probeFailureTTL = old }
+}
+
+// SetCacheClock makes the lookup cache tell the time with now, until the returned restore is called
+func SetCacheClock(now func() time.Time) (restore func()) {
+	cacheNow = now
+	return func() { cacheNow = time.Now }
+}
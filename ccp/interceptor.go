@@ -0,0 +1,96 @@
+/*Copyright (c) 2019 Cisco and/or its affiliates.
+
+This software is licensed to you under the terms of the Cisco Sample
+Code License, Version 1.0 (the "License"). You may obtain a copy of the
+License at
+
+               https://developer.cisco.com/docs/licenses
+
+All use of the material herein must be in accordance with the terms of
+the License. All rights not expressly granted by the License are
+reserved. Unless required by applicable law or agreed to separately in
+writing, software distributed under the License is distributed on an "AS
+IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+or implied.*/
+
+package ccp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// RoundTripFunc sends one HTTP request to the control plane and returns its response
+type RoundTripFunc func(req *http.Request) (*http.Response, error)
+
+// Interceptor wraps the RoundTripFunc that sends every request, Login included.
+// It can change the request before calling next and inspect or replace the response after.
+// Retries and re-logins go through the chain again, once per HTTP request
+type Interceptor func(next RoundTripFunc) RoundTripFunc
+
+// operationKey holds the API operation name in a request context
+type operationKey struct{}
+
+// withOperation names the API call, such as "GetClusters", that ctx belongs to
+func withOperation(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, operationKey{}, name)
+}
+
+// Operation returns the name of the API call that sent a request, such as "GetClusters" or "Login".
+// Interceptors call it with req.Context(). It is empty outside the library's own calls
+func Operation(ctx context.Context) string {
+	name, _ := ctx.Value(operationKey{}).(string)
+	return name
+}
+
+// WithInterceptors adds interceptors to the client. The first one given is the outermost,
+// it sees the request first and the response last
+func WithInterceptors(interceptors ...Interceptor) Option {
+	return func(s *Client) error {
+		s.interceptors = append(s.interceptors, interceptors...)
+		return nil
+	}
+}
+
+// roundTrip sends req through the interceptor chain and then the pooled http.Client,
+// once the rate and in-flight limits allow, inside the request's HTTP span
+// and counted in the client's metrics
+func (s *Client) roundTrip(req *http.Request) (*http.Response, error) {
+	// wait for the rate limit and a free slot before the clock starts
+	release, err := s.acquire(req)
+	if err != nil {
+		return nil, err
+	}
+
+	req, span := s.startHTTPSpan(req)
+	start := time.Now()
+
+	next := RoundTripFunc(s.getHTTPClient().Do)
+	for i := len(s.interceptors) - 1; i >= 0; i-- {
+		next = s.interceptors[i](next)
+	}
+	resp, err := next(req)
+	if err == nil && resp == nil {
+		err = fmt.Errorf("%s %s: an interceptor returned neither a response nor an error", req.Method, req.URL)
+	}
+	if resp != nil && resp.Body == nil {
+		resp.Body = http.NoBody
+	}
+
+	status := 0
+	if resp != nil {
+		status = resp.StatusCode
+	}
+	s.meter().ObserveRequest(Operation(req.Context()), req.Method, status, err, time.Since(start))
+	endHTTPSpan(span, resp, err)
+
+	// the slot stays taken until the response body has been read and closed
+	if err != nil {
+		release()
+		return nil, err
+	}
+	resp.Body = &releaseOnClose{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
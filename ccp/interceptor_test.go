@@ -0,0 +1,179 @@
+/*Copyright (c) 2019 Cisco and/or its affiliates.
+
+This software is licensed to you under the terms of the Cisco Sample
+Code License, Version 1.0 (the "License"). You may obtain a copy of the
+License at
+
+               https://developer.cisco.com/docs/licenses
+
+All use of the material herein must be in accordance with the terms of
+the License. All rights not expressly granted by the License are
+reserved. Unless required by applicable law or agreed to separately in
+writing, software distributed under the License is distributed on an "AS
+IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+or implied.*/
+
+package ccp_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/rob-moss/ccp-clientlibrary-go/ccp"
+	"github.com/rob-moss/ccp-clientlibrary-go/ccp/ccptest"
+)
+
+// trace records what the interceptors saw, in order
+type trace struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (tr *trace) add(event string) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	tr.events = append(tr.events, event)
+}
+
+func (tr *trace) String() string {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	return strings.Join(tr.events, " ")
+}
+
+// named records name before and after the rest of the chain, for cluster lists only
+func named(tr *trace, name string) ccp.Interceptor {
+	return func(next ccp.RoundTripFunc) ccp.RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			if req.URL.Path != "/v3/clusters" {
+				return next(req)
+			}
+			tr.add(name + ">")
+			resp, err := next(req)
+			tr.add("<" + name)
+			return resp, err
+		}
+	}
+}
+
+func TestInterceptorOrder(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+
+	tr := &trace{}
+	client := newTestClient(t, srv, ccp.WithInterceptors(named(tr, "a"), named(tr, "b")), ccp.WithInterceptors(named(tr, "c")))
+	if _, err := client.GetClusters(); err != nil {
+		t.Fatalf("GetClusters: %v", err)
+	}
+	if got, want := tr.String(), "a> b> c> <c <b <a"; got != want {
+		t.Errorf("interceptors ran %q, want %q", got, want)
+	}
+}
+
+func TestInterceptorOperation(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+
+	tr := &trace{}
+	operations := func(next ccp.RoundTripFunc) ccp.RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			tr.add(req.Method + " " + req.URL.Path + "=" + ccp.Operation(req.Context()))
+			return next(req)
+		}
+	}
+	client := newTestClient(t, srv, ccp.WithInterceptors(operations))
+	if _, err := client.GetClusters(); err != nil {
+		t.Fatalf("GetClusters: %v", err)
+	}
+
+	events := tr.String()
+	for _, want := range []string{"POST /v3/system/login=Login", "GET /v3/clusters=GetClusters"} {
+		if !strings.Contains(events, want) {
+			t.Errorf("interceptor saw %q, want %q", events, want)
+		}
+	}
+	if got := ccp.Operation(context.Background()); got != "" {
+		t.Errorf("Operation outside a call = %q, want empty", got)
+	}
+}
+
+func TestInterceptorShortCircuit(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+
+	// answers cluster lists itself, the control plane never sees them
+	canned := func(next ccp.RoundTripFunc) ccp.RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			if req.URL.Path != "/v3/clusters" {
+				return next(req)
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": {"application/json"}},
+				Body:       io.NopCloser(strings.NewReader(`[{"name": "canned"}]`)),
+				Request:    req,
+			}, nil
+		}
+	}
+	client := newTestClient(t, srv, ccp.WithInterceptors(canned))
+
+	clusters, err := client.GetClusters()
+	if err != nil {
+		t.Fatalf("GetClusters: %v", err)
+	}
+	if len(clusters) != 1 || *clusters[0].Name != "canned" {
+		t.Errorf("GetClusters = %v, want the canned cluster", clusters)
+	}
+	if n := countRequests(srv, http.MethodGet, "/v3/clusters"); n != 0 {
+		t.Errorf("control plane got %d cluster lists, want 0", n)
+	}
+}
+
+func TestInterceptorError(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+
+	blocked := errors.New("blocked by policy")
+	deny := func(next ccp.RoundTripFunc) ccp.RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			if req.Method == http.MethodDelete {
+				return nil, blocked
+			}
+			return next(req)
+		}
+	}
+	client := newTestClient(t, srv, ccp.WithInterceptors(deny), ccp.WithRetryPolicy(nil))
+	c := addTestCluster(srv)
+
+	if err := client.DeleteCluster(*c.UUID); !errors.Is(err, blocked) {
+		t.Fatalf("DeleteCluster error = %v, want the interceptor's error", err)
+	}
+	if _, ok := srv.Cluster(*c.UUID); !ok {
+		t.Error("the cluster was deleted")
+	}
+}
+
+func TestInterceptorNilResponse(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+
+	broken := func(next ccp.RoundTripFunc) ccp.RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			if req.URL.Path == "/v3/clusters" {
+				return nil, nil
+			}
+			return next(req)
+		}
+	}
+	client := newTestClient(t, srv, ccp.WithInterceptors(broken), ccp.WithRetryPolicy(nil))
+
+	_, err := client.GetClusters()
+	if err == nil || !strings.Contains(err.Error(), "neither a response nor an error") {
+		t.Fatalf("GetClusters error = %v, want an error for the missing response", err)
+	}
+}
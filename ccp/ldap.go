@@ -0,0 +1,200 @@
+/*Copyright (c) 2019 Cisco and/or its affiliates.
+
+This software is licensed to you under the terms of the Cisco Sample
+Code License, Version 1.0 (the "License"). You may obtain a copy of the
+License at
+
+               https://developer.cisco.com/docs/licenses
+
+All use of the material herein must be in accordance with the terms of
+the License. All rights not expressly granted by the License are
+reserved. Unless required by applicable law or agreed to separately in
+writing, software distributed under the License is distributed on an "AS
+IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+or implied.*/
+
+package ccp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// LDAPSetup is the control plane's LDAP or Active Directory integration
+type LDAPSetup struct {
+	Server                 *string `json:"server,omitempty"`
+	Port                   *int64  `json:"port,omitempty"`
+	BaseDN                 *string `json:"baseDN,omitempty"`
+	ServiceAccountDN       *string `json:"serviceAccountDN,omitempty"`       // the bind user
+	ServiceAccountPassword *string `json:"serviceAccountPassword,omitempty"` // only sent, never returned
+	GroupSearchBaseDN      *string `json:"groupSearchBaseDN,omitempty"`
+	GroupSearchFilter      *string `json:"groupSearchFilter,omitempty"`
+	StartTLS               *bool   `json:"startTLS,omitempty"`
+	InsecureSkipVerify     *bool   `json:"insecureSkipVerify,omitempty"`
+	CACert                 *string `json:"caCert,omitempty"` // PEM bundle to verify the LDAP server with
+}
+
+// LDAPTestResult is the outcome of the control plane connecting and binding to the LDAP server
+type LDAPTestResult struct {
+	Connected *bool   `json:"connected,omitempty"`
+	Message   *string `json:"message,omitempty"`
+}
+
+// OK reports whether the control plane could connect and bind
+func (r *LDAPTestResult) OK() bool {
+	return r != nil && r.Connected != nil && *r.Connected
+}
+
+// validLDAPSetup checks the fields a new LDAP setup needs, and the port of any setup
+func validLDAPSetup(setup *LDAPSetup, complete bool) error {
+	if setup == nil {
+		return fmt.Errorf("LDAPSetup is missing: %w", ErrValidation)
+	}
+	if complete && nonzero(setup.Server) {
+		return fmt.Errorf("LDAPSetup.Server is missing: %w", ErrValidation)
+	}
+	if complete && nonzero(setup.BaseDN) {
+		return fmt.Errorf("LDAPSetup.BaseDN is missing: %w", ErrValidation)
+	}
+	if setup.Port != nil && (*setup.Port < 1 || *setup.Port > 65535) {
+		return fmt.Errorf("LDAPSetup.Port %d is out of range: %w", *setup.Port, ErrValidation)
+	}
+	return nil
+}
+
+// GetLDAPSetup gets the LDAP setup. It fails with ErrNotFound when LDAP isn't set up
+func (s *Client) GetLDAPSetup() (*LDAPSetup, error) {
+	return s.GetLDAPSetupWithContext(context.Background())
+}
+
+// GetLDAPSetupWithContext is GetLDAPSetup with a context that can cancel the call
+func (s *Client) GetLDAPSetupWithContext(ctx context.Context) (_ *LDAPSetup, err error) {
+	ctx, span := s.startOperation(ctx, "GetLDAPSetup")
+	defer func() { endOperation(span, err) }()
+
+	var data LDAPSetup
+	if err := s.sendLDAP(ctx, "GET", "/v3/ldap/", nil, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// SetLDAPSetup sets up LDAP, replacing any earlier setup. Server and BaseDN are required
+func (s *Client) SetLDAPSetup(setup *LDAPSetup) (*LDAPSetup, error) {
+	return s.SetLDAPSetupWithContext(context.Background(), setup)
+}
+
+// SetLDAPSetupWithContext is SetLDAPSetup with a context that can cancel the call
+func (s *Client) SetLDAPSetupWithContext(ctx context.Context, setup *LDAPSetup) (_ *LDAPSetup, err error) {
+	ctx, span := s.startOperation(ctx, "SetLDAPSetup", ldapServerAttr(setup))
+	defer func() { endOperation(span, err) }()
+
+	if err := validLDAPSetup(setup, true); err != nil {
+		return nil, err
+	}
+
+	var data LDAPSetup
+	if err := s.sendLDAP(ctx, "POST", "/v3/ldap/", setup, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// UpdateLDAPSetup changes the fields that are set in setup and keeps the others
+func (s *Client) UpdateLDAPSetup(setup *LDAPSetup) (*LDAPSetup, error) {
+	return s.UpdateLDAPSetupWithContext(context.Background(), setup)
+}
+
+// UpdateLDAPSetupWithContext is UpdateLDAPSetup with a context that can cancel the call
+func (s *Client) UpdateLDAPSetupWithContext(ctx context.Context, setup *LDAPSetup) (_ *LDAPSetup, err error) {
+	ctx, span := s.startOperation(ctx, "UpdateLDAPSetup", ldapServerAttr(setup))
+	defer func() { endOperation(span, err) }()
+
+	if err := validLDAPSetup(setup, false); err != nil {
+		return nil, err
+	}
+
+	var data LDAPSetup
+	if err := s.sendLDAP(ctx, "PATCH", "/v3/ldap/", setup, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// DeleteLDAPSetup removes the LDAP setup, LDAP users can no longer log in
+func (s *Client) DeleteLDAPSetup() error {
+	return s.DeleteLDAPSetupWithContext(context.Background())
+}
+
+// DeleteLDAPSetupWithContext is DeleteLDAPSetup with a context that can cancel the call
+func (s *Client) DeleteLDAPSetupWithContext(ctx context.Context) (err error) {
+	ctx, span := s.startOperation(ctx, "DeleteLDAPSetup")
+	defer func() { endOperation(span, err) }()
+
+	return s.sendLDAP(ctx, "DELETE", "/v3/ldap/", nil, nil)
+}
+
+// TestLDAPSetup has the control plane connect and bind to the LDAP server. A nil
+// setup tests the saved one, otherwise setup is tested without being saved. A server
+// that can't be reached is reported in the result, not as an error
+func (s *Client) TestLDAPSetup(setup *LDAPSetup) (*LDAPTestResult, error) {
+	return s.TestLDAPSetupWithContext(context.Background(), setup)
+}
+
+// TestLDAPSetupWithContext is TestLDAPSetup with a context that can cancel the call
+func (s *Client) TestLDAPSetupWithContext(ctx context.Context, setup *LDAPSetup) (_ *LDAPTestResult, err error) {
+	ctx, span := s.startOperation(ctx, "TestLDAPSetup", ldapServerAttr(setup))
+	defer func() { endOperation(span, err) }()
+
+	var body interface{} // a nil *LDAPSetup would be sent as null
+	if setup != nil {
+		if err := validLDAPSetup(setup, true); err != nil {
+			return nil, err
+		}
+		body = setup
+	}
+
+	var data LDAPTestResult
+	if err := s.sendLDAP(ctx, "POST", "/v3/ldap/test/", body, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// sendLDAP sends body, if any, as JSON to one of the /v3/ldap endpoints and decodes the answer into v, if any
+func (s *Client) sendLDAP(ctx context.Context, method, path string, body, v interface{}) error {
+	var j []byte
+	if body != nil {
+		var err error
+		if j, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, s.BaseURL+path, bytes.NewReader(j))
+	if err != nil {
+		return err
+	}
+	bytes, err := s.doRequest(req)
+	if err != nil {
+		return err
+	}
+	if v == nil || len(bytes) == 0 {
+		return nil
+	}
+	return json.Unmarshal(bytes, v)
+}
+
+// ldapServerAttr puts the LDAP server on the operation span
+func ldapServerAttr(setup *LDAPSetup) attribute.KeyValue {
+	server := ""
+	if setup != nil && setup.Server != nil {
+		server = *setup.Server
+	}
+	return attribute.String("ccp.ldap.server", server)
+}
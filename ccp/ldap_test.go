@@ -0,0 +1,192 @@
+/*Copyright (c) 2019 Cisco and/or its affiliates.
+
+This software is licensed to you under the terms of the Cisco Sample
+Code License, Version 1.0 (the "License"). You may obtain a copy of the
+License at
+
+               https://developer.cisco.com/docs/licenses
+
+All use of the material herein must be in accordance with the terms of
+the License. All rights not expressly granted by the License are
+reserved. Unless required by applicable law or agreed to separately in
+writing, software distributed under the License is distributed on an "AS
+IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+or implied.*/
+
+package ccp_test
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/rob-moss/ccp-clientlibrary-go/ccp"
+	"github.com/rob-moss/ccp-clientlibrary-go/ccp/ccptest"
+)
+
+// newLDAPSetup returns a complete setup for ldap.example.com
+func newLDAPSetup() *ccp.LDAPSetup {
+	return &ccp.LDAPSetup{
+		Server:                 ccp.String("ldap.example.com"),
+		Port:                   ccp.Int64(636),
+		BaseDN:                 ccp.String("dc=example,dc=com"),
+		ServiceAccountDN:       ccp.String("cn=ccp,dc=example,dc=com"),
+		ServiceAccountPassword: ccp.String("bind-secret"),
+	}
+}
+
+func TestLDAPSetupValidation(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	client := newTestClient(t, srv)
+
+	withoutServer, withoutBaseDN, badPort := newLDAPSetup(), newLDAPSetup(), newLDAPSetup()
+	withoutServer.Server = nil
+	withoutBaseDN.BaseDN = ccp.String("")
+	badPort.Port = ccp.Int64(70000)
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{"SetLDAPSetup nil", func() error { _, err := client.SetLDAPSetup(nil); return err }},
+		{"SetLDAPSetup without a server", func() error { _, err := client.SetLDAPSetup(withoutServer); return err }},
+		{"SetLDAPSetup without a base DN", func() error { _, err := client.SetLDAPSetup(withoutBaseDN); return err }},
+		{"SetLDAPSetup with port 70000", func() error { _, err := client.SetLDAPSetup(badPort); return err }},
+		{"UpdateLDAPSetup nil", func() error { _, err := client.UpdateLDAPSetup(nil); return err }},
+		{"UpdateLDAPSetup with port 0", func() error {
+			_, err := client.UpdateLDAPSetup(&ccp.LDAPSetup{Port: ccp.Int64(0)})
+			return err
+		}},
+		{"TestLDAPSetup without a server", func() error { _, err := client.TestLDAPSetup(withoutServer); return err }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); !errors.Is(err, ccp.ErrValidation) {
+				t.Errorf("error = %v, want ErrValidation", err)
+			}
+		})
+	}
+
+	if n := len(srv.Requests()); n != 0 {
+		t.Errorf("control plane got %d requests for invalid setups, want none", n)
+	}
+}
+
+func TestLDAPSetup(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	client := newTestClient(t, srv)
+
+	if _, err := client.GetLDAPSetup(); !errors.Is(err, ccp.ErrNotFound) {
+		t.Fatalf("GetLDAPSetup before any setup error = %v, want ErrNotFound", err)
+	}
+	setup, err := client.SetLDAPSetup(newLDAPSetup())
+	if err != nil {
+		t.Fatalf("SetLDAPSetup: %v", err)
+	}
+	if *setup.Server != "ldap.example.com" || setup.ServiceAccountPassword != nil {
+		t.Errorf("SetLDAPSetup = %+v, want the setup without its password", setup)
+	}
+	if stored, _ := srv.LDAPSetup(); stored.ServiceAccountPassword == nil || *stored.ServiceAccountPassword != "bind-secret" {
+		t.Error("the bind password did not reach the control plane")
+	}
+
+	// an update keeps the fields it leaves out
+	if _, err := client.UpdateLDAPSetup(&ccp.LDAPSetup{StartTLS: ccp.Bool(true)}); err != nil {
+		t.Fatalf("UpdateLDAPSetup: %v", err)
+	}
+	got, err := client.GetLDAPSetup()
+	if err != nil {
+		t.Fatalf("GetLDAPSetup: %v", err)
+	}
+	if got.StartTLS == nil || !*got.StartTLS || *got.BaseDN != "dc=example,dc=com" {
+		t.Errorf("GetLDAPSetup = %+v, want StartTLS set and the base DN kept", got)
+	}
+
+	if err := client.DeleteLDAPSetup(); err != nil {
+		t.Fatalf("DeleteLDAPSetup: %v", err)
+	}
+	if _, ok := srv.LDAPSetup(); ok {
+		t.Error("the LDAP setup was not deleted")
+	}
+}
+
+func TestTestLDAPSetup(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	client := newTestClient(t, srv)
+
+	// a setup given is tested without being saved
+	result, err := client.TestLDAPSetup(newLDAPSetup())
+	if err != nil {
+		t.Fatalf("TestLDAPSetup: %v", err)
+	}
+	if !result.OK() {
+		t.Errorf("TestLDAPSetup = %+v, want it connected", result)
+	}
+	if _, ok := srv.LDAPSetup(); ok {
+		t.Error("TestLDAPSetup saved the setup")
+	}
+
+	// nil tests the saved setup, and sends no body
+	if _, err := client.TestLDAPSetup(nil); !errors.Is(err, ccp.ErrNotFound) {
+		t.Errorf("TestLDAPSetup(nil) with nothing saved error = %v, want ErrNotFound", err)
+	}
+	if _, err := client.SetLDAPSetup(newLDAPSetup()); err != nil {
+		t.Fatalf("SetLDAPSetup: %v", err)
+	}
+	srv.ResetRequests()
+	if result, err := client.TestLDAPSetup(nil); err != nil || !result.OK() {
+		t.Errorf("TestLDAPSetup(nil) = %+v, %v, want the saved setup connected", result, err)
+	}
+	for _, r := range srv.Requests() {
+		if r.Path == "/v3/ldap/test/" && len(r.Body) != 0 {
+			t.Errorf("TestLDAPSetup(nil) sent %q, want no body", r.Body)
+		}
+	}
+}
+
+func TestTestLDAPSetupNotConnected(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	client := newTestClient(t, srv)
+
+	srv.AddFault(ccptest.Fault{Method: http.MethodPost, Path: "/v3/ldap/test", Status: http.StatusOK, Body: `{"connected": false, "message": "connection refused"}`})
+	result, err := client.TestLDAPSetup(newLDAPSetup())
+	if err != nil {
+		t.Fatalf("TestLDAPSetup of an unreachable server error = %v, want it in the result", err)
+	}
+	if result.OK() || result.Message == nil || *result.Message != "connection refused" {
+		t.Errorf("TestLDAPSetup = %+v, want not connected with the reason", result)
+	}
+	if (*ccp.LDAPTestResult)(nil).OK() {
+		t.Error("a nil result is OK")
+	}
+}
+
+func TestLDAPSetupRejected(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	client := newTestClient(t, srv, ccp.WithRetryPolicy(nil))
+
+	srv.AddFault(ccptest.Fault{Method: http.MethodPost, Path: "/v3/ldap", Status: http.StatusBadRequest, Body: `{"message": "caCert: not a PEM certificate"}`})
+	setup := newLDAPSetup()
+	setup.CACert = ccp.String("not a certificate")
+	_, err := client.SetLDAPSetup(setup)
+
+	var apiErr *ccp.APIError
+	if !errors.Is(err, ccp.ErrValidation) || !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		t.Fatalf("SetLDAPSetup error = %v, want a 400 APIError matching ErrValidation", err)
+	}
+	if !strings.Contains(err.Error(), "not a PEM certificate") {
+		t.Errorf("SetLDAPSetup error = %v, want the control plane's reason", err)
+	}
+	if strings.Contains(err.Error(), "bind-secret") {
+		t.Errorf("SetLDAPSetup error = %v, shows the bind password", err)
+	}
+	if _, ok := srv.LDAPSetup(); ok {
+		t.Error("a rejected setup was saved")
+	}
+}
@@ -0,0 +1,56 @@
+/*Copyright (c) 2019 Cisco and/or its affiliates.
+
+This software is licensed to you under the terms of the Cisco Sample
+Code License, Version 1.0 (the "License"). You may obtain a copy of the
+License at
+
+               https://developer.cisco.com/docs/licenses
+
+All use of the material herein must be in accordance with the terms of
+the License. All rights not expressly granted by the License are
+reserved. Unless required by applicable law or agreed to separately in
+writing, software distributed under the License is distributed on an "AS
+IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+or implied.*/
+
+package ccp
+
+import "time"
+
+// MetricsRecorder is told about every HTTP request, Login included, every login made
+// because the X-Auth-Token expired, and every status poll. Set one with WithMetrics.
+// The ccpprom package has one that keeps Prometheus metrics, so the core library
+// doesn't depend on Prometheus
+type MetricsRecorder interface {
+	// ObserveRequest is called once an HTTP request is done. operation is the API call,
+	// such as GetClusters. status is 0 when err is set and no response came back
+	ObserveRequest(operation, method string, status int, err error, took time.Duration)
+	// ObserveTokenRefresh is called after a login made because the token expired
+	ObserveTokenRefresh()
+	// ObservePoll is called for every status poll made by operation
+	ObservePoll(operation string)
+}
+
+// WithMetrics records the client's requests, token refreshes and polls in m.
+// One MetricsRecorder can be shared by many clients
+func WithMetrics(m MetricsRecorder) Option {
+	return func(s *Client) error {
+		s.metrics = m
+		return nil
+	}
+}
+
+// noMetrics records nothing, for clients without WithMetrics
+type noMetrics struct{}
+
+func (noMetrics) ObserveRequest(string, string, int, error, time.Duration) {}
+func (noMetrics) ObserveTokenRefresh()                                     {}
+func (noMetrics) ObservePoll(string)                                       {}
+
+// meter returns the client's MetricsRecorder, never nil
+func (s *Client) meter() MetricsRecorder {
+	if s.metrics == nil {
+		return noMetrics{}
+	}
+	return s.metrics
+}
@@ -0,0 +1,161 @@
+/*Copyright (c) 2019 Cisco and/or its affiliates.
+
+This software is licensed to you under the terms of the Cisco Sample
+Code License, Version 1.0 (the "License"). You may obtain a copy of the
+License at
+
+               https://developer.cisco.com/docs/licenses
+
+All use of the material herein must be in accordance with the terms of
+the License. All rights not expressly granted by the License are
+reserved. Unless required by applicable law or agreed to separately in
+writing, software distributed under the License is distributed on an "AS
+IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+or implied.*/
+
+package ccp
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Option configures a Client in NewClient
+type Option func(*Client) error
+
+// NewClient returns a Client for the control plane at baseURL, configured by opts.
+// Server certificates are verified unless WithInsecureSkipVerify or WithPinnedCertificate is given
+func NewClient(baseURL string, opts ...Option) (*Client, error) {
+
+	s := &Client{
+		BaseURL:     baseURL,
+		RetryPolicy: DefaultRetryPolicy(),
+	}
+
+	for _, opt := range opts {
+		if err := opt(s); err != nil {
+			return nil, err
+		}
+	}
+
+	// build the pooled http.Client now that every option is in
+	s.getHTTPClient()
+
+	return s, nil
+}
+
+// NewInsecureClient is the original three argument constructor. It skips TLS
+// verification, as the library always did, since most CCP instances use self-signed certs.
+//
+// Deprecated: use NewClient with WithCredentials, and WithInsecureSkipVerify if needed
+func NewInsecureClient(username, password, baseURL string) *Client {
+
+	s, _ := NewClient(baseURL, WithCredentials(username, password), WithInsecureSkipVerify())
+	return s
+}
+
+// WithCredentials sets the username and password used by Login
+func WithCredentials(username, password string) Option {
+	return func(s *Client) error {
+		s.Username = username
+		s.Password = password
+		return nil
+	}
+}
+
+// WithHTTPClient sends every request with hc instead of the client's own pooled http.Client.
+// The TLS, proxy, timeout and pool options have no effect when it is used
+func WithHTTPClient(hc *http.Client) Option {
+	return func(s *Client) error {
+		if hc == nil {
+			return errors.New("nil http.Client")
+		}
+		s.httpClient = hc
+		return nil
+	}
+}
+
+// WithTransport sends every request through rt instead of the default pooled http.Transport.
+// The TLS, proxy and pool options have no effect when it is used
+func WithTransport(rt http.RoundTripper) Option {
+	return func(s *Client) error {
+		if rt == nil {
+			return errors.New("nil http.RoundTripper")
+		}
+		s.transport = rt
+		return nil
+	}
+}
+
+// WithTimeout limits how long a single HTTP request may take, including reading the body.
+// Use a context deadline to bound a whole operation such as AddClusterSynchronous
+func WithTimeout(d time.Duration) Option {
+	return func(s *Client) error {
+		s.timeout = d
+		return nil
+	}
+}
+
+// WithDialTimeout limits how long opening a TCP connection to the control plane may take
+func WithDialTimeout(d time.Duration) Option {
+	return func(s *Client) error {
+		s.dialTimeout = d
+		return nil
+	}
+}
+
+// WithProxyURL sends requests through the given HTTP proxy instead of the one in the environment
+func WithProxyURL(proxyURL string) Option {
+	return func(s *Client) error {
+		u, err := url.Parse(proxyURL)
+		if err != nil {
+			return err
+		}
+		s.proxyURL = u
+		return nil
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request
+func WithUserAgent(userAgent string) Option {
+	return func(s *Client) error {
+		s.userAgent = userAgent
+		return nil
+	}
+}
+
+// WithMaxIdleConnsPerHost sets how many idle keep-alive connections to the control plane are kept in the pool
+func WithMaxIdleConnsPerHost(n int) Option {
+	return func(s *Client) error {
+		s.maxIdleConnsPerHost = n
+		return nil
+	}
+}
+
+// WithMaxConnsPerHost caps the number of connections to the control plane, 0 means no limit
+func WithMaxConnsPerHost(n int) Option {
+	return func(s *Client) error {
+		s.maxConnsPerHost = n
+		return nil
+	}
+}
+
+// WithRetryPolicy replaces DefaultRetryPolicy, nil disables retries
+func WithRetryPolicy(p *RetryPolicy) Option {
+	return func(s *Client) error {
+		s.RetryPolicy = p
+		return nil
+	}
+}
+
+// WithLogger logs through l. Requests are logged at slog.LevelDebug with method, url,
+// status and duration, full JSON bodies at LevelTrace. Without a logger nothing is logged
+func WithLogger(l *slog.Logger) Option {
+	return func(s *Client) error {
+		s.logger.Store(l)
+		return nil
+	}
+}
@@ -0,0 +1,239 @@
+/*Copyright (c) 2019 Cisco and/or its affiliates.
+
+This software is licensed to you under the terms of the Cisco Sample
+Code License, Version 1.0 (the "License"). You may obtain a copy of the
+License at
+
+               https://developer.cisco.com/docs/licenses
+
+All use of the material herein must be in accordance with the terms of
+the License. All rights not expressly granted by the License are
+reserved. Unless required by applicable law or agreed to separately in
+writing, software distributed under the License is distributed on an "AS
+IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+or implied.*/
+
+package ccp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// WithPageSize asks list endpoints for n items per page. The default of 0 leaves
+// the page size to the control plane. Every page is still fetched either way
+func WithPageSize(n int) Option {
+	return func(s *Client) error {
+		if n < 0 {
+			return errors.New("negative page size")
+		}
+		s.pageSize = n
+		return nil
+	}
+}
+
+// page is one page of a paginated list. Next is either the URL of the following
+// page or its page number, and is null, 0 or missing on the last page
+type page struct {
+	Count   int64           `json:"count"`
+	Next    json.RawMessage `json:"next"`
+	Results json.RawMessage `json:"results"`
+}
+
+// Collect drains a List iterator into a slice, stopping at the first error
+func Collect[T any](seq iter.Seq2[T, error]) ([]T, error) {
+	all := []T{}
+	for item, err := range seq {
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, item)
+	}
+	return all, nil
+}
+
+// ListClusters iterates over every cluster, fetching further pages as the loop reaches them
+func (s *Client) ListClusters(ctx context.Context) iter.Seq2[Cluster, error] {
+	return list[Cluster](ctx, s, "ListClusters", "/v3/clusters")
+}
+
+// ListInfraProviders iterates over every infrastructure provider, a page at a time
+func (s *Client) ListInfraProviders(ctx context.Context) iter.Seq2[ProviderClientConfig, error] {
+	return list[ProviderClientConfig](ctx, s, "ListInfraProviders", "/v3/providers")
+}
+
+// ListNetworkProviderSubnets iterates over every network provider subnet, a page at a time
+func (s *Client) ListNetworkProviderSubnets(ctx context.Context) iter.Seq2[NetworkProviderSubnet, error] {
+	// in CCP 6.x this is still part of the v2 API
+	return list[NetworkProviderSubnet](ctx, s, "ListNetworkProviderSubnets", "/2/network_service/subnets/")
+}
+
+// ListACIProfiles iterates over every ACI profile, a page at a time
+func (s *Client) ListACIProfiles(ctx context.Context) iter.Seq2[ACIProfile, error] {
+	return list[ACIProfile](ctx, s, "ListACIProfiles", "/v3/aci-profiles")
+}
+
+// ListUsers iterates over every user, a page at a time
+func (s *Client) ListUsers(ctx context.Context) iter.Seq2[User, error] {
+	return list[User](ctx, s, "ListUsers", "/v3/users")
+}
+
+// ListRoles iterates over every RBAC role, a page at a time
+func (s *Client) ListRoles(ctx context.Context) iter.Seq2[Role, error] {
+	return list[Role](ctx, s, "ListRoles", "/v3/rbac/roles")
+}
+
+// ListClusterInstalledAddons iterates over the addons installed on a cluster, a page at a time
+func (s *Client) ListClusterInstalledAddons(ctx context.Context, clusterUUID string) iter.Seq2[InstalledAddon, error] {
+	return list[InstalledAddon](ctx, s, "ListClusterInstalledAddons", "/v3/clusters/"+clusterUUID+"/addons/",
+		attribute.String("ccp.cluster.uuid", clusterUUID))
+}
+
+// list is paginate wrapped in an operation span that lasts as long as the iteration
+func list[T any](ctx context.Context, s *Client, op, path string, attrs ...attribute.KeyValue) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		ctx, span := s.startOperation(ctx, op, attrs...)
+		var err error
+		defer func() { endOperation(span, err) }()
+
+		for item, e := range paginate[T](ctx, s, path) {
+			if e != nil {
+				err = e
+			}
+			if !yield(item, e) {
+				return
+			}
+		}
+	}
+}
+
+// paginate yields every item of the list at path, following the next page links.
+// An endpoint that answers with a bare JSON array is a single page
+func paginate[T any](ctx context.Context, s *Client, path string) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+
+		u, err := url.Parse(s.BaseURL + path)
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+		if s.pageSize > 0 {
+			q := u.Query()
+			q.Set("page_size", strconv.Itoa(s.pageSize))
+			u.RawQuery = q.Encode()
+		}
+
+		seen := map[string]bool{u.String(): true}
+		for n := 1; ; n++ {
+			s.log().DebugContext(ctx, "fetching page", "page", n, "url", u.String())
+
+			req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			body, err := s.doRequest(req)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+
+			items, next, err := decodePage[T](body)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+
+			if len(items) == 0 {
+				return
+			}
+			nextURL, err := nextPage(u, next)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			if nextURL == nil {
+				return
+			}
+			// a next link back to any page already fetched would loop forever
+			if seen[nextURL.String()] {
+				yield(zero, fmt.Errorf("pagination of %s does not advance past page %d", path, n))
+				return
+			}
+			seen[nextURL.String()] = true
+			u = nextURL
+		}
+	}
+}
+
+// decodePage reads either a bare JSON array or a {count, next, previous, results} page
+func decodePage[T any](body []byte) ([]T, json.RawMessage, error) {
+	var items []T
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '[' {
+		err := json.Unmarshal(trimmed, &items)
+		return items, nil, err
+	}
+
+	var p page
+	if err := json.Unmarshal(body, &p); err != nil {
+		return nil, nil, err
+	}
+	if len(p.Results) > 0 {
+		if err := json.Unmarshal(p.Results, &items); err != nil {
+			return nil, nil, err
+		}
+	}
+	return items, p.Next, nil
+}
+
+// nextPage works out the URL of the page after current, or nil on the last page.
+// A next link is only trusted for its path and query, the control plane behind a
+// proxy or load balancer often reports its own internal address
+func nextPage(current *url.URL, next json.RawMessage) (*url.URL, error) {
+	var v interface{}
+	if len(next) > 0 {
+		if err := json.Unmarshal(next, &v); err != nil {
+			return nil, err
+		}
+	}
+
+	switch v := v.(type) {
+	case nil:
+		return nil, nil
+	case float64:
+		if v <= 0 {
+			return nil, nil
+		}
+		u := *current
+		q := u.Query()
+		q.Set("page", strconv.FormatInt(int64(v), 10))
+		u.RawQuery = q.Encode()
+		return &u, nil
+	case string:
+		if v == "" {
+			return nil, nil
+		}
+		ref, err := url.Parse(v)
+		if err != nil {
+			return nil, err
+		}
+		u := current.ResolveReference(&url.URL{Path: ref.Path, RawPath: ref.RawPath, RawQuery: ref.RawQuery})
+		return u, nil
+	}
+	return nil, fmt.Errorf("unexpected next page %s", next)
+}
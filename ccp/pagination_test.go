@@ -0,0 +1,195 @@
+/*Copyright (c) 2019 Cisco and/or its affiliates.
+
+This software is licensed to you under the terms of the Cisco Sample
+Code License, Version 1.0 (the "License"). You may obtain a copy of the
+License at
+
+               https://developer.cisco.com/docs/licenses
+
+All use of the material herein must be in accordance with the terms of
+the License. All rights not expressly granted by the License are
+reserved. Unless required by applicable law or agreed to separately in
+writing, software distributed under the License is distributed on an "AS
+IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+or implied.*/
+
+package ccp_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rob-moss/ccp-clientlibrary-go/ccp"
+	"github.com/rob-moss/ccp-clientlibrary-go/ccp/ccptest"
+)
+
+// addClusters stores n READY clusters called cluster-1 to cluster-n
+func addClusters(srv *ccptest.Server, n int) {
+	for i := 1; i <= n; i++ {
+		srv.AddCluster(ccp.Cluster{Name: ccp.String(fmt.Sprintf("cluster-%d", i))})
+	}
+}
+
+func TestPaginationFetchesEveryPage(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	addClusters(srv, 5)
+	client := newTestClient(t, srv, ccp.WithPageSize(2))
+
+	clusters, err := client.GetClusters()
+	if err != nil {
+		t.Fatalf("GetClusters: %v", err)
+	}
+	if len(clusters) != 5 {
+		t.Fatalf("got %d clusters, want 5", len(clusters))
+	}
+	for i, c := range clusters {
+		if want := fmt.Sprintf("cluster-%d", i+1); *c.Name != want {
+			t.Errorf("cluster %d is %s, want %s", i, *c.Name, want)
+		}
+	}
+	if n := countRequests(srv, http.MethodGet, "/v3/clusters"); n != 3 {
+		t.Errorf("fetched %d pages, want 3", n)
+	}
+}
+
+func TestPaginationBareArray(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	addClusters(srv, 3)
+	client := newTestClient(t, srv)
+
+	clusters, err := client.GetClusters()
+	if err != nil {
+		t.Fatalf("GetClusters: %v", err)
+	}
+	if len(clusters) != 3 {
+		t.Errorf("got %d clusters, want 3", len(clusters))
+	}
+	if n := countRequests(srv, http.MethodGet, "/v3/clusters"); n != 1 {
+		t.Errorf("sent %d requests for an unpaginated list, want 1", n)
+	}
+}
+
+func TestPaginationStopsWithTheLoop(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	addClusters(srv, 10)
+	client := newTestClient(t, srv, ccp.WithPageSize(2))
+
+	seen := 0
+	for _, err := range client.ListClusters(context.Background()) {
+		if err != nil {
+			t.Fatalf("ListClusters: %v", err)
+		}
+		if seen++; seen == 3 {
+			break
+		}
+	}
+	if n := countRequests(srv, http.MethodGet, "/v3/clusters"); n != 2 {
+		t.Errorf("fetched %d pages for 3 clusters, want 2", n)
+	}
+}
+
+// pagedServer answers GET /v3/clusters with the pages given, the page query picking one
+func pagedServer(t *testing.T, pages map[string]string) *ccp.Client {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := pages[r.URL.Query().Get("page")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := ccp.NewClient(srv.URL, ccp.WithAPIVersion(ccp.APIv3), ccp.WithRetryPolicy(nil))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	return client
+}
+
+func TestPaginationPageNumbers(t *testing.T) {
+	client := pagedServer(t, map[string]string{
+		"":  `{"count": 3, "next": 2, "results": [{"name": "a"}, {"name": "b"}]}`,
+		"2": `{"count": 3, "next": null, "results": [{"name": "c"}]}`,
+	})
+
+	clusters, err := client.GetClusters()
+	if err != nil {
+		t.Fatalf("GetClusters: %v", err)
+	}
+	if len(clusters) != 3 || *clusters[2].Name != "c" {
+		t.Errorf("GetClusters = %v, want a, b and c", clusters)
+	}
+}
+
+func TestPaginationMustAdvance(t *testing.T) {
+	client := pagedServer(t, map[string]string{
+		"":  `{"count": 4, "next": 2, "results": [{"name": "a"}]}`,
+		"2": `{"count": 4, "next": 2, "results": [{"name": "b"}]}`,
+	})
+
+	_, err := client.GetClusters()
+	if err == nil || !strings.Contains(err.Error(), "does not advance") {
+		t.Errorf("GetClusters error = %v, want pagination that does not advance", err)
+	}
+}
+
+func TestPaginationCycle(t *testing.T) {
+	client := pagedServer(t, map[string]string{
+		"":  `{"count": 9, "next": 2, "results": [{"name": "a"}]}`,
+		"2": `{"count": 9, "next": 3, "results": [{"name": "b"}]}`,
+		"3": `{"count": 9, "next": 2, "results": [{"name": "c"}]}`,
+	})
+
+	// a loop that never ends runs into the deadline instead of hanging the test
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var names []string
+	var err error
+	for c, e := range client.ListClusters(ctx) {
+		if e != nil {
+			err = e
+			break
+		}
+		names = append(names, *c.Name)
+	}
+	if err == nil || !strings.Contains(err.Error(), "does not advance") {
+		t.Errorf("ListClusters error = %v, want pagination that does not advance", err)
+	}
+	if len(names) != 3 {
+		t.Errorf("ListClusters gave %q, want a, b and c once each", names)
+	}
+}
+
+func TestPaginationError(t *testing.T) {
+	client := pagedServer(t, map[string]string{
+		"": `{"count": 4, "next": 2, "results": [{"name": "a"}]}`,
+	})
+
+	var names []string
+	var err error
+	for c, e := range client.ListClusters(context.Background()) {
+		if e != nil {
+			err = e
+			break
+		}
+		names = append(names, *c.Name)
+	}
+	if len(names) != 1 || err == nil {
+		t.Errorf("ListClusters gave %q then %v, want the first page then the error for the second", names, err)
+	}
+	if _, err := client.GetClusters(); err == nil {
+		t.Error("GetClusters succeeded, want the error for the second page")
+	}
+}
@@ -16,10 +16,12 @@ or implied.*/
 package ccp
 
 import (
+	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // ProviderClientConfig struct for vSphere. AWS, GKE, AKS not yet made
@@ -60,79 +62,68 @@ type NetworkProviderSubnet struct {
 
 // GetNetworkProviderSubnetByName Get and return named Network Provider
 func (s *Client) GetNetworkProviderSubnetByName(networkProviderName string) (*NetworkProviderSubnet, error) {
+	return s.GetNetworkProviderSubnetByNameWithContext(context.Background(), networkProviderName)
+}
+
+// GetNetworkProviderSubnetByNameWithContext is GetNetworkProviderSubnetByName with a context that can cancel the call
+func (s *Client) GetNetworkProviderSubnetByNameWithContext(ctx context.Context, networkProviderName string) (_ *NetworkProviderSubnet, err error) {
+	ctx, span := s.startOperation(ctx, "GetNetworkProviderSubnetByName", attribute.String("ccp.provider.name", networkProviderName))
+	defer func() { endOperation(span, err) }()
 
-	networkProviderSubnets, err := s.GetNetworkProviderSubnets()
+	x, err := findByName(ctx, s, cacheSubnets, networkProviderName, s.GetNetworkProviderSubnetsWithContext)
 	if err != nil {
 		return nil, err
 	}
-	// iterate over array networkProviderSubnets
-	// var _ is discarding the iterator int
-	// var x is each singular networkProviderSubnets struct
-	for _, x := range networkProviderSubnets {
-		if networkProviderName == string(*x.Name) {
-			Debug(2, "Found matching network provider "+*x.Name)
-			return &x, nil
-		}
+	if x == nil {
+		return nil, fmt.Errorf("Network provider %s: %w", networkProviderName, ErrNotFound)
 	}
 
-	return nil, errors.New("Network provider " + networkProviderName + " not found")
+	s.log().DebugContext(ctx, "found matching network provider", "name", *x.Name)
+	return x, nil
 }
 
 // GetNetworkProviderSubnets Get and return All Providers
 func (s *Client) GetNetworkProviderSubnets() ([]NetworkProviderSubnet, error) {
+	return s.GetNetworkProviderSubnetsWithContext(context.Background())
+}
 
-	// in CCP 6.x this is still part of the v2 API
-	url := s.BaseURL + "/2/network_service/subnets/"
-
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, err
-	}
-	bytes, err := s.doRequest(req)
-	if err != nil {
-		return nil, err
-	}
-	var data []NetworkProviderSubnet
-
-	err = json.Unmarshal(bytes, &data)
-	if err != nil {
-		return nil, err
-	}
+// GetNetworkProviderSubnetsWithContext is GetNetworkProviderSubnets with a context that can cancel the call
+func (s *Client) GetNetworkProviderSubnetsWithContext(ctx context.Context) (_ []NetworkProviderSubnet, err error) {
+	ctx, span := s.startOperation(ctx, "GetNetworkProviderSubnets")
+	defer func() { endOperation(span, err) }()
 
-	return data, nil
+	// in CCP 6.x this is still part of the v2 API
+	return Collect(paginate[NetworkProviderSubnet](ctx, s, "/2/network_service/subnets/"))
 }
 
 // ----- working
 
 // GetInfraProviders Get and return All Infra Providers
 func (s *Client) GetInfraProviders() ([]ProviderClientConfig, error) {
+	return s.GetInfraProvidersWithContext(context.Background())
+}
 
-	url := fmt.Sprintf(s.BaseURL + "/v3/providers")
-
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, err
-	}
-	bytes, err := s.doRequest(req)
-	if err != nil {
-		return nil, err
-	}
-	var data []ProviderClientConfig
+// GetInfraProvidersWithContext is GetInfraProviders with a context that can cancel the call
+func (s *Client) GetInfraProvidersWithContext(ctx context.Context) (_ []ProviderClientConfig, err error) {
+	ctx, span := s.startOperation(ctx, "GetInfraProviders")
+	defer func() { endOperation(span, err) }()
 
-	err = json.Unmarshal(bytes, &data)
-	if err != nil {
-		return nil, err
-	}
-
-	return data, nil
+	return Collect(paginate[ProviderClientConfig](ctx, s, "/v3/providers"))
 }
 
 // GetInfraProviderByUUID by UUID
 func (s *Client) GetInfraProviderByUUID(providerUUID string) (*ProviderClientConfig, error) {
+	return s.GetInfraProviderByUUIDWithContext(context.Background(), providerUUID)
+}
+
+// GetInfraProviderByUUIDWithContext is GetInfraProviderByUUID with a context that can cancel the call
+func (s *Client) GetInfraProviderByUUIDWithContext(ctx context.Context, providerUUID string) (_ *ProviderClientConfig, err error) {
+	ctx, span := s.startOperation(ctx, "GetInfraProviderByUUID", attribute.String("ccp.provider.uuid", providerUUID))
+	defer func() { endOperation(span, err) }()
 
 	url := s.BaseURL + "/v3/providers/" + providerUUID
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -147,27 +138,29 @@ func (s *Client) GetInfraProviderByUUID(providerUUID string) (*ProviderClientCon
 		return nil, err
 	}
 
-	Debug(2, "Found matching Infra provider "+*data.Name)
+	s.log().DebugContext(ctx, "found infra provider", "name", *data.Name, "uuid", providerUUID)
 
 	return data, nil
 }
 
 // GetInfraProviderByName by Name
 func (s *Client) GetInfraProviderByName(providerName string) (*ProviderClientConfig, error) {
+	return s.GetInfraProviderByNameWithContext(context.Background(), providerName)
+}
+
+// GetInfraProviderByNameWithContext is GetInfraProviderByName with a context that can cancel the call
+func (s *Client) GetInfraProviderByNameWithContext(ctx context.Context, providerName string) (_ *ProviderClientConfig, err error) {
+	ctx, span := s.startOperation(ctx, "GetInfraProviderByName", attribute.String("ccp.provider.name", providerName))
+	defer func() { endOperation(span, err) }()
 
-	providers, err := s.GetInfraProviders()
+	x, err := findByName(ctx, s, cacheProviders, providerName, s.GetInfraProvidersWithContext)
 	if err != nil {
 		return nil, err
 	}
-	// iterate over array networkProviderSubnets
-	// var _ is discarding the iterator int
-	// var x is each singular networkProviderSubnets struct
-	for _, x := range providers {
-		if providerName == string(*x.Name) {
-			Debug(2, "Found matching Infra provider "+*x.Name)
-			return &x, nil
-		}
+	if x == nil {
+		return nil, fmt.Errorf("Infra provider %s: %w", providerName, ErrNotFound)
 	}
 
-	return nil, errors.New("Infra provider " + providerName + " not found")
+	s.log().DebugContext(ctx, "found matching infra provider", "name", *x.Name)
+	return x, nil
 }
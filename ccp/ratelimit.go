@@ -0,0 +1,155 @@
+/*Copyright (c) 2019 Cisco and/or its affiliates.
+
+This software is licensed to you under the terms of the Cisco Sample
+Code License, Version 1.0 (the "License"). You may obtain a copy of the
+License at
+
+               https://developer.cisco.com/docs/licenses
+
+All use of the material herein must be in accordance with the terms of
+the License. All rights not expressly granted by the License are
+reserved. Unless required by applicable law or agreed to separately in
+writing, software distributed under the License is distributed on an "AS
+IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+or implied.*/
+
+package ccp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+// limiter caps the request rate with a token bucket and the number of requests in flight.
+// Either may be nil for no limit
+type limiter struct {
+	rate     *rate.Limiter
+	inFlight chan struct{}
+}
+
+// wait blocks until a request may be sent or ctx is done,
+// returning the function that frees the request's in-flight slot.
+// The token bucket comes first so a slot is never held while sleeping on the rate
+func (l *limiter) wait(ctx context.Context) (func(), error) {
+	if l.rate != nil {
+		if err := l.rate.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if l.inFlight != nil {
+		select {
+		case l.inFlight <- struct{}{}:
+			return func() { <-l.inFlight }, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return func() {}, nil
+}
+
+// limiterFor returns the limiter for method, or the client wide one for "", creating it on first use
+func (s *Client) limiterFor(method string) *limiter {
+	if method == "" {
+		if s.limits == nil {
+			s.limits = &limiter{}
+		}
+		return s.limits
+	}
+	if s.methodLimits == nil {
+		s.methodLimits = make(map[string]*limiter)
+	}
+	method = strings.ToUpper(method)
+	if s.methodLimits[method] == nil {
+		s.methodLimits[method] = &limiter{}
+	}
+	return s.methodLimits[method]
+}
+
+// WithRateLimit lets the client send at most requestsPerSecond requests, with bursts of up to burst.
+// Requests wait their turn, giving up if their context is done
+func WithRateLimit(requestsPerSecond float64, burst int) Option {
+	return withRateLimit("", requestsPerSecond, burst)
+}
+
+// WithMethodRateLimit limits requests with the HTTP verb method, such as http.MethodPost,
+// on top of any WithRateLimit for the whole client
+func WithMethodRateLimit(method string, requestsPerSecond float64, burst int) Option {
+	return withRateLimit(method, requestsPerSecond, burst)
+}
+
+func withRateLimit(method string, requestsPerSecond float64, burst int) Option {
+	return func(s *Client) error {
+		if requestsPerSecond <= 0 || burst < 1 {
+			return errors.New("rate limit needs a positive rate and a burst of at least 1")
+		}
+		s.limiterFor(method).rate = rate.NewLimiter(rate.Limit(requestsPerSecond), burst)
+		return nil
+	}
+}
+
+// WithMaxInFlight lets the client have at most n requests waiting on the control plane at once.
+// Further requests wait for a slot, giving up if their context is done
+func WithMaxInFlight(n int) Option {
+	return withMaxInFlight("", n)
+}
+
+// WithMethodMaxInFlight caps requests in flight with the HTTP verb method,
+// on top of any WithMaxInFlight for the whole client
+func WithMethodMaxInFlight(method string, n int) Option {
+	return withMaxInFlight(method, n)
+}
+
+func withMaxInFlight(method string, n int) Option {
+	return func(s *Client) error {
+		if n < 1 {
+			return errors.New("max in flight must be at least 1")
+		}
+		s.limiterFor(method).inFlight = make(chan struct{}, n)
+		return nil
+	}
+}
+
+// acquire waits until req may be sent under the per verb and client limits.
+// The per verb limit comes first, so a request queued behind other POSTs, say,
+// doesn't sit on a client wide slot that a GET could use. The returned function
+// frees its in-flight slots
+func (s *Client) acquire(req *http.Request) (func(), error) {
+	var releases []func()
+	release := func() {
+		for _, r := range releases {
+			r()
+		}
+	}
+
+	for _, l := range []*limiter{s.methodLimits[req.Method], s.limits} {
+		if l == nil {
+			continue
+		}
+		r, err := l.wait(req.Context())
+		if err != nil {
+			release()
+			return nil, err
+		}
+		releases = append(releases, r)
+	}
+	return release, nil
+}
+
+// releaseOnClose frees a request's in-flight slot once its response body is closed
+type releaseOnClose struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (b *releaseOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
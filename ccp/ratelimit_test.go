@@ -0,0 +1,123 @@
+/*Copyright (c) 2019 Cisco and/or its affiliates.
+
+This software is licensed to you under the terms of the Cisco Sample
+Code License, Version 1.0 (the "License"). You may obtain a copy of the
+License at
+
+               https://developer.cisco.com/docs/licenses
+
+All use of the material herein must be in accordance with the terms of
+the License. All rights not expressly granted by the License are
+reserved. Unless required by applicable law or agreed to separately in
+writing, software distributed under the License is distributed on an "AS
+IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+or implied.*/
+
+package ccp_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rob-moss/ccp-clientlibrary-go/ccp"
+	"github.com/rob-moss/ccp-clientlibrary-go/ccp/ccptest"
+)
+
+func TestRateLimit(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	client := newTestClient(t, srv, ccp.WithRateLimit(20, 1))
+
+	// logging in emptied the bucket, so each call waits about 50ms
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if _, err := client.GetClusters(); err != nil {
+			t.Fatalf("GetClusters: %v", err)
+		}
+	}
+	if took := time.Since(start); took < 200*time.Millisecond {
+		t.Errorf("5 calls at 20 a second took %s, want at least 200ms", took)
+	}
+}
+
+func TestRateLimitGivesUpWithContext(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	// a burst of 2 covers the login and the API version probe before it
+	client := newTestClient(t, srv, ccp.WithRateLimit(0.1, 2))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := client.GetClustersWithContext(ctx)
+	if err == nil {
+		t.Fatal("GetClusters waited 10s for the bucket, want it to give up with the context")
+	}
+	if n := countRequests(srv, http.MethodGet, "/v3/clusters"); n != 0 {
+		t.Errorf("GET /v3/clusters sent %d times, want 0", n)
+	}
+}
+
+func TestMaxInFlight(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	client := newTestClient(t, srv, ccp.WithMaxInFlight(1))
+
+	srv.AddFault(ccptest.Fault{Method: http.MethodGet, Path: "/v3/clusters", Delay: 50 * time.Millisecond})
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.GetClusters(); err != nil {
+				t.Errorf("GetClusters: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if took := time.Since(start); took < 150*time.Millisecond {
+		t.Errorf("3 slow calls one at a time took %s, want at least 150ms", took)
+	}
+}
+
+// A request waiting on its verb's limit must not hold a client wide slot, or
+// a queue of POSTs would starve every GET
+func TestMethodLimitDoesNotStarveOtherVerbs(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	client := newTestClient(t, srv, ccp.WithMaxInFlight(2), ccp.WithMethodMaxInFlight(http.MethodPost, 1), ccp.WithRetryPolicy(nil))
+	c := addTestCluster(srv)
+
+	const postDelay = 300 * time.Millisecond
+	srv.AddFault(ccptest.Fault{Method: http.MethodPost, Path: "/v3/clusters/" + *c.UUID + "/addons/", Delay: postDelay})
+
+	var wg sync.WaitGroup
+	for _, install := range []func(string) error{client.InstallAddonDashboard, client.InstallAddonMonitoring, client.InstallAddonLogging} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			install(*c.UUID)
+		}()
+	}
+	defer wg.Wait()
+
+	// let the first POST reach the server and the others queue behind it
+	for countRequests(srv, http.MethodPost, "/v3/clusters/"+*c.UUID+"/addons/") == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), postDelay/2)
+	defer cancel()
+	if _, err := client.GetClustersWithContext(ctx); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			t.Fatal("GetClusters waited behind the queued POSTs")
+		}
+		t.Fatalf("GetClusters: %v", err)
+	}
+}
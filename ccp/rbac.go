@@ -0,0 +1,198 @@
+/*Copyright (c) 2019 Cisco and/or its affiliates.
+
+This software is licensed to you under the terms of the Cisco Sample
+Code License, Version 1.0 (the "License"). You may obtain a copy of the
+License at
+
+               https://developer.cisco.com/docs/licenses
+
+All use of the material herein must be in accordance with the terms of
+the License. All rights not expressly granted by the License are
+reserved. Unless required by applicable law or agreed to separately in
+writing, software distributed under the License is distributed on an "AS
+IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+or implied.*/
+
+package ccp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Role is an RBAC role a user or LDAP group can have
+type Role struct {
+	Role        *string   `json:"role,omitempty"`
+	Description *string   `json:"description,omitempty"`
+	Permissions *[]string `json:"permissions,omitempty"`
+}
+
+// ClusterAuthz is who besides the administrators may use a cluster
+type ClusterAuthz struct {
+	ClusterUUID *string          `json:"cluster_id,omitempty"`
+	AuthList    *[]AuthListEntry `json:"auth_list,omitempty"`
+}
+
+// AuthListEntry grants a user or an LDAP group access to a cluster
+type AuthListEntry struct {
+	Type *string `json:"type,omitempty"` // AuthTypeUser or AuthTypeGroup
+	Name *string `json:"name,omitempty"` // the username or the LDAP group DN
+}
+
+// The kinds of AuthListEntry
+const (
+	AuthTypeUser  = "user"
+	AuthTypeGroup = "group"
+)
+
+// validAuthEntry checks the type and name of a grant or revoke
+func validAuthEntry(authType, name string) error {
+	if authType != AuthTypeUser && authType != AuthTypeGroup {
+		return fmt.Errorf("access type %q must be %s or %s: %w", authType, AuthTypeUser, AuthTypeGroup, ErrValidation)
+	}
+	if name == "" {
+		return fmt.Errorf("%s name is missing: %w", authType, ErrValidation)
+	}
+	return nil
+}
+
+// GetRoles gets every RBAC role
+func (s *Client) GetRoles() ([]Role, error) {
+	return s.GetRolesWithContext(context.Background())
+}
+
+// GetRolesWithContext is GetRoles with a context that can cancel the call
+func (s *Client) GetRolesWithContext(ctx context.Context) (_ []Role, err error) {
+	ctx, span := s.startOperation(ctx, "GetRoles")
+	defer func() { endOperation(span, err) }()
+
+	return Collect(paginate[Role](ctx, s, "/v3/rbac/roles"))
+}
+
+// GetRole gets an RBAC role by name
+func (s *Client) GetRole(role string) (*Role, error) {
+	return s.GetRoleWithContext(context.Background(), role)
+}
+
+// GetRoleWithContext is GetRole with a context that can cancel the call
+func (s *Client) GetRoleWithContext(ctx context.Context, role string) (_ *Role, err error) {
+	ctx, span := s.startOperation(ctx, "GetRole", attribute.String("ccp.role", role))
+	defer func() { endOperation(span, err) }()
+
+	roles, err := s.GetRolesWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range roles {
+		if r.Role != nil && *r.Role == role {
+			return &r, nil
+		}
+	}
+	return nil, fmt.Errorf("Cannot find Role %s: %w", role, ErrNotFound)
+}
+
+// GetClusterAuthz gets the users and LDAP groups that may use a cluster
+func (s *Client) GetClusterAuthz(clusterUUID string) (*ClusterAuthz, error) {
+	return s.GetClusterAuthzWithContext(context.Background(), clusterUUID)
+}
+
+// GetClusterAuthzWithContext is GetClusterAuthz with a context that can cancel the call
+func (s *Client) GetClusterAuthzWithContext(ctx context.Context, clusterUUID string) (_ *ClusterAuthz, err error) {
+	ctx, span := s.startOperation(ctx, "GetClusterAuthz", attribute.String("ccp.cluster.uuid", clusterUUID))
+	defer func() { endOperation(span, err) }()
+
+	if clusterUUID == "" {
+		return nil, fmt.Errorf("Cluster UUID is required: %w", ErrValidation)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", s.BaseURL+"/v3/clusters/"+clusterUUID+"/authz/", nil)
+	if err != nil {
+		return nil, err
+	}
+	bytes, err := s.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var data ClusterAuthz
+	if err := json.Unmarshal(bytes, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// GrantClusterAccess lets a user, or the members of an LDAP group, use a cluster.
+// authType is AuthTypeUser or AuthTypeGroup
+func (s *Client) GrantClusterAccess(clusterUUID, authType, name string) (*ClusterAuthz, error) {
+	return s.GrantClusterAccessWithContext(context.Background(), clusterUUID, authType, name)
+}
+
+// GrantClusterAccessWithContext is GrantClusterAccess with a context that can cancel the call
+func (s *Client) GrantClusterAccessWithContext(ctx context.Context, clusterUUID, authType, name string) (_ *ClusterAuthz, err error) {
+	ctx, span := s.startOperation(ctx, "GrantClusterAccess", attribute.String("ccp.cluster.uuid", clusterUUID),
+		attribute.String("ccp.authz.type", authType), attribute.String("ccp.authz.name", name))
+	defer func() { endOperation(span, err) }()
+	defer s.cache.forget(cacheClusters, "", clusterUUID) // the cluster's access changed
+
+	if clusterUUID == "" {
+		return nil, fmt.Errorf("Cluster UUID is required: %w", ErrValidation)
+	}
+	if err := validAuthEntry(authType, name); err != nil {
+		return nil, err
+	}
+
+	j, err := json.Marshal(AuthListEntry{Type: String(authType), Name: String(name)})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", s.BaseURL+"/v3/clusters/"+clusterUUID+"/authz/", bytes.NewBuffer(j))
+	if err != nil {
+		return nil, err
+	}
+	bytes, err := s.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var data ClusterAuthz
+	if err := json.Unmarshal(bytes, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// RevokeClusterAccess takes a user's or an LDAP group's access to a cluster away
+func (s *Client) RevokeClusterAccess(clusterUUID, authType, name string) error {
+	return s.RevokeClusterAccessWithContext(context.Background(), clusterUUID, authType, name)
+}
+
+// RevokeClusterAccessWithContext is RevokeClusterAccess with a context that can cancel the call
+func (s *Client) RevokeClusterAccessWithContext(ctx context.Context, clusterUUID, authType, name string) (err error) {
+	ctx, span := s.startOperation(ctx, "RevokeClusterAccess", attribute.String("ccp.cluster.uuid", clusterUUID),
+		attribute.String("ccp.authz.type", authType), attribute.String("ccp.authz.name", name))
+	defer func() { endOperation(span, err) }()
+	defer s.cache.forget(cacheClusters, "", clusterUUID) // the cluster's access changed
+
+	if clusterUUID == "" {
+		return fmt.Errorf("Cluster UUID is required: %w", ErrValidation)
+	}
+	if err := validAuthEntry(authType, name); err != nil {
+		return err
+	}
+
+	// an LDAP group DN is full of characters that don't belong in a path, so it goes in the query
+	q := url.Values{"type": {authType}, "name": {name}}
+	req, err := http.NewRequestWithContext(ctx, "DELETE", s.BaseURL+"/v3/clusters/"+clusterUUID+"/authz/?"+q.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	_, err = s.doRequest(req)
+	return err
+}
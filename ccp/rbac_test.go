@@ -0,0 +1,178 @@
+/*Copyright (c) 2019 Cisco and/or its affiliates.
+
+This software is licensed to you under the terms of the Cisco Sample
+Code License, Version 1.0 (the "License"). You may obtain a copy of the
+License at
+
+               https://developer.cisco.com/docs/licenses
+
+All use of the material herein must be in accordance with the terms of
+the License. All rights not expressly granted by the License are
+reserved. Unless required by applicable law or agreed to separately in
+writing, software distributed under the License is distributed on an "AS
+IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+or implied.*/
+
+package ccp_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/rob-moss/ccp-clientlibrary-go/ccp"
+	"github.com/rob-moss/ccp-clientlibrary-go/ccp/ccptest"
+)
+
+const devsGroup = "cn=devs,ou=groups,dc=example,dc=com"
+
+func TestGetRoles(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	client := newTestClient(t, srv)
+
+	roles, err := client.GetRoles()
+	if err != nil {
+		t.Fatalf("GetRoles: %v", err)
+	}
+	var names []string
+	for _, r := range roles {
+		names = append(names, *r.Role)
+	}
+	if len(names) != 2 || names[0] != ccp.RoleAdministrator || names[1] != ccp.RoleDevops {
+		t.Errorf("GetRoles = %v, want Administrator and Devops", names)
+	}
+
+	role, err := client.GetRole(ccp.RoleDevops)
+	if err != nil {
+		t.Fatalf("GetRole: %v", err)
+	}
+	if role.Permissions == nil || len(*role.Permissions) == 0 {
+		t.Errorf("GetRole(Devops) = %+v, want its permissions", role)
+	}
+	if _, err := client.GetRole("Superuser"); !errors.Is(err, ccp.ErrNotFound) {
+		t.Errorf("GetRole of an unknown role error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestClusterAccess(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	srv.AddUser("jo", "secret")
+	c := srv.AddCluster(*ccptest.NewCluster("shared"))
+	client := newTestClient(t, srv)
+
+	authz, err := client.GetClusterAuthz(*c.UUID)
+	if err != nil {
+		t.Fatalf("GetClusterAuthz: %v", err)
+	}
+	if *authz.ClusterUUID != *c.UUID || authz.AuthList == nil || len(*authz.AuthList) != 0 {
+		t.Errorf("GetClusterAuthz of a new cluster = %+v, want an empty list", authz)
+	}
+
+	if _, err := client.GrantClusterAccess(*c.UUID, ccp.AuthTypeUser, "jo"); err != nil {
+		t.Fatalf("GrantClusterAccess to jo: %v", err)
+	}
+	authz, err = client.GrantClusterAccess(*c.UUID, ccp.AuthTypeGroup, devsGroup)
+	if err != nil {
+		t.Fatalf("GrantClusterAccess to the group: %v", err)
+	}
+	if len(*authz.AuthList) != 2 {
+		t.Errorf("GrantClusterAccess = %+v, want jo and the group", *authz.AuthList)
+	}
+
+	// the group DN goes in the query, commas and all
+	if err := client.RevokeClusterAccess(*c.UUID, ccp.AuthTypeGroup, devsGroup); err != nil {
+		t.Fatalf("RevokeClusterAccess from the group: %v", err)
+	}
+	stored, _ := srv.ClusterAuthz(*c.UUID)
+	if len(stored) != 1 || *stored[0].Type != ccp.AuthTypeUser || *stored[0].Name != "jo" {
+		t.Errorf("access after revoking the group = %+v, want only jo", stored)
+	}
+	if err := client.RevokeClusterAccess(*c.UUID, ccp.AuthTypeGroup, devsGroup); !errors.Is(err, ccp.ErrNotFound) {
+		t.Errorf("RevokeClusterAccess of access never granted error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestClusterAccessErrors(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	c := srv.AddCluster(*ccptest.NewCluster("shared"))
+	client := newTestClient(t, srv, ccp.WithRetryPolicy(nil))
+
+	if _, err := client.GetClusterAuthz("no-such-cluster"); !errors.Is(err, ccp.ErrNotFound) {
+		t.Errorf("GetClusterAuthz of a missing cluster error = %v, want ErrNotFound", err)
+	}
+	// the control plane only knows its own users
+	if _, err := client.GrantClusterAccess(*c.UUID, ccp.AuthTypeUser, "nobody"); !errors.Is(err, ccp.ErrValidation) {
+		t.Errorf("GrantClusterAccess to an unknown user error = %v, want ErrValidation", err)
+	}
+}
+
+func TestClusterAccessValidation(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	c := srv.AddCluster(*ccptest.NewCluster("shared"))
+	client := newTestClient(t, srv)
+
+	tests := []struct {
+		name                  string
+		clusterUUID, authType string
+		entityName            string
+	}{
+		{"no cluster", "", ccp.AuthTypeUser, "jo"},
+		{"unknown type", *c.UUID, "team", "jo"},
+		{"empty type", *c.UUID, "", "jo"},
+		{"no name", *c.UUID, ccp.AuthTypeGroup, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := client.GrantClusterAccess(tt.clusterUUID, tt.authType, tt.entityName); !errors.Is(err, ccp.ErrValidation) {
+				t.Errorf("GrantClusterAccess error = %v, want ErrValidation", err)
+			}
+			if err := client.RevokeClusterAccess(tt.clusterUUID, tt.authType, tt.entityName); !errors.Is(err, ccp.ErrValidation) {
+				t.Errorf("RevokeClusterAccess error = %v, want ErrValidation", err)
+			}
+		})
+	}
+	if _, err := client.GetClusterAuthz(""); !errors.Is(err, ccp.ErrValidation) {
+		t.Errorf("GetClusterAuthz without a cluster error = %v, want ErrValidation", err)
+	}
+
+	if n := len(srv.Requests()); n != 0 {
+		t.Errorf("control plane got %d requests for invalid grants, want none", n)
+	}
+}
+
+func TestClusterAccessDropsCache(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	srv.AddUser("jo", "secret")
+	addClusters(srv, 2)
+	client := newTestClient(t, srv, ccp.WithCache(time.Minute))
+
+	c, _, err := lookup(t, srv, client, "cluster-1")
+	if err != nil {
+		t.Fatalf("GetClusterByName: %v", err)
+	}
+	if _, err := client.GrantClusterAccess(*c.UUID, ccp.AuthTypeUser, "jo"); err != nil {
+		t.Fatalf("GrantClusterAccess: %v", err)
+	}
+	if _, lists, err := lookup(t, srv, client, "cluster-1"); err != nil || lists != 1 {
+		t.Errorf("GetClusterByName after GrantClusterAccess = %v after %d lists, want a new list", err, lists)
+	}
+	if _, lists, err := lookup(t, srv, client, "cluster-2"); err != nil || lists != 0 {
+		t.Errorf("GetClusterByName(cluster-2) = %v after %d lists, want the cached cluster", err, lists)
+	}
+
+	if err := client.RevokeClusterAccess(*c.UUID, ccp.AuthTypeUser, "jo"); err != nil {
+		t.Fatalf("RevokeClusterAccess: %v", err)
+	}
+	if _, lists, err := lookup(t, srv, client, "cluster-1"); err != nil || lists != 1 {
+		t.Errorf("GetClusterByName after RevokeClusterAccess = %v after %d lists, want a new list", err, lists)
+	}
+	if n := countRequests(srv, http.MethodDelete, "/v3/clusters/"+*c.UUID+"/authz/"); n != 1 {
+		t.Errorf("DELETE of the grant sent %d times, want 1", n)
+	}
+}
@@ -0,0 +1,208 @@
+/*Copyright (c) 2019 Cisco and/or its affiliates.
+
+This software is licensed to you under the terms of the Cisco Sample
+Code License, Version 1.0 (the "License"). You may obtain a copy of the
+License at
+
+               https://developer.cisco.com/docs/licenses
+
+All use of the material herein must be in accordance with the terms of
+the License. All rights not expressly granted by the License are
+reserved. Unless required by applicable law or agreed to separately in
+writing, software distributed under the License is distributed on an "AS
+IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+or implied.*/
+
+package ccp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Redacted replaces secret values in logs, error messages and the String and GoString output of the models
+const Redacted = "[REDACTED]"
+
+// secretKeys are the JSON keys whose values are never logged
+var secretKeys = map[string]bool{
+	"password":               true, // LoginCreds, VsphereClientConfig, User
+	"serviceaccountpassword": true, // LDAPSetup
+	"apic_password":          true, // ACIProfile
+	"ssh_key":                true, // MasterNodePool, WorkerNodePool
+	"kubeconfig":             true, // Cluster
+	"selfsignedca":           true, // RegistriesSelfSigned
+	"token":                  true,
+	"x-auth-token":           true,
+	"secret":                 true,
+}
+
+// isSecretKey reports whether the value under the JSON key k must be masked
+func isSecretKey(k string) bool {
+	return secretKeys[strings.ToLower(k)]
+}
+
+// redactJSON masks the secret values anywhere in a JSON document.
+// Anything that is not JSON is returned as is
+func redactJSON(body []byte) []byte {
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return body
+	}
+	if !redactValue(v) {
+		// nothing to mask, keep the original formatting
+		return body
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		return body
+	}
+	return out
+}
+
+// redactValue masks secret values in a decoded JSON value in place, reporting whether it changed anything
+func redactValue(v interface{}) bool {
+	changed := false
+	switch x := v.(type) {
+	case map[string]interface{}:
+		for k, val := range x {
+			if isSecretKey(k) && val != nil && val != "" {
+				x[k] = Redacted
+				changed = true
+				continue
+			}
+			if redactValue(val) {
+				changed = true
+			}
+		}
+	case []interface{}:
+		for _, val := range x {
+			if redactValue(val) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// RedactJSON masks secrets anywhere in a JSON document the way the client's logs do.
+// Anything that is not JSON is returned as is
+func RedactJSON(body []byte) []byte {
+	return redactJSON(body)
+}
+
+// redactBody masks the secrets in a JSON document or a form, such as the v2 login
+func redactBody(body []byte) []byte {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 || json.Valid(trimmed) {
+		return redactJSON(body)
+	}
+
+	form, err := url.ParseQuery(string(trimmed))
+	if err != nil {
+		return body
+	}
+	changed := false
+	for k := range form {
+		if isSecretKey(k) {
+			form.Set(k, Redacted)
+			changed = true
+		}
+	}
+	if !changed {
+		return body
+	}
+	return []byte(form.Encode())
+}
+
+// RedactBody masks secrets in a JSON or form encoded body the way the client's logs do.
+// Anything else is returned as is
+func RedactBody(body []byte) []byte {
+	return redactBody(body)
+}
+
+// redactedString renders v as JSON with the secrets masked, for String and GoString
+func redactedString(v interface{}) string {
+	j, err := json.Marshal(v)
+	if err != nil {
+		return Redacted
+	}
+	return string(redactJSON(j))
+}
+
+// String prints the cluster as JSON with the kubeconfig, SSH keys and registry certificate masked
+func (c Cluster) String() string { return redactedString(c) }
+
+// GoString is used by %#v, masking the same fields as String
+func (c Cluster) GoString() string { return "ccp.Cluster(" + redactedString(c) + ")" }
+
+// String prints the node pool as JSON with the SSH key masked
+func (p MasterNodePool) String() string { return redactedString(p) }
+
+// GoString is used by %#v, masking the same fields as String
+func (p MasterNodePool) GoString() string { return "ccp.MasterNodePool(" + redactedString(p) + ")" }
+
+// String prints the node pool as JSON with the SSH key masked
+func (p WorkerNodePool) String() string { return redactedString(p) }
+
+// GoString is used by %#v, masking the same fields as String
+func (p WorkerNodePool) GoString() string { return "ccp.WorkerNodePool(" + redactedString(p) + ")" }
+
+// String prints the registries as JSON with the certificate masked
+func (r RegistriesSelfSigned) String() string { return redactedString(r) }
+
+// GoString is used by %#v, masking the same fields as String
+func (r RegistriesSelfSigned) GoString() string {
+	return "ccp.RegistriesSelfSigned(" + redactedString(r) + ")"
+}
+
+// String prints the config as JSON with the password masked
+func (c VsphereClientConfig) String() string { return redactedString(c) }
+
+// GoString is used by %#v, masking the same fields as String
+func (c VsphereClientConfig) GoString() string {
+	return "ccp.VsphereClientConfig(" + redactedString(c) + ")"
+}
+
+// String prints the profile as JSON with the APIC password masked
+func (p ACIProfile) String() string { return redactedString(p) }
+
+// GoString is used by %#v, masking the same fields as String
+func (p ACIProfile) GoString() string { return "ccp.ACIProfile(" + redactedString(p) + ")" }
+
+// String prints the credentials with the password masked
+func (c LoginCreds) String() string { return redactedString(c) }
+
+// GoString is used by %#v, masking the same fields as String
+func (c LoginCreds) GoString() string { return "ccp.LoginCreds(" + redactedString(c) + ")" }
+
+// String prints the user as JSON with the password masked
+func (u User) String() string { return redactedString(u) }
+
+// GoString is used by %#v, masking the same fields as String
+func (u User) GoString() string { return "ccp.User(" + redactedString(u) + ")" }
+
+// String prints the LDAP setup as JSON with the bind password masked
+func (l LDAPSetup) String() string { return redactedString(l) }
+
+// GoString is used by %#v, masking the same fields as String
+func (l LDAPSetup) GoString() string { return "ccp.LDAPSetup(" + redactedString(l) + ")" }
+
+// String describes the client without its password or session token
+func (s *Client) String() string {
+	return fmt.Sprintf("ccp.Client{BaseURL: %q, Username: %q, Password: %q, Token: %q}",
+		s.BaseURL, s.Username, maskNonEmpty(s.Password), maskNonEmpty(s.Token()))
+}
+
+// GoString is used by %#v, masking the same fields as String
+func (s *Client) GoString() string { return s.String() }
+
+// maskNonEmpty returns Redacted for a set secret so the output still shows whether it was set
+func maskNonEmpty(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return Redacted
+}
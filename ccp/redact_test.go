@@ -0,0 +1,158 @@
+/*Copyright (c) 2019 Cisco and/or its affiliates.
+
+This software is licensed to you under the terms of the Cisco Sample
+Code License, Version 1.0 (the "License"). You may obtain a copy of the
+License at
+
+               https://developer.cisco.com/docs/licenses
+
+All use of the material herein must be in accordance with the terms of
+the License. All rights not expressly granted by the License are
+reserved. Unless required by applicable law or agreed to separately in
+writing, software distributed under the License is distributed on an "AS
+IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+or implied.*/
+
+package ccp_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/rob-moss/ccp-clientlibrary-go/ccp"
+	"github.com/rob-moss/ccp-clientlibrary-go/ccp/ccptest"
+)
+
+// secret is the value every test hides, it must never show up in any output
+const secret = "s3cret-value"
+
+// assertMasked fails unless out has the secret masked
+func assertMasked(t *testing.T, what, out string) {
+	t.Helper()
+	if strings.Contains(out, secret) {
+		t.Errorf("%s leaks the secret: %s", what, out)
+	}
+	if !strings.Contains(out, ccp.Redacted) {
+		t.Errorf("%s has no %s: %s", what, ccp.Redacted, out)
+	}
+}
+
+func TestRedactJSONEveryKey(t *testing.T) {
+	for key := range ccp.SecretKeys {
+		t.Run(key, func(t *testing.T) {
+			docs := map[string]string{
+				"top level":   fmt.Sprintf(`{"name": "kept", %q: %q}`, key, secret),
+				"upper case":  fmt.Sprintf(`{"name": "kept", %q: %q}`, strings.ToUpper(key), secret),
+				"nested":      fmt.Sprintf(`{"outer": {"inner": {%q: %q}}}`, key, secret),
+				"in an array": fmt.Sprintf(`{"items": [{"name": "kept"}, {%q: %q}]}`, key, secret),
+				"top array":   fmt.Sprintf(`[[{%q: %q}]]`, key, secret),
+				"object":      fmt.Sprintf(`{%q: {"value": %q}}`, key, secret),
+			}
+			for name, doc := range docs {
+				assertMasked(t, name, string(ccp.RedactJSON([]byte(doc))))
+				assertMasked(t, name+" body", string(ccp.RedactBody([]byte(doc))))
+			}
+
+			form := url.Values{"username": {"admin"}, key: {secret}}.Encode()
+			masked, err := url.ParseQuery(string(ccp.RedactBody([]byte(form))))
+			if err != nil || masked.Get(key) != ccp.Redacted || masked.Get("username") != "admin" {
+				t.Errorf("RedactBody of the form = %v, %v, want only %s masked", masked, err, key)
+			}
+		})
+	}
+}
+
+func TestRedactJSONKeepsTheRest(t *testing.T) {
+	doc := `{"name": "kept", "password": "", "token": null, "nodes": [{"name": "master"}]}`
+	if got := string(ccp.RedactJSON([]byte(doc))); got != doc {
+		t.Errorf("RedactJSON changed a document with no secrets to %s", got)
+	}
+
+	out := string(ccp.RedactJSON([]byte(`{"name": "kept", "password": "` + secret + `"}`)))
+	if !strings.Contains(out, `"name":"kept"`) {
+		t.Errorf("RedactJSON lost the other fields: %s", out)
+	}
+
+	for _, body := range []string{"", "not json", "<html>password</html>"} {
+		if got := string(ccp.RedactJSON([]byte(body))); got != body {
+			t.Errorf("RedactJSON(%q) = %q, want it unchanged", body, got)
+		}
+	}
+}
+
+func TestModelsHideSecrets(t *testing.T) {
+	models := map[string]interface{}{
+		"Cluster kubeconfig": ccp.Cluster{Name: ccp.String("demo"), KubeConfig: ccp.String(secret)},
+		"Cluster SSH key": ccp.Cluster{
+			Name:           ccp.String("demo"),
+			MasterNodePool: &ccp.MasterNodePool{SSHKey: ccp.String(secret)},
+		},
+		"Cluster worker SSH key": ccp.Cluster{
+			Name:           ccp.String("demo"),
+			WorkerNodePool: &[]ccp.WorkerNodePool{{Name: ccp.String("workers"), SSHKey: ccp.String(secret)}},
+		},
+		"Cluster registry CA": ccp.Cluster{
+			Name:                 ccp.String("demo"),
+			RegistriesSelfSigned: &ccp.RegistriesSelfSigned{Cert: ccp.String(secret)},
+		},
+		"ACIProfile":  ccp.ACIProfile{Name: ccp.String("aci"), APICPassword: ccp.String(secret)},
+		"LoginCreds":  ccp.LoginCreds{Username: ccp.String("admin"), Password: ccp.String(secret)},
+		"User":        ccp.User{Username: ccp.String("bob"), Password: ccp.String(secret)},
+		"LDAPSetup":   ccp.LDAPSetup{Server: ccp.String("ldap"), ServiceAccountPassword: ccp.String(secret)},
+		"vSphere":     ccp.VsphereClientConfig{Username: ccp.String("vsphere"), Password: ccp.String(secret)},
+		"*Cluster":    &ccp.Cluster{Name: ccp.String("demo"), KubeConfig: ccp.String(secret)},
+		"*LoginCreds": &ccp.LoginCreds{Username: ccp.String("admin"), Password: ccp.String(secret)},
+	}
+	for name, model := range models {
+		for _, verb := range []string{"%v", "%+v", "%#v", "%s"} {
+			assertMasked(t, name+" "+verb, fmt.Sprintf(verb, model))
+		}
+	}
+}
+
+func TestClientStringHidesSecrets(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	client := newTestClient(t, srv)
+
+	for _, verb := range []string{"%v", "%+v", "%#v", "%s"} {
+		out := fmt.Sprintf(verb, client)
+		if strings.Contains(out, ccptest.Password) || strings.Contains(out, client.Token()) {
+			t.Errorf("%s of the client leaks the password or token: %s", verb, out)
+		}
+		if !strings.Contains(out, "Token: ") || strings.Contains(out, "XAuthToken") {
+			t.Errorf("%s of the client = %s, want the token labelled Token", verb, out)
+		}
+	}
+}
+
+func TestAPIErrorHidesEchoedSecrets(t *testing.T) {
+	bodies := map[string]string{
+		"validation fields": `{"name": ["already taken"], "password": "` + secret + `"}`,
+		"nested":            `{"cluster": {"kubeconfig": "` + secret + `"}}`,
+		"array":             `[{"ssh_key": "` + secret + `"}]`,
+	}
+	for name, body := range bodies {
+		t.Run(name, func(t *testing.T) {
+			srv := ccptest.NewServer()
+			defer srv.Close()
+			client := newTestClient(t, srv, ccp.WithRetryPolicy(nil))
+
+			srv.AddFault(ccptest.Fault{Method: http.MethodGet, Path: "/v3/clusters", Status: http.StatusBadRequest, Body: body})
+			_, err := client.GetClusters()
+
+			var apiErr *ccp.APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("GetClusters error = %v, want an *ccp.APIError", err)
+			}
+			assertMasked(t, "APIError.Error()", err.Error())
+			if string(apiErr.Body) != body {
+				t.Errorf("APIError.Body = %s, want the response as sent", apiErr.Body)
+			}
+		})
+	}
+}
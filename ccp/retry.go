@@ -0,0 +1,143 @@
+/*Copyright (c) 2019 Cisco and/or its affiliates.
+
+This software is licensed to you under the terms of the Cisco Sample
+Code License, Version 1.0 (the "License"). You may obtain a copy of the
+License at
+
+               https://developer.cisco.com/docs/licenses
+
+All use of the material herein must be in accordance with the terms of
+the License. All rights not expressly granted by the License are
+reserved. Unless required by applicable law or agreed to separately in
+writing, software distributed under the License is distributed on an "AS
+IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+or implied.*/
+
+package ccp
+
+import (
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryPolicy controls how requests that fail with a transient error are retried.
+// Transient errors are network errors and 429, 502, 503 and 504 responses
+type RetryPolicy struct {
+	MaxAttempts int           // attempts including the first one, 1 or less disables retries
+	BaseBackoff time.Duration // wait before the first retry, doubled on every retry after that
+	MaxBackoff  time.Duration // upper bound for the wait between attempts
+	Jitter      float64       // fraction of each wait that is randomised, 0 to 1
+
+	// RetryNonIdempotent also retries requests that may not be safe to send twice,
+	// such as the POST to /v3/clusters/ made by AddCluster. Off by default
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryPolicy returns the retry policy NewClient sets on a new Client
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 3,
+		BaseBackoff: 1 * time.Second,
+		MaxBackoff:  30 * time.Second,
+		Jitter:      0.2,
+	}
+}
+
+// shouldRetry reports whether req may be sent again after failing with err on the given attempt
+func (p *RetryPolicy) shouldRetry(req *http.Request, err error, attempt int) bool {
+	if p == nil || attempt >= p.MaxAttempts {
+		return false
+	}
+	// the caller gave up, don't keep trying
+	if req.Context().Err() != nil {
+		return false
+	}
+	// the body has to be readable a second time
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	if !p.RetryNonIdempotent && !isIdempotent(req) {
+		return false
+	}
+
+	// logging in again gave no token, trying again won't give one either
+	var authErr *AuthError
+	if errors.As(err, &authErr) && authErr.Err == nil {
+		return false
+	}
+
+	// the control plane is too old for this call, it won't grow the endpoint
+	if errors.Is(err, ErrUnsupported) {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+
+	// anything else came from the transport: connection reset, refused, timeout
+	return true
+}
+
+// backoff returns how long to wait before the next attempt.
+// A Retry-After header on the failed response takes precedence, up to MaxBackoff
+func (p *RetryPolicy) backoff(err error, attempt int) time.Duration {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		if wait, ok := parseRetryAfter(apiErr.Header.Get("Retry-After")); ok {
+			if p.MaxBackoff > 0 && wait > p.MaxBackoff {
+				wait = p.MaxBackoff
+			}
+			return wait
+		}
+	}
+
+	wait := p.BaseBackoff << uint(attempt-1)
+	if wait <= 0 || (p.MaxBackoff > 0 && wait > p.MaxBackoff) {
+		wait = p.MaxBackoff
+	}
+	if p.Jitter > 0 {
+		wait -= time.Duration(p.Jitter * rand.Float64() * float64(wait))
+	}
+	return wait
+}
+
+// isIdempotent reports whether sending req twice has the same effect as sending it once.
+// Only GET, DELETE and the node pool PATCH are retried, as the README says. Scaling a
+// node pool PATCHes an absolute size, so it is safe to repeat
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodDelete:
+		return true
+	case http.MethodPatch:
+		return strings.Contains(req.URL.Path, "/node-pools/")
+	}
+	return false
+}
+
+// parseRetryAfter reads a Retry-After header given either in seconds or as an HTTP date
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if when, err := http.ParseTime(value); err == nil {
+		wait := time.Until(when)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
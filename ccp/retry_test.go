@@ -0,0 +1,159 @@
+/*Copyright (c) 2019 Cisco and/or its affiliates.
+
+This software is licensed to you under the terms of the Cisco Sample
+Code License, Version 1.0 (the "License"). You may obtain a copy of the
+License at
+
+               https://developer.cisco.com/docs/licenses
+
+All use of the material herein must be in accordance with the terms of
+the License. All rights not expressly granted by the License are
+reserved. Unless required by applicable law or agreed to separately in
+writing, software distributed under the License is distributed on an "AS
+IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+or implied.*/
+
+package ccp_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rob-moss/ccp-clientlibrary-go/ccp"
+	"github.com/rob-moss/ccp-clientlibrary-go/ccp/ccptest"
+)
+
+// fastRetries retries like DefaultRetryPolicy without the long waits
+func fastRetries() ccp.Option {
+	return ccp.WithRetryPolicy(&ccp.RetryPolicy{MaxAttempts: 3, BaseBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond})
+}
+
+// addTestCluster stores a READY cluster with one worker pool called "workers"
+func addTestCluster(srv *ccptest.Server) ccp.Cluster {
+	return srv.AddCluster(ccp.Cluster{
+		Name:           ccp.String("retry-test"),
+		WorkerNodePool: &[]ccp.WorkerNodePool{{Name: ccp.String("workers"), Size: ccp.Int64(1)}},
+	})
+}
+
+func TestRetryTransientGET(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	client := newTestClient(t, srv, fastRetries())
+
+	srv.AddFault(ccptest.Fault{Method: http.MethodGet, Path: "/v3/clusters", Status: http.StatusServiceUnavailable, Times: 2})
+	if _, err := client.GetClusters(); err != nil {
+		t.Fatalf("GetClusters: %v", err)
+	}
+	if n := countRequests(srv, http.MethodGet, "/v3/clusters"); n != 3 {
+		t.Errorf("GET /v3/clusters sent %d times, want 3", n)
+	}
+}
+
+func TestRetryGivesUpAfterMaxAttempts(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	client := newTestClient(t, srv, fastRetries())
+
+	srv.AddFault(ccptest.Fault{Method: http.MethodGet, Path: "/v3/clusters", Status: http.StatusBadGateway})
+	_, err := client.GetClusters()
+
+	var apiErr *ccp.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadGateway {
+		t.Fatalf("GetClusters error = %v, want a 502 APIError", err)
+	}
+	if n := countRequests(srv, http.MethodGet, "/v3/clusters"); n != 3 {
+		t.Errorf("GET /v3/clusters sent %d times, want 3", n)
+	}
+}
+
+func TestRetryOnlyTransientStatuses(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	client := newTestClient(t, srv, fastRetries())
+
+	srv.AddFault(ccptest.Fault{Method: http.MethodGet, Path: "/v3/clusters", Status: http.StatusInternalServerError})
+	if _, err := client.GetClusters(); err == nil {
+		t.Fatal("GetClusters succeeded, want the 500")
+	}
+	if n := countRequests(srv, http.MethodGet, "/v3/clusters"); n != 1 {
+		t.Errorf("GET /v3/clusters sent %d times, want 1", n)
+	}
+}
+
+func TestRetryIdempotentMethodsOnly(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	client := newTestClient(t, srv, fastRetries())
+	c := addTestCluster(srv)
+	uuid := *c.UUID
+
+	// scaling PATCHes an absolute size, so it is retried
+	srv.AddFault(ccptest.Fault{Method: http.MethodPatch, Path: "/v3/clusters/" + uuid + "/node-pools/", Status: http.StatusServiceUnavailable, Times: 1})
+	if _, err := client.ScaleCluster(uuid, "workers", 3); err != nil {
+		t.Fatalf("ScaleCluster: %v", err)
+	}
+
+	// any other PATCH is not
+	srv.AddFault(ccptest.Fault{Method: http.MethodPatch, Path: "/v3/clusters/" + uuid + "/", Status: http.StatusServiceUnavailable, Times: 1})
+	if _, err := client.PatchCluster(&ccp.Cluster{Description: ccp.String("changed")}, uuid); err == nil {
+		t.Fatal("PatchCluster succeeded, want the 503")
+	}
+
+	if n := countRequests(srv, http.MethodPatch, "/v3/clusters/"+uuid+"/node-pools/workers/"); n != 2 {
+		t.Errorf("node pool PATCH sent %d times, want 2", n)
+	}
+	if n := countRequests(srv, http.MethodPatch, "/v3/clusters/"+uuid+"/"); n != 1 {
+		t.Errorf("cluster PATCH sent %d times, want 1", n)
+	}
+}
+
+func TestRetryNonIdempotentOptIn(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	client := newTestClient(t, srv, ccp.WithRetryPolicy(&ccp.RetryPolicy{MaxAttempts: 2, BaseBackoff: time.Millisecond, RetryNonIdempotent: true}))
+	c := addTestCluster(srv)
+
+	srv.AddFault(ccptest.Fault{Method: http.MethodPatch, Path: "/v3/clusters/" + *c.UUID + "/", Status: http.StatusServiceUnavailable, Times: 1})
+	if _, err := client.PatchCluster(&ccp.Cluster{Description: ccp.String("changed")}, *c.UUID); err != nil {
+		t.Fatalf("PatchCluster: %v", err)
+	}
+}
+
+func TestRetryAfterCappedAtMaxBackoff(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+
+	// the first cluster list is told to come back in an hour
+	var busy bool
+	retryAfter := func(next ccp.RoundTripFunc) ccp.RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			if req.Method == http.MethodGet && req.URL.Path == "/v3/clusters" && !busy {
+				busy = true
+				return &http.Response{
+					StatusCode: http.StatusServiceUnavailable,
+					Header:     http.Header{"Retry-After": {"3600"}},
+					Body:       io.NopCloser(strings.NewReader("")),
+					Request:    req,
+				}, nil
+			}
+			return next(req)
+		}
+	}
+	client := newTestClient(t, srv, fastRetries(), ccp.WithInterceptors(retryAfter))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	start := time.Now()
+	if _, err := client.GetClustersWithContext(ctx); err != nil {
+		t.Fatalf("GetClusters: %v, want the retry to wait MaxBackoff rather than Retry-After", err)
+	}
+	if took := time.Since(start); took > time.Second {
+		t.Errorf("GetClusters took %v, want about MaxBackoff", took)
+	}
+}
@@ -17,86 +17,92 @@ package ccp
 
 import (
 	"bytes"
-	"crypto/tls"
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
 	"net/http"
+	neturl "net/url"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
 )
 
-// type LivenessHealth struct {
-// 	CXVersion      *string `json:"CXVersion,omitempty"`
-// 	TimeOnMgmtHost *string `json:"TimeOnMgmtHost,omitempty"`
-// }
-
-// type Health struct {
-// 	TotalSystemHealth *string          `json:"TotalSystemHealth,omitempty"`
-// 	CurrentNodes      *int64           `json:"CurrentNodes,omitempty"`
-// 	ExpectedNodes     *int64           `json:"ExpectedNodes,omitempty"`
-// 	NodesStatus       *[]NodeStatus    `json:"NodesStatus,omitempty"`
-// 	PodStatusList     *[]PodStatusList `json:"PodStatusList,omitempty"`
-// }
-
-// type NodeStatus struct {
-// 	NodeName           *string `json:"NodeName,omitempty"`
-// 	NodeCondition      *string `json:"NodeCondition,omitempty"`
-// 	NodeStatus         *string `json:"NodeStatus,omitempty"`
-// 	LastTransitionTime *string `json:"LastTransitionTime,omitempty"`
-// }
-
-// type PodStatusList struct {
-// 	PodName            *string `json:"PodName,omitempty"`
-// 	PodCondition       *string `json:"PodCondition,omitempty"`
-// 	PodStatus          *string `json:"PodStatus,omitempty"`
-// 	LastTransitionTime *string `json:"LastTransitionTime,omitempty"`
-// }
-
-// Login for v2
-// func (s *Client) Login(client *Client) error {
-//
-// 	url := fmt.Sprintf(s.BaseURL + "/2/system/login?username=" + client.Username + "&password=" + client.Password)
-//
-// 	j, err := json.Marshal(client)
-//
-// 	if err != nil {
-// 		return err
-// 	}
-//
-// 	req, err := http.NewRequest("POST", url, bytes.NewBuffer(j))
-// 	if err != nil {
-// 		return err
-// 	}
-//
-// 	_, err = s.doRequest(req)
-//
-// 	if err != nil {
-// 		return err
-// 	}
-//
-// 	return nil
-// }
-
-// func (s *Client) GetLivenessHealth() (*LivenessHealth, error) {
-//
-// 	url := fmt.Sprintf(s.BaseURL + "/2/system/livenessHealth")
-//
-// 	req, err := http.NewRequest("GET", url, nil)
-// 	if err != nil {
-// 		return nil, err
-// 	}
-// 	bytes, err := s.doRequest(req)
-// 	if err != nil {
-// 		return nil, err
-// 	}
-// 	var data LivenessHealth
-//
-// 	err = json.Unmarshal(bytes, &data)
-// 	if err != nil {
-// 		return nil, err
-// 	}
-//
-// 	health := &data
-//
-// 	return health, nil
-// }
+// LivenessHealth is the control plane's answer to a liveness probe
+type LivenessHealth struct {
+	CXVersion      *string `json:"CXVersion,omitempty"`
+	TimeOnMgmtHost *string `json:"TimeOnMgmtHost,omitempty"`
+}
+
+// Health is the health of the control plane's own nodes and pods
+type Health struct {
+	TotalSystemHealth *string          `json:"TotalSystemHealth,omitempty"`
+	CurrentNodes      *int64           `json:"CurrentNodes,omitempty"`
+	ExpectedNodes     *int64           `json:"ExpectedNodes,omitempty"`
+	NodesStatus       *[]NodeStatus    `json:"NodesStatus,omitempty"`
+	PodStatusList     *[]PodStatusList `json:"PodStatusList,omitempty"`
+}
+
+// NodeStatus is one condition of a control plane node, NodeStatus is "True" when it holds
+type NodeStatus struct {
+	NodeName           *string `json:"NodeName,omitempty"`
+	NodeCondition      *string `json:"NodeCondition,omitempty"`
+	NodeStatus         *string `json:"NodeStatus,omitempty"`
+	LastTransitionTime *string `json:"LastTransitionTime,omitempty"`
+}
+
+// PodStatusList is one condition of a control plane pod, PodStatus is "True" when it holds
+type PodStatusList struct {
+	PodName            *string `json:"PodName,omitempty"`
+	PodCondition       *string `json:"PodCondition,omitempty"`
+	PodStatus          *string `json:"PodStatus,omitempty"`
+	LastTransitionTime *string `json:"LastTransitionTime,omitempty"`
+}
+
+// Version is the version of the control plane software
+type Version struct {
+	CXVersion *string `json:"CXVersion,omitempty"`
+}
+
+// Problems lists what is wrong with the control plane, nothing when it is healthy
+func (h *Health) Problems() []string {
+	var problems []string
+	if h.TotalSystemHealth != nil && !strings.EqualFold(*h.TotalSystemHealth, "Healthy") {
+		problems = append(problems, "system health is "+*h.TotalSystemHealth)
+	}
+	if h.CurrentNodes != nil && h.ExpectedNodes != nil && *h.CurrentNodes < *h.ExpectedNodes {
+		problems = append(problems, fmt.Sprintf("%d of %d nodes up", *h.CurrentNodes, *h.ExpectedNodes))
+	}
+	if h.NodesStatus != nil {
+		for _, n := range *h.NodesStatus {
+			if n.NodeCondition != nil && *n.NodeCondition == "Ready" && (n.NodeStatus == nil || *n.NodeStatus != "True") {
+				problems = append(problems, "node "+derefString(n.NodeName)+" is not ready")
+			}
+		}
+	}
+	if h.PodStatusList != nil {
+		for _, p := range *h.PodStatusList {
+			if p.PodCondition != nil && *p.PodCondition == "Ready" && (p.PodStatus == nil || *p.PodStatus != "True") {
+				problems = append(problems, "pod "+derefString(p.PodName)+" is not ready")
+			}
+		}
+	}
+	return problems
+}
+
+// Healthy reports whether Problems finds nothing wrong
+func (h *Health) Healthy() bool {
+	return len(h.Problems()) == 0
+}
+
+// derefString returns *p, or "" for nil
+func derefString(p *string) string {
+	if p == nil {
+		return ""
+	}
+	return *p
+}
 
 // LoginCreds for provider
 type LoginCreds struct {
@@ -110,43 +116,55 @@ type LoginCreds struct {
 //                        ^ input arg
 //                                        ^ return
 
-// Login updated for v3
+// Login updated for v3, on a v2 control plane it posts the v2 login form instead.
+// It always sends the username and password, ResumeSession takes a saved token instead
 func (s *Client) Login(client *Client) error {
+	return s.LoginWithContext(context.Background(), client)
+}
+
+// LoginWithContext is Login with a context that can cancel the call
+func (s *Client) LoginWithContext(ctx context.Context, client *Client) (err error) {
+	ctx, span := s.startOperation(ctx, "Login", attribute.String("ccp.user", client.Username))
+	defer func() { endOperation(span, err) }()
+
+	caps, err := s.Capabilities(ctx)
+	if err != nil {
+		return err
+	}
 
 	url := s.BaseURL + "/v3/system/login"
+	contentType := "application/json"
 
 	loginCreds := LoginCreds{
 		Username: String(client.Username),
 		Password: String(client.Password),
 	}
 
-	//var client *http.Client
-
-	var PTransport = &http.Transport{
-		Proxy:           http.ProxyFromEnvironment,
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-	}
-	cl := http.Client{
-		Transport: PTransport,
-	}
-
 	// Marshal the JSON payload to then send
 	j, err := json.Marshal(loginCreds)
 	if err != nil {
 		return err
 	}
 
+	// the v2 API takes a form and answers with a session cookie
+	if caps.APIVersion == APIv2 {
+		url = s.BaseURL + "/2/system/login/"
+		contentType = "application/x-www-form-urlencoded"
+		j = []byte(neturl.Values{"username": {client.Username}, "password": {client.Password}}.Encode())
+	}
+
 	// print the JSON query
 	//	fmt.Println(string(j))
 	// Send the JSON payload
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(j))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(j))
 	if err != nil {
-		Debug(1, "Error logging in: "+err.Error())
+		s.log().DebugContext(ctx, "error logging in", "user", client.Username, "error", err)
 		return err
 	}
-	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("Content-Type", contentType)
+	req.Header.Set("User-Agent", s.userAgentString())
 
-	resp, err := cl.Do(req)
+	resp, err := s.roundTrip(req)
 	// if err != nil {
 	// 	Debug(1, "Error logging in: "+err.Error())
 	// 	// Debug(1, "Response: "+ioutil.ReadAll(resp.Body))
@@ -156,9 +174,9 @@ func (s *Client) Login(client *Client) error {
 	// }
 
 	if err == nil {
-		Debug(1, "Logged in as user "+client.Username)
+		s.log().DebugContext(ctx, "login response", "user", client.Username, "status", resp.StatusCode)
 	} else {
-		Debug(1, "Error logging in: "+err.Error())
+		s.log().DebugContext(ctx, "error logging in", "user", client.Username, "error", err)
 		// Debug(1, "Response: "+ioutil.ReadAll(resp.Body))
 		return err
 	}
@@ -166,12 +184,27 @@ func (s *Client) Login(client *Client) error {
 	// fmt.Println(resp)
 	// fmt.Println("Geting X-Auth-Token")
 
+	defer resp.Body.Close()
+
+	// a wrong password comes back as a 401 with no token, don't carry on without a session
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return &AuthError{Username: client.Username, URL: s.BaseURL, Reason: "login refused", Err: newAPIError(req, resp, body)}
+	}
 	var xauthtoken = resp.Header.Get("X-Auth-Token")
-	Debug(1, "X-Auth-Token = "+xauthtoken)
+	if xauthtoken == "" && caps.APIVersion == APIv2 {
+		// the session cookie is already in the client's jar
+		s.log().DebugContext(ctx, "logged in with a session cookie", "user", client.Username)
+		s.SetToken("")
+		return nil
+	}
+	if xauthtoken == "" {
+		return &AuthError{Username: client.Username, URL: s.BaseURL, Reason: "no X-Auth-Token in the response"}
+	}
+	s.log().DebugContext(ctx, "got X-Auth-Token", "user", client.Username)
 	// set xauth
-	s.XAuthToken = xauthtoken
-
-	defer resp.Body.Close()
+	s.SetToken(xauthtoken)
+	s.storeToken(ctx, client.Username, xauthtoken)
 	// if err != nil {
 	// 	return err
 	// }
@@ -192,51 +225,198 @@ func (s *Client) Login(client *Client) error {
 	return nil
 }
 
-// // GetLivenessHealth foobar
-// func (s *Client) GetLivenessHealth() (*LivenessHealth, error) {
+// Session is the user behind the client's X-Auth-Token
+type Session struct {
+	UserID    *string `json:"id,omitempty"`
+	Username  *string `json:"username,omitempty"`
+	FirstName *string `json:"firstName,omitempty"`
+	LastName  *string `json:"lastName,omitempty"`
+	Role      *string `json:"role,omitempty"`
+	Valid     bool    `json:"-"` // whether the control plane accepted the token
+}
+
+// Logout ends the session on the control plane and forgets the token, here and in the token store.
+// A token that had already expired is not an error. v2 control planes have no logout,
+// so there Logout only drops the session cookie
+func (s *Client) Logout() error {
+	return s.LogoutWithContext(context.Background())
+}
+
+// LogoutWithContext is Logout with a context that can cancel the call
+func (s *Client) LogoutWithContext(ctx context.Context) (err error) {
+	ctx, span := s.startOperation(ctx, "Logout", attribute.String("ccp.user", s.Username))
+	defer func() { endOperation(span, err) }()
+
+	token := s.Token()
+	defer func() {
+		s.SetToken("")
+		s.cache.clear()
+		if s.tokenStore != nil {
+			if err := s.tokenStore.Delete(ctx, TokenKey(s.BaseURL, s.Username)); err != nil {
+				s.log().WarnContext(ctx, "cannot delete saved X-Auth-Token", "user", s.Username, "error", err)
+			}
+		}
+	}()
+	// v2 has no logout endpoint, the session ends when its cookie is dropped
+	if caps, err := s.Capabilities(ctx); err == nil && caps.APIVersion == APIv2 {
+		s.dropCookies()
+		return nil
+	}
+	if token == "" {
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", s.BaseURL+"/v3/system/logout", nil)
+	if err != nil {
+		return err
+	}
+	// sent as is, logging in again just to log out makes no sense
+	_, err = s.sendRequest(req, token)
+	if errors.Is(err, ErrUnauthorized) {
+		return nil
+	}
+	return err
+}
+
+// WhoAmI returns the user and role of the current session, logging in again if the token expired
+func (s *Client) WhoAmI() (*Session, error) {
+	return s.WhoAmIWithContext(context.Background())
+}
+
+// WhoAmIWithContext is WhoAmI with a context that can cancel the call
+func (s *Client) WhoAmIWithContext(ctx context.Context) (_ *Session, err error) {
+	ctx, span := s.startOperation(ctx, "WhoAmI")
+	defer func() { endOperation(span, err) }()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", s.BaseURL+"/v3/system/whoami", nil)
+	if err != nil {
+		return nil, err
+	}
+	bytes, err := s.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var data Session
+	err = json.Unmarshal(bytes, &data)
+	if err != nil {
+		return nil, err
+	}
+	data.Valid = true
+
+	return &data, nil
+}
+
+// ValidateSession checks the current token without logging in again. An expired or
+// missing token gives a Session with Valid false and no error, so tools can stop early
+func (s *Client) ValidateSession() (*Session, error) {
+	return s.ValidateSessionWithContext(context.Background())
+}
+
+// ValidateSessionWithContext is ValidateSession with a context that can cancel the call
+func (s *Client) ValidateSessionWithContext(ctx context.Context) (_ *Session, err error) {
+	ctx, span := s.startOperation(ctx, "ValidateSession")
+	defer func() { endOperation(span, err) }()
+
+	return s.checkToken(ctx, s.Token())
+}
+
+// checkToken asks the control plane who token belongs to, without logging in again if it is rejected
+func (s *Client) checkToken(ctx context.Context, token string) (*Session, error) {
+	if token == "" {
+		return &Session{}, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", s.BaseURL+"/v3/system/whoami", nil)
+	if err != nil {
+		return nil, err
+	}
+	bytes, err := s.sendRequest(req, token)
+	if errors.Is(err, ErrUnauthorized) {
+		return &Session{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var data Session
+	err = json.Unmarshal(bytes, &data)
+	if err != nil {
+		return nil, err
+	}
+	data.Valid = true
 
-// 	url := fmt.Sprintf(s.BaseURL + "/2/system/livenessHealth")
+	return &data, nil
+}
 
-// 	req, err := http.NewRequest("GET", url, nil)
-// 	if err != nil {
-// 		return nil, err
-// 	}
-// 	bytes, err := s.doRequest(req)
-// 	if err != nil {
-// 		return nil, err
-// 	}
-// 	var data LivenessHealth
+// GetLivenessHealth asks the control plane whether it is up
+func (s *Client) GetLivenessHealth() (*LivenessHealth, error) {
+	return s.GetLivenessHealthWithContext(context.Background())
+}
 
-// 	err = json.Unmarshal(bytes, &data)
-// 	if err != nil {
-// 		return nil, err
-// 	}
+// GetLivenessHealthWithContext is GetLivenessHealth with a context that can cancel the call
+func (s *Client) GetLivenessHealthWithContext(ctx context.Context) (_ *LivenessHealth, err error) {
+	ctx, span := s.startOperation(ctx, "GetLivenessHealth")
+	defer func() { endOperation(span, err) }()
 
-// 	health := &data
+	var data LivenessHealth
+	if err := s.getSystem(ctx, "/v3/system/livenessHealth", &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
 
-// 	return health, nil
-// }
+// GetHealth returns the health of the control plane's nodes and pods
+func (s *Client) GetHealth() (*Health, error) {
+	return s.GetHealthWithContext(context.Background())
+}
 
-// func (s *Client) GetHealth() (*Health, error) {
+// GetHealthWithContext is GetHealth with a context that can cancel the call
+func (s *Client) GetHealthWithContext(ctx context.Context) (_ *Health, err error) {
+	ctx, span := s.startOperation(ctx, "GetHealth")
+	defer func() { endOperation(span, err) }()
 
-// 	url := fmt.Sprintf(s.BaseURL + "/2/system/health")
+	var data Health
+	if err := s.getSystem(ctx, "/v3/system/health", &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
 
-// 	req, err := http.NewRequest("GET", url, nil)
-// 	if err != nil {
-// 		return nil, err
-// 	}
-// 	bytes, err := s.doRequest(req)
-// 	if err != nil {
-// 		return nil, err
-// 	}
-// 	var data Health
+// GetVersion returns the version of the control plane
+func (s *Client) GetVersion() (*Version, error) {
+	return s.GetVersionWithContext(context.Background())
+}
 
-// 	err = json.Unmarshal(bytes, &data)
-// 	if err != nil {
-// 		return nil, err
-// 	}
+// GetVersionWithContext is GetVersion with a context that can cancel the call
+func (s *Client) GetVersionWithContext(ctx context.Context) (_ *Version, err error) {
+	ctx, span := s.startOperation(ctx, "GetVersion")
+	defer func() { endOperation(span, err) }()
+
+	// v2 has no version endpoint, livenessHealth already told us if it said
+	if caps, err := s.Capabilities(ctx); err == nil && caps.APIVersion == APIv2 {
+		if caps.CXVersion == "" {
+			return nil, unsupported(ctx, caps, "reading the version")
+		}
+		return &Version{CXVersion: String(caps.CXVersion)}, nil
+	}
 
-// 	health := &data
+	var data Version
+	if err := s.getSystem(ctx, "/v3/system/version", &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
 
-// 	return health, nil
-// }
+// getSystem GETs one of the /v3/system endpoints into v
+func (s *Client) getSystem(ctx context.Context, path string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", s.BaseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	bytes, err := s.doRequest(req)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(bytes, v)
+}
@@ -0,0 +1,298 @@
+/*Copyright (c) 2019 Cisco and/or its affiliates.
+
+This software is licensed to you under the terms of the Cisco Sample
+Code License, Version 1.0 (the "License"). You may obtain a copy of the
+License at
+
+               https://developer.cisco.com/docs/licenses
+
+All use of the material herein must be in accordance with the terms of
+the License. All rights not expressly granted by the License are
+reserved. Unless required by applicable law or agreed to separately in
+writing, software distributed under the License is distributed on an "AS
+IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+or implied.*/
+
+package ccp_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/rob-moss/ccp-clientlibrary-go/ccp"
+	"github.com/rob-moss/ccp-clientlibrary-go/ccp/ccptest"
+)
+
+func TestLoginWrongPassword(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+
+	for _, creds := range [][2]string{{ccptest.Username, "wrong"}, {"nobody", ccptest.Password}} {
+		client, err := ccp.NewClient(srv.URL, ccp.WithCredentials(creds[0], creds[1]))
+		if err != nil {
+			t.Fatalf("NewClient: %v", err)
+		}
+		err = client.Login(client)
+
+		var authErr *ccp.AuthError
+		if !errors.As(err, &authErr) || authErr.Username != creds[0] {
+			t.Errorf("Login as %s error = %v, want an *ccp.AuthError", creds[0], err)
+		}
+		if !errors.Is(err, ccp.ErrUnauthorized) {
+			t.Errorf("Login as %s error = %v, want it to match ErrUnauthorized", creds[0], err)
+		}
+		if client.Token() != "" {
+			t.Errorf("Login as %s left token %q", creds[0], client.Token())
+		}
+	}
+}
+
+func TestLoginWithoutToken(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+
+	// a proxy in the way answers the login with a 200 and no token
+	strip := func(next ccp.RoundTripFunc) ccp.RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			resp, err := next(req)
+			if err == nil {
+				resp.Header.Del("X-Auth-Token")
+			}
+			return resp, err
+		}
+	}
+	client, err := ccp.NewClient(srv.URL, ccp.WithCredentials(ccptest.Username, ccptest.Password), ccp.WithInterceptors(strip))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	err = client.Login(client)
+
+	var authErr *ccp.AuthError
+	if !errors.As(err, &authErr) || authErr.Err != nil {
+		t.Fatalf("Login error = %v, want an *ccp.AuthError without an APIError", err)
+	}
+	if !errors.Is(err, ccp.ErrUnauthorized) {
+		t.Errorf("Login error = %v, want it to match ErrUnauthorized", err)
+	}
+	if client.Token() != "" {
+		t.Errorf("Login left token %q", client.Token())
+	}
+}
+
+func TestLogout(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+
+	store := ccp.NewMemoryTokenStore()
+	client := newTestClient(t, srv, ccp.WithTokenStore(store))
+	token := client.Token()
+	key := ccp.TokenKey(srv.URL, ccptest.Username)
+	if saved, _ := store.Load(context.Background(), key); saved != token {
+		t.Fatalf("saved token %q, want %q", saved, token)
+	}
+
+	if err := client.Logout(); err != nil {
+		t.Fatalf("Logout: %v", err)
+	}
+	if n := countRequests(srv, http.MethodPost, "/v3/system/logout"); n != 1 {
+		t.Errorf("sent %d logouts, want 1", n)
+	}
+	if client.Token() != "" {
+		t.Errorf("token after Logout = %q, want none", client.Token())
+	}
+	if saved, _ := store.Load(context.Background(), key); saved != "" {
+		t.Errorf("saved token after Logout = %q, want none", saved)
+	}
+
+	// the control plane no longer takes the old token either
+	client.SetToken(token)
+	if session, err := client.ValidateSession(); err != nil || session.Valid {
+		t.Errorf("ValidateSession of the logged out token = %+v, %v, want invalid", session, err)
+	}
+}
+
+func TestLogoutExpiredToken(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	client := newTestClient(t, srv)
+
+	srv.ExpireTokens()
+	if err := client.Logout(); err != nil {
+		t.Fatalf("Logout with an expired token: %v", err)
+	}
+	if n := countRequests(srv, http.MethodPost, "/v3/system/login"); n != 0 {
+		t.Errorf("logged in %d times to log out, want 0", n)
+	}
+	if client.Token() != "" {
+		t.Errorf("token after Logout = %q, want none", client.Token())
+	}
+}
+
+func TestWhoAmI(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	client := newTestClient(t, srv)
+
+	session, err := client.WhoAmI()
+	if err != nil {
+		t.Fatalf("WhoAmI: %v", err)
+	}
+	if !session.Valid || session.Username == nil || *session.Username != ccptest.Username || session.Role == nil || *session.Role != ccp.RoleAdministrator {
+		t.Errorf("WhoAmI = %+v, want a valid session for %s as %s", session, ccptest.Username, ccp.RoleAdministrator)
+	}
+}
+
+func TestWhoAmIExpiredToken(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	client := newTestClient(t, srv)
+
+	// WhoAmI logs in again like any other call
+	srv.ExpireTokens()
+	session, err := client.WhoAmI()
+	if err != nil || !session.Valid {
+		t.Fatalf("WhoAmI after the token expired = %+v, %v, want a valid session", session, err)
+	}
+	if n := countRequests(srv, http.MethodPost, "/v3/system/login"); n != 1 {
+		t.Errorf("logged in %d times, want 1", n)
+	}
+}
+
+func TestValidateSession(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	client := newTestClient(t, srv)
+
+	session, err := client.ValidateSession()
+	if err != nil || !session.Valid || session.Username == nil || *session.Username != ccptest.Username {
+		t.Fatalf("ValidateSession = %+v, %v, want a valid session for %s", session, err, ccptest.Username)
+	}
+
+	// an expired token is reported, not replaced
+	srv.ExpireTokens()
+	token := client.Token()
+	session, err = client.ValidateSession()
+	if err != nil || session.Valid {
+		t.Errorf("ValidateSession of an expired token = %+v, %v, want invalid and no error", session, err)
+	}
+	if n := countRequests(srv, http.MethodPost, "/v3/system/login"); n != 0 {
+		t.Errorf("logged in %d times, want 0", n)
+	}
+	if client.Token() != token {
+		t.Error("ValidateSession replaced the token")
+	}
+}
+
+func TestValidateSessionWithoutToken(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+
+	client, err := ccp.NewClient(srv.URL, ccp.WithCredentials(ccptest.Username, ccptest.Password))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	session, err := client.ValidateSession()
+	if err != nil || session.Valid {
+		t.Errorf("ValidateSession before Login = %+v, %v, want invalid and no error", session, err)
+	}
+	if n := len(srv.Requests()); n != 0 {
+		t.Errorf("sent %d requests, want none", n)
+	}
+}
+
+func TestHealthProblems(t *testing.T) {
+	ready := func(name, status string) ccp.NodeStatus {
+		return ccp.NodeStatus{NodeName: ccp.String(name), NodeCondition: ccp.String("Ready"), NodeStatus: ccp.String(status)}
+	}
+	pod := func(name, status string) ccp.PodStatusList {
+		return ccp.PodStatusList{PodName: ccp.String(name), PodCondition: ccp.String("Ready"), PodStatus: ccp.String(status)}
+	}
+
+	tests := []struct {
+		name   string
+		health ccp.Health
+		want   []string
+	}{
+		{"empty", ccp.Health{}, nil},
+		{"healthy", ccp.Health{
+			TotalSystemHealth: ccp.String("Healthy"),
+			CurrentNodes:      ccp.Int64(3),
+			ExpectedNodes:     ccp.Int64(3),
+			NodesStatus:       &[]ccp.NodeStatus{ready("master-1", "True")},
+			PodStatusList:     &[]ccp.PodStatusList{pod("ccp-api", "True")},
+		}, nil},
+		{"healthy in lower case", ccp.Health{TotalSystemHealth: ccp.String("healthy")}, nil},
+		{"system degraded", ccp.Health{TotalSystemHealth: ccp.String("Degraded")}, []string{"system health is Degraded"}},
+		{"nodes down", ccp.Health{CurrentNodes: ccp.Int64(2), ExpectedNodes: ccp.Int64(3)}, []string{"2 of 3 nodes up"}},
+		{"node count missing", ccp.Health{CurrentNodes: ccp.Int64(2)}, nil},
+		{"node not ready", ccp.Health{NodesStatus: &[]ccp.NodeStatus{ready("master-1", "True"), ready("master-2", "False")}}, []string{"node master-2 is not ready"}},
+		{"node status missing", ccp.Health{NodesStatus: &[]ccp.NodeStatus{{NodeName: ccp.String("master-3"), NodeCondition: ccp.String("Ready")}}}, []string{"node master-3 is not ready"}},
+		{"node name missing", ccp.Health{NodesStatus: &[]ccp.NodeStatus{{NodeCondition: ccp.String("Ready"), NodeStatus: ccp.String("False")}}}, []string{"node  is not ready"}},
+		{"other node condition", ccp.Health{NodesStatus: &[]ccp.NodeStatus{{NodeName: ccp.String("master-1"), NodeCondition: ccp.String("DiskPressure"), NodeStatus: ccp.String("False")}}}, nil},
+		{"node condition missing", ccp.Health{NodesStatus: &[]ccp.NodeStatus{{NodeName: ccp.String("master-1")}}}, nil},
+		{"pod not ready", ccp.Health{PodStatusList: &[]ccp.PodStatusList{pod("ccp-api", "False")}}, []string{"pod ccp-api is not ready"}},
+		{"everything wrong", ccp.Health{
+			TotalSystemHealth: ccp.String("Unhealthy"),
+			CurrentNodes:      ccp.Int64(1),
+			ExpectedNodes:     ccp.Int64(3),
+			NodesStatus:       &[]ccp.NodeStatus{ready("master-1", "False")},
+			PodStatusList:     &[]ccp.PodStatusList{pod("ccp-api", "Unknown")},
+		}, []string{"system health is Unhealthy", "1 of 3 nodes up", "node master-1 is not ready", "pod ccp-api is not ready"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.health.Problems()
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("Problems() = %q, want %q", got, tt.want)
+			}
+			if healthy := tt.health.Healthy(); healthy != (len(tt.want) == 0) {
+				t.Errorf("Healthy() = %v with problems %q", healthy, got)
+			}
+		})
+	}
+}
+
+func TestGetHealth(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	client := newTestClient(t, srv)
+
+	health, err := client.GetHealth()
+	if err != nil || !health.Healthy() {
+		t.Fatalf("GetHealth of a new server = %+v, %v, want healthy", health, err)
+	}
+
+	srv.SetHealth(ccp.Health{TotalSystemHealth: ccp.String("Degraded"), CurrentNodes: ccp.Int64(2), ExpectedNodes: ccp.Int64(3)})
+	health, err = client.GetHealth()
+	if err != nil {
+		t.Fatalf("GetHealth: %v", err)
+	}
+	if got := health.Problems(); len(got) != 2 {
+		t.Errorf("Problems() = %q, want the system health and the missing node", got)
+	}
+}
+
+func TestGetVersionMissing(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	client := newTestClient(t, srv)
+
+	version, err := client.GetVersion()
+	if err != nil || version.CXVersion == nil || *version.CXVersion != ccptest.DefaultVersion {
+		t.Fatalf("GetVersion = %+v, %v, want %s", version, err, ccptest.DefaultVersion)
+	}
+
+	// a control plane may leave the version out, callers must check for nil
+	srv.AddFault(ccptest.Fault{Method: http.MethodGet, Path: "/v3/system/version", Status: http.StatusOK, Body: `{}`})
+	version, err = client.GetVersion()
+	if err != nil {
+		t.Fatalf("GetVersion: %v", err)
+	}
+	if version.CXVersion != nil {
+		t.Errorf("CXVersion = %q, want nil when the control plane leaves it out", *version.CXVersion)
+	}
+}
@@ -0,0 +1,128 @@
+/*Copyright (c) 2019 Cisco and/or its affiliates.
+
+This software is licensed to you under the terms of the Cisco Sample
+Code License, Version 1.0 (the "License"). You may obtain a copy of the
+License at
+
+               https://developer.cisco.com/docs/licenses
+
+All use of the material herein must be in accordance with the terms of
+the License. All rights not expressly granted by the License are
+reserved. Unless required by applicable law or agreed to separately in
+writing, software distributed under the License is distributed on an "AS
+IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+or implied.*/
+
+package ccp
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// clientTLSConfig returns the TLS config shared by Login and every other request,
+// creating it on first use
+func (s *Client) clientTLSConfig() *tls.Config {
+	if s.tlsConfig == nil {
+		s.tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	return s.tlsConfig
+}
+
+// WithCACertFile trusts the CA certificates in the PEM file at path, in addition to the system roots
+func WithCACertFile(path string) Option {
+	return func(s *Client) error {
+		pem, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return WithCACertPEM(pem)(s)
+	}
+}
+
+// WithCACertPEM trusts the PEM encoded CA certificates, in addition to the system roots
+func WithCACertPEM(pem []byte) Option {
+	return func(s *Client) error {
+		cfg := s.clientTLSConfig()
+		if cfg.RootCAs == nil {
+			pool, err := x509.SystemCertPool()
+			if err != nil || pool == nil {
+				pool = x509.NewCertPool()
+			}
+			cfg.RootCAs = pool
+		}
+		if !cfg.RootCAs.AppendCertsFromPEM(pem) {
+			return errors.New("no CA certificates found in PEM data")
+		}
+		return nil
+	}
+}
+
+// WithClientCertificateFile presents the certificate and key in the PEM files for mutual TLS
+func WithClientCertificateFile(certFile, keyFile string) Option {
+	return func(s *Client) error {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return err
+		}
+		cfg := s.clientTLSConfig()
+		cfg.Certificates = append(cfg.Certificates, cert)
+		return nil
+	}
+}
+
+// WithClientCertificatePEM presents the PEM encoded certificate and key for mutual TLS
+func WithClientCertificatePEM(certPEM, keyPEM []byte) Option {
+	return func(s *Client) error {
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return err
+		}
+		cfg := s.clientTLSConfig()
+		cfg.Certificates = append(cfg.Certificates, cert)
+		return nil
+	}
+}
+
+// WithPinnedCertificate accepts only a server whose leaf certificate has the given
+// SHA-256 fingerprint, written as hex with or without colons. The certificate chain
+// is not checked, which suits control planes with a self-signed certificate
+func WithPinnedCertificate(fingerprint string) Option {
+	return func(s *Client) error {
+		pin, err := hex.DecodeString(strings.ReplaceAll(fingerprint, ":", ""))
+		if err != nil || len(pin) != sha256.Size {
+			return fmt.Errorf("invalid SHA-256 certificate fingerprint %q", fingerprint)
+		}
+
+		cfg := s.clientTLSConfig()
+		// the pin replaces chain verification, VerifyConnection below does the check
+		cfg.InsecureSkipVerify = true
+		cfg.VerifyConnection = func(cs tls.ConnectionState) error {
+			if len(cs.PeerCertificates) == 0 {
+				return errors.New("server presented no certificate")
+			}
+			sum := sha256.Sum256(cs.PeerCertificates[0].Raw)
+			if subtle.ConstantTimeCompare(sum[:], pin) != 1 {
+				return fmt.Errorf("server certificate fingerprint %s does not match the pinned fingerprint", hex.EncodeToString(sum[:]))
+			}
+			return nil
+		}
+		return nil
+	}
+}
+
+// WithInsecureSkipVerify turns off server certificate verification.
+// Only use it against a control plane you trust on a network you trust
+func WithInsecureSkipVerify() Option {
+	return func(s *Client) error {
+		s.clientTLSConfig().InsecureSkipVerify = true
+		return nil
+	}
+}
@@ -0,0 +1,190 @@
+/*Copyright (c) 2019 Cisco and/or its affiliates.
+
+This software is licensed to you under the terms of the Cisco Sample
+Code License, Version 1.0 (the "License"). You may obtain a copy of the
+License at
+
+               https://developer.cisco.com/docs/licenses
+
+All use of the material herein must be in accordance with the terms of
+the License. All rights not expressly granted by the License are
+reserved. Unless required by applicable law or agreed to separately in
+writing, software distributed under the License is distributed on an "AS
+IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+or implied.*/
+
+package ccp_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rob-moss/ccp-clientlibrary-go/ccp"
+	"github.com/rob-moss/ccp-clientlibrary-go/ccp/ccptest"
+)
+
+// fingerprint returns the SHA-256 fingerprint of the server certificate, colon separated
+func fingerprint(srv *ccptest.Server) string {
+	sum := sha256.Sum256(srv.Certificate().Raw)
+	var parts []string
+	for _, b := range sum {
+		parts = append(parts, hex.EncodeToString([]byte{b}))
+	}
+	return strings.Join(parts, ":")
+}
+
+// serverCAPEM returns the server's self-signed certificate as a PEM CA bundle
+func serverCAPEM(srv *ccptest.Server) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+}
+
+// newClientCert returns a self-signed client certificate and key as PEM, and a pool that trusts it
+func newClientCert(t *testing.T) ([]byte, []byte, *x509.CertPool) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ccp-client"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("MarshalECPrivateKey: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("ParseCertificate: %v", err)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+		pool
+}
+
+// login creates a client for srv with opts and logs in
+func login(srv *ccptest.Server, opts ...ccp.Option) error {
+	opts = append([]ccp.Option{ccp.WithCredentials(ccptest.Username, ccptest.Password), ccp.WithRetryPolicy(nil)}, opts...)
+	client, err := ccp.NewClient(srv.URL, opts...)
+	if err != nil {
+		return err
+	}
+	return client.Login(client)
+}
+
+func TestUnknownCertificateRejected(t *testing.T) {
+	srv := ccptest.NewTLSServer()
+	defer srv.Close()
+
+	var unknownCA x509.UnknownAuthorityError
+	if err := login(srv); !errors.As(err, &unknownCA) {
+		t.Fatalf("Login to a self-signed server error = %v, want x509.UnknownAuthorityError", err)
+	}
+	if n := len(srv.Requests()); n != 0 {
+		t.Errorf("server handled %d requests, want none", n)
+	}
+}
+
+func TestCACertPEM(t *testing.T) {
+	srv := ccptest.NewTLSServer()
+	defer srv.Close()
+
+	if err := login(srv, ccp.WithCACertPEM(serverCAPEM(srv))); err != nil {
+		t.Fatalf("Login trusting the server CA: %v", err)
+	}
+}
+
+func TestCACertPEMInvalid(t *testing.T) {
+	if _, err := ccp.NewClient("https://ccp.example.com", ccp.WithCACertPEM([]byte("not a certificate"))); err == nil {
+		t.Fatal("NewClient with no certificates in the PEM succeeded, want an error")
+	}
+}
+
+func TestPinnedCertificate(t *testing.T) {
+	srv := ccptest.NewTLSServer()
+	defer srv.Close()
+
+	if err := login(srv, ccp.WithPinnedCertificate(fingerprint(srv))); err != nil {
+		t.Fatalf("Login with the server's fingerprint pinned: %v", err)
+	}
+	// the pin also takes hex without colons, in either case
+	pin := strings.ToUpper(strings.ReplaceAll(fingerprint(srv), ":", ""))
+	if err := login(srv, ccp.WithPinnedCertificate(pin)); err != nil {
+		t.Fatalf("Login with the fingerprint pinned as plain hex: %v", err)
+	}
+}
+
+func TestPinnedCertificateMismatch(t *testing.T) {
+	srv := ccptest.NewTLSServer()
+	defer srv.Close()
+
+	other := sha256.Sum256([]byte("another certificate"))
+	err := login(srv, ccp.WithPinnedCertificate(hex.EncodeToString(other[:])))
+	if err == nil || !strings.Contains(err.Error(), "does not match the pinned fingerprint") {
+		t.Fatalf("Login with another fingerprint pinned error = %v, want a fingerprint mismatch", err)
+	}
+	if n := len(srv.Requests()); n != 0 {
+		t.Errorf("server handled %d requests, want none", n)
+	}
+}
+
+func TestPinnedCertificateInvalid(t *testing.T) {
+	for _, pin := range []string{"", "zz:zz", "ab:cd"} {
+		if _, err := ccp.NewClient("https://ccp.example.com", ccp.WithPinnedCertificate(pin)); err == nil {
+			t.Errorf("NewClient with pin %q succeeded, want an error", pin)
+		}
+	}
+}
+
+func TestClientCertificate(t *testing.T) {
+	certPEM, keyPEM, pool := newClientCert(t)
+	srv := ccptest.NewMutualTLSServer(pool)
+	defer srv.Close()
+
+	if err := login(srv, ccp.WithCACertPEM(serverCAPEM(srv))); err == nil {
+		t.Fatal("Login without a client certificate succeeded, want the handshake to fail")
+	}
+	if err := login(srv, ccp.WithCACertPEM(serverCAPEM(srv)), ccp.WithClientCertificatePEM(certPEM, keyPEM)); err != nil {
+		t.Fatalf("Login with the client certificate: %v", err)
+	}
+}
+
+func TestClientCertificateInvalid(t *testing.T) {
+	if _, err := ccp.NewClient("https://ccp.example.com", ccp.WithClientCertificatePEM([]byte("cert"), []byte("key"))); err == nil {
+		t.Fatal("NewClient with an invalid client certificate succeeded, want an error")
+	}
+}
+
+func TestInsecureSkipVerify(t *testing.T) {
+	srv := ccptest.NewTLSServer()
+	defer srv.Close()
+
+	if err := login(srv, ccp.WithInsecureSkipVerify()); err != nil {
+		t.Fatalf("Login with verification off: %v", err)
+	}
+}
@@ -0,0 +1,270 @@
+/*Copyright (c) 2019 Cisco and/or its affiliates.
+
+This software is licensed to you under the terms of the Cisco Sample
+Code License, Version 1.0 (the "License"). You may obtain a copy of the
+License at
+
+               https://developer.cisco.com/docs/licenses
+
+All use of the material herein must be in accordance with the terms of
+the License. All rights not expressly granted by the License are
+reserved. Unless required by applicable law or agreed to separately in
+writing, software distributed under the License is distributed on an "AS
+IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+or implied.*/
+
+package ccp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// TokenStore keeps X-Auth-Tokens between processes, or shares them between clients,
+// keyed by TokenKey. Implementations must be safe for concurrent use
+type TokenStore interface {
+	// Load returns the token saved under key, or "" if there is none
+	Load(ctx context.Context, key string) (string, error)
+	// Save stores token under key, replacing any older one
+	Save(ctx context.Context, key, token string) error
+	// Delete forgets the token saved under key
+	Delete(ctx context.Context, key string) error
+}
+
+// TokenKey is the key a client saves its session under, one per control plane and user
+func TokenKey(baseURL, username string) string {
+	return username + "@" + strings.TrimSuffix(baseURL, "/")
+}
+
+// WithTokenStore reads and writes the session token through ts. ResumeSession takes a
+// token saved by an earlier process if the control plane still accepts it, and every new
+// token, from Login or from logging in again after the token expired, is saved
+func WithTokenStore(ts TokenStore) Option {
+	return func(s *Client) error {
+		if ts == nil {
+			return errors.New("nil TokenStore")
+		}
+		s.tokenStore = ts
+		return nil
+	}
+}
+
+// ResumeSession takes the token saved for the client's user, if the control plane still
+// accepts it, and reports whether it did. It never sends the password: call Login when
+// it returns false
+func (s *Client) ResumeSession() (bool, error) {
+	return s.ResumeSessionWithContext(context.Background())
+}
+
+// ResumeSessionWithContext is ResumeSession with a context that can cancel the call
+func (s *Client) ResumeSessionWithContext(ctx context.Context) (_ bool, err error) {
+	ctx, span := s.startOperation(ctx, "ResumeSession", attribute.String("ccp.user", s.Username))
+	defer func() { endOperation(span, err) }()
+
+	return s.resumeSession(ctx, "")
+}
+
+// resumeSession switches to the token saved for the client's user once the control plane
+// has accepted it. A saved token equal to rejected is not tried again
+func (s *Client) resumeSession(ctx context.Context, rejected string) (bool, error) {
+	token := s.storedToken(ctx, s.Username)
+	if token == "" || token == rejected {
+		return false, nil
+	}
+	session, err := s.checkToken(ctx, token)
+	if err != nil {
+		return false, err
+	}
+	if !session.Valid {
+		s.log().DebugContext(ctx, "saved X-Auth-Token rejected", "user", s.Username)
+		return false, nil
+	}
+	s.log().DebugContext(ctx, "using saved X-Auth-Token", "user", s.Username)
+	s.SetToken(token)
+	return true, nil
+}
+
+// storedToken returns the token saved for username, "" if there is no store or no token
+func (s *Client) storedToken(ctx context.Context, username string) string {
+	if s.tokenStore == nil {
+		return ""
+	}
+	token, err := s.tokenStore.Load(ctx, TokenKey(s.BaseURL, username))
+	if err != nil {
+		s.log().WarnContext(ctx, "cannot load saved X-Auth-Token", "user", username, "error", err)
+		return ""
+	}
+	return token
+}
+
+// storeToken saves token for username. A store that fails only costs a login next time, so it is logged
+func (s *Client) storeToken(ctx context.Context, username, token string) {
+	if s.tokenStore == nil || token == "" {
+		return
+	}
+	if err := s.tokenStore.Save(ctx, TokenKey(s.BaseURL, username), token); err != nil {
+		s.log().WarnContext(ctx, "cannot save X-Auth-Token", "user", username, "error", err)
+	}
+}
+
+// MemoryTokenStore is a TokenStore that shares sessions between the clients of one process
+type MemoryTokenStore struct {
+	mu     sync.RWMutex
+	tokens map[string]string
+}
+
+// NewMemoryTokenStore returns an empty MemoryTokenStore
+func NewMemoryTokenStore() *MemoryTokenStore {
+	return &MemoryTokenStore{tokens: map[string]string{}}
+}
+
+// Load implements TokenStore
+func (m *MemoryTokenStore) Load(ctx context.Context, key string) (string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.tokens[key], nil
+}
+
+// Save implements TokenStore
+func (m *MemoryTokenStore) Save(ctx context.Context, key, token string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tokens[key] = token
+	return nil
+}
+
+// Delete implements TokenStore
+func (m *MemoryTokenStore) Delete(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.tokens, key)
+	return nil
+}
+
+// FileTokenStore is a TokenStore in a JSON file readable only by its owner. Each change
+// rewrites the whole file through a temporary file and a rename, so a reader never sees
+// half a file. Processes saving at the same moment can lose one of the tokens, which
+// only costs that process a login
+type FileTokenStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// StoredToken is one session in a FileTokenStore
+type StoredToken struct {
+	Token string    `json:"token"`
+	Saved time.Time `json:"saved"`
+}
+
+// NewFileTokenStore returns a FileTokenStore in the file at path, which is created on the first Save
+func NewFileTokenStore(path string) *FileTokenStore {
+	return &FileTokenStore{path: path}
+}
+
+// Load implements TokenStore
+func (f *FileTokenStore) Load(ctx context.Context, key string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	tokens, err := f.read()
+	if err != nil {
+		return "", err
+	}
+	return tokens[key].Token, nil
+}
+
+// Saved returns when the token under key was saved, the zero time if there is none
+func (f *FileTokenStore) Saved(key string) (time.Time, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	tokens, err := f.read()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return tokens[key].Saved, nil
+}
+
+// Save implements TokenStore
+func (f *FileTokenStore) Save(ctx context.Context, key, token string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	tokens, err := f.read()
+	if err != nil {
+		return err
+	}
+	tokens[key] = StoredToken{Token: token, Saved: time.Now()}
+	return f.write(tokens)
+}
+
+// Delete implements TokenStore
+func (f *FileTokenStore) Delete(ctx context.Context, key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	tokens, err := f.read()
+	if err != nil {
+		return err
+	}
+	if _, ok := tokens[key]; !ok {
+		return nil
+	}
+	delete(tokens, key)
+	return f.write(tokens)
+}
+
+// read loads the file, a missing file is an empty store. f.mu must be held
+func (f *FileTokenStore) read() (map[string]StoredToken, error) {
+	tokens := map[string]StoredToken{}
+	j, err := ioutil.ReadFile(f.path)
+	if os.IsNotExist(err) {
+		return tokens, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(j) == 0 {
+		return tokens, nil
+	}
+	if err := json.Unmarshal(j, &tokens); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// write replaces the file with tokens, mode 0600. f.mu must be held
+func (f *FileTokenStore) write(tokens map[string]StoredToken) error {
+	j, err := json.MarshalIndent(tokens, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(f.path), filepath.Base(f.path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // fails harmlessly once renamed
+
+	if err := tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(j); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.path)
+}
@@ -0,0 +1,129 @@
+/*Copyright (c) 2019 Cisco and/or its affiliates.
+
+This software is licensed to you under the terms of the Cisco Sample
+Code License, Version 1.0 (the "License"). You may obtain a copy of the
+License at
+
+               https://developer.cisco.com/docs/licenses
+
+All use of the material herein must be in accordance with the terms of
+the License. All rights not expressly granted by the License are
+reserved. Unless required by applicable law or agreed to separately in
+writing, software distributed under the License is distributed on an "AS
+IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+or implied.*/
+
+package ccp_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/rob-moss/ccp-clientlibrary-go/ccp"
+	"github.com/rob-moss/ccp-clientlibrary-go/ccp/ccptest"
+)
+
+// newStoreClient returns a client for srv that keeps its tokens in store, not yet logged in
+func newStoreClient(t *testing.T, srv *ccptest.Server, store ccp.TokenStore, password string) *ccp.Client {
+	t.Helper()
+
+	client, err := ccp.NewClient(srv.URL, ccp.WithCredentials(ccptest.Username, password), ccp.WithTokenStore(store))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	return client
+}
+
+func TestLoginIgnoresSavedToken(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	store := ccp.NewMemoryTokenStore()
+
+	first := newStoreClient(t, srv, store, ccptest.Password)
+	if err := first.Login(first); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+
+	// a saved, valid token must not stand in for a wrong password
+	wrong := newStoreClient(t, srv, store, "wrong")
+	var authErr *ccp.AuthError
+	if err := wrong.Login(wrong); !errors.As(err, &authErr) {
+		t.Fatalf("Login with a wrong password = %v, want an *ccp.AuthError", err)
+	}
+	if wrong.Token() != "" {
+		t.Errorf("the failed login left token %q", wrong.Token())
+	}
+}
+
+func TestResumeSession(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	store := ccp.NewMemoryTokenStore()
+
+	first := newStoreClient(t, srv, store, ccptest.Password)
+	if err := first.Login(first); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	srv.ResetRequests()
+
+	second := newStoreClient(t, srv, store, ccptest.Password)
+	resumed, err := second.ResumeSession()
+	if err != nil || !resumed {
+		t.Fatalf("ResumeSession = %v, %v, want true", resumed, err)
+	}
+	if second.Token() != first.Token() {
+		t.Errorf("token = %q, want the saved %q", second.Token(), first.Token())
+	}
+	if n := countRequests(srv, http.MethodPost, "/v3/system/login"); n != 0 {
+		t.Errorf("logged in %d times, want 0", n)
+	}
+}
+
+func TestResumeSessionRejectedToken(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	store := ccp.NewMemoryTokenStore()
+	if err := store.Save(context.Background(), ccp.TokenKey(srv.URL, ccptest.Username), "expired-token"); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	client := newStoreClient(t, srv, store, ccptest.Password)
+	resumed, err := client.ResumeSession()
+	if err != nil || resumed {
+		t.Fatalf("ResumeSession = %v, %v, want false", resumed, err)
+	}
+	if client.Token() != "" {
+		t.Errorf("token = %q, want none", client.Token())
+	}
+}
+
+func TestReloginTakesNewerSavedToken(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	store := ccp.NewMemoryTokenStore()
+
+	stale := newStoreClient(t, srv, store, ccptest.Password)
+	if err := stale.Login(stale); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	srv.ExpireTokens()
+
+	// another process logs in and saves its token first
+	fresh := newStoreClient(t, srv, store, ccptest.Password)
+	if err := fresh.Login(fresh); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	srv.ResetRequests()
+
+	if _, err := stale.GetClusters(); err != nil {
+		t.Fatalf("GetClusters after the token expired: %v", err)
+	}
+	if n := countRequests(srv, http.MethodPost, "/v3/system/login"); n != 0 {
+		t.Errorf("logged in %d times, want 0", n)
+	}
+	if stale.Token() != fresh.Token() {
+		t.Errorf("token = %q, want the saved %q", stale.Token(), fresh.Token())
+	}
+}
@@ -0,0 +1,108 @@
+/*Copyright (c) 2019 Cisco and/or its affiliates.
+
+This software is licensed to you under the terms of the Cisco Sample
+Code License, Version 1.0 (the "License"). You may obtain a copy of the
+License at
+
+               https://developer.cisco.com/docs/licenses
+
+All use of the material herein must be in accordance with the terms of
+the License. All rights not expressly granted by the License are
+reserved. Unless required by applicable law or agreed to separately in
+writing, software distributed under the License is distributed on an "AS
+IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+or implied.*/
+
+package ccp
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the instrumentation scope of every span the library creates
+const tracerName = "github.com/rob-moss/ccp-clientlibrary-go/ccp"
+
+// Each API call, such as AddCluster or Login, is a span named after the call.
+// Every HTTP request it sends is a child span with the HTTP attributes, so calls
+// made of several requests or polls, such as InstallAddonIstio and
+// AddClusterSynchronous, show as a parent span with one child per request.
+//
+// Spans go to the TracerProvider set with WithTracerProvider, or to the global
+// one from otel.SetTracerProvider. Nothing is recorded until one of those is set.
+
+// WithTracerProvider sends the client's spans to tp instead of the global TracerProvider
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(s *Client) error {
+		s.tracerProvider = tp
+		return nil
+	}
+}
+
+// tracer returns the tracer for the client's spans
+func (s *Client) tracer() trace.Tracer {
+	tp := s.tracerProvider
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+	return tp.Tracer(tracerName)
+}
+
+// startOperation starts the span for an API call and names the call for interceptors
+func (s *Client) startOperation(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	ctx = withOperation(ctx, name)
+	attrs = append(attrs, attribute.String("ccp.operation", name))
+	return s.tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// endOperation records err on the span, if any, and ends it
+func endOperation(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// clusterNameAttr is the span attribute for the cluster passed to AddCluster and friends
+func clusterNameAttr(cluster *Cluster) attribute.KeyValue {
+	if cluster == nil || cluster.Name == nil {
+		return attribute.String("ccp.cluster.name", "")
+	}
+	return attribute.String("ccp.cluster.name", *cluster.Name)
+}
+
+// startHTTPSpan starts the client span for one HTTP request and propagates it to CCP in the headers
+func (s *Client) startHTTPSpan(req *http.Request) (*http.Request, trace.Span) {
+	ctx, span := s.tracer().Start(req.Context(), "HTTP "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", req.Method),
+			attribute.String("url.full", req.URL.String()),
+			attribute.String("server.address", req.URL.Hostname()),
+			attribute.String("ccp.operation", Operation(req.Context())),
+		))
+	req = req.WithContext(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+	return req, span
+}
+
+// endHTTPSpan records the response status or transport error on the span and ends it
+func endHTTPSpan(span trace.Span, resp *http.Response, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	} else {
+		span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+		if resp.StatusCode >= 400 {
+			span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+		}
+	}
+	span.End()
+}
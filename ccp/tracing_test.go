@@ -0,0 +1,167 @@
+/*Copyright (c) 2019 Cisco and/or its affiliates.
+
+This software is licensed to you under the terms of the Cisco Sample
+Code License, Version 1.0 (the "License"). You may obtain a copy of the
+License at
+
+               https://developer.cisco.com/docs/licenses
+
+All use of the material herein must be in accordance with the terms of
+the License. All rights not expressly granted by the License are
+reserved. Unless required by applicable law or agreed to separately in
+writing, software distributed under the License is distributed on an "AS
+IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+or implied.*/
+
+package ccp_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/rob-moss/ccp-clientlibrary-go/ccp"
+	"github.com/rob-moss/ccp-clientlibrary-go/ccp/ccptest"
+)
+
+// newTracedClient returns a logged in client whose spans, from here on, go to the exporter
+func newTracedClient(t *testing.T, srv *ccptest.Server) (*ccp.Client, *tracetest.InMemoryExporter) {
+	t.Helper()
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	t.Cleanup(func() { tp.Shutdown(t.Context()) })
+
+	client := newTestClient(t, srv, ccp.WithTracerProvider(tp), fastRetries())
+	exporter.Reset()
+	return client, exporter
+}
+
+// spanNamed returns the only span called name
+func spanNamed(t *testing.T, spans tracetest.SpanStubs, name string) tracetest.SpanStub {
+	t.Helper()
+
+	var found []tracetest.SpanStub
+	for _, span := range spans {
+		if span.Name == name {
+			found = append(found, span)
+		}
+	}
+	if len(found) != 1 {
+		t.Fatalf("got %d spans called %q, want 1", len(found), name)
+	}
+	return found[0]
+}
+
+// childrenOf returns the names of the direct children of parent, in the order they ended
+func childrenOf(spans tracetest.SpanStubs, parent tracetest.SpanStub) []string {
+	var names []string
+	for _, span := range spans {
+		if span.Parent.SpanID() == parent.SpanContext.SpanID() {
+			names = append(names, span.Name)
+		}
+	}
+	return names
+}
+
+// intAttr returns the value of an integer span attribute
+func intAttr(span tracetest.SpanStub, key attribute.Key) (int64, bool) {
+	for _, kv := range span.Attributes {
+		if kv.Key == key {
+			return kv.Value.AsInt64(), true
+		}
+	}
+	return 0, false
+}
+
+func TestTraceInstallAddonIstio(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	client, exporter := newTracedClient(t, srv)
+	c := addTestCluster(srv)
+
+	if err := client.InstallAddonIstio(*c.UUID); err != nil {
+		t.Fatalf("InstallAddonIstio: %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	root := spanNamed(t, spans, "InstallAddonIstio")
+	if root.Parent.IsValid() {
+		t.Error("InstallAddonIstio has a parent span, want a root span")
+	}
+	if got := childrenOf(spans, root); len(got) != 2 || got[0] != "InstallAddonIstioOp" || got[1] != "InstallAddonIstioInstance" {
+		t.Errorf("InstallAddonIstio children = %q, want InstallAddonIstioOp then InstallAddonIstioInstance", got)
+	}
+	for _, name := range []string{"InstallAddonIstioOp", "InstallAddonIstioInstance"} {
+		step := spanNamed(t, spans, name)
+		if step.SpanContext.TraceID() != root.SpanContext.TraceID() {
+			t.Errorf("%s is in another trace", name)
+		}
+		if got := childrenOf(spans, step); len(got) != 1 || got[0] != "HTTP POST" {
+			t.Errorf("%s children = %q, want one HTTP POST", name, got)
+		}
+	}
+}
+
+func TestTraceAddClusterSynchronous(t *testing.T) {
+	srv := ccptest.NewServer()
+	srv.CreateDelay = 30 * time.Millisecond
+	defer srv.Close()
+	client, exporter := newTracedClient(t, srv)
+
+	if _, err := client.AddClusterSynchronous(ccptest.NewCluster("traced")); err != nil {
+		t.Fatalf("AddClusterSynchronous: %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	root := spanNamed(t, spans, "AddClusterSynchronous")
+	children := childrenOf(spans, root)
+	if len(children) < 2 || children[0] != "HTTP POST" {
+		t.Fatalf("AddClusterSynchronous children = %q, want HTTP POST then the status polls", children)
+	}
+	for _, name := range children[1:] {
+		if name != "GetClusterByUUID" {
+			t.Errorf("AddClusterSynchronous child %q, want only GetClusterByUUID polls after the POST", name)
+		}
+	}
+
+	// every poll is one GET of its own
+	for _, span := range spans {
+		if span.Name == "GetClusterByUUID" {
+			if got := childrenOf(spans, span); len(got) != 1 || got[0] != "HTTP GET" {
+				t.Errorf("GetClusterByUUID children = %q, want one HTTP GET", got)
+			}
+		}
+	}
+}
+
+func TestTraceFailedCall(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	client, exporter := newTracedClient(t, srv)
+	c := addTestCluster(srv)
+
+	srv.AddFault(ccptest.Fault{Method: http.MethodPost, Path: "/v3/clusters/" + *c.UUID + "/addons/", Status: http.StatusInternalServerError})
+	if err := client.InstallAddonIstio(*c.UUID); err == nil {
+		t.Fatal("InstallAddonIstio succeeded, want the 500")
+	}
+
+	spans := exporter.GetSpans()
+	for _, name := range []string{"InstallAddonIstio", "InstallAddonIstioOp", "HTTP POST"} {
+		span := spanNamed(t, spans, name)
+		if span.Status.Code != codes.Error {
+			t.Errorf("%s status = %v, want Error", name, span.Status.Code)
+		}
+	}
+	if status, ok := intAttr(spanNamed(t, spans, "HTTP POST"), "http.response.status_code"); !ok || status != http.StatusInternalServerError {
+		t.Errorf("HTTP POST http.response.status_code = %d, want 500", status)
+	}
+	if len(spanNamed(t, spans, "InstallAddonIstioOp").Events) == 0 {
+		t.Error("InstallAddonIstioOp recorded no error event")
+	}
+}
@@ -0,0 +1,252 @@
+/*Copyright (c) 2019 Cisco and/or its affiliates.
+
+This software is licensed to you under the terms of the Cisco Sample
+Code License, Version 1.0 (the "License"). You may obtain a copy of the
+License at
+
+               https://developer.cisco.com/docs/licenses
+
+All use of the material herein must be in accordance with the terms of
+the License. All rights not expressly granted by the License are
+reserved. Unless required by applicable law or agreed to separately in
+writing, software distributed under the License is distributed on an "AS
+IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+or implied.*/
+
+package ccp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// User is a control plane user
+type User struct {
+	UUID      *string `json:"id,omitempty"`
+	Username  *string `json:"username,omitempty"`
+	Disable   *bool   `json:"disable,omitempty"`
+	Role      *string `json:"role,omitempty"`
+	FirstName *string `json:"firstName,omitempty"`
+	LastName  *string `json:"lastName,omitempty"`
+	Password  *string `json:"password,omitempty"`
+}
+
+// The roles a control plane user can have
+const (
+	RoleAdministrator = "Administrator"
+	RoleDevops        = "Devops"
+)
+
+// validRole checks a role given for AddUser or PatchUser
+func validRole(role string) error {
+	switch role {
+	case RoleAdministrator, RoleDevops:
+		return nil
+	}
+	return fmt.Errorf("User.Role %q must be %s or %s: %w", role, RoleAdministrator, RoleDevops, ErrValidation)
+}
+
+// GetUsers gets every user
+func (s *Client) GetUsers() ([]User, error) {
+	return s.GetUsersWithContext(context.Background())
+}
+
+// GetUsersWithContext is GetUsers with a context that can cancel the call
+func (s *Client) GetUsersWithContext(ctx context.Context) (_ []User, err error) {
+	ctx, span := s.startOperation(ctx, "GetUsers")
+	defer func() { endOperation(span, err) }()
+
+	return Collect(paginate[User](ctx, s, "/v3/users"))
+}
+
+// GetUser gets a user by username
+func (s *Client) GetUser(username string) (*User, error) {
+	return s.GetUserWithContext(context.Background(), username)
+}
+
+// GetUserWithContext is GetUser with a context that can cancel the call
+func (s *Client) GetUserWithContext(ctx context.Context, username string) (_ *User, err error) {
+	ctx, span := s.startOperation(ctx, "GetUser", attribute.String("ccp.user.name", username))
+	defer func() { endOperation(span, err) }()
+
+	x, err := findByName(ctx, s, cacheUsers, username, s.GetUsersWithContext)
+	if err != nil {
+		return nil, err
+	}
+	if x == nil {
+		return nil, fmt.Errorf("Cannot find User %s: %w", username, ErrNotFound)
+	}
+	return x, nil
+}
+
+// AddUser creates a user. Username and Role are required
+func (s *Client) AddUser(user *User) (*User, error) {
+	return s.AddUserWithContext(context.Background(), user)
+}
+
+// AddUserWithContext is AddUser with a context that can cancel the call
+func (s *Client) AddUserWithContext(ctx context.Context, user *User) (_ *User, err error) {
+	ctx, span := s.startOperation(ctx, "AddUser", attribute.String("ccp.user.name", nameOf(user)))
+	defer func() { endOperation(span, err) }()
+	defer s.cache.forget(cacheUsers, nameOf(user), "")
+
+	if user == nil || nonzero(user.Username) {
+		return nil, fmt.Errorf("User.Username is missing: %w", ErrValidation)
+	}
+	if nonzero(user.Role) {
+		return nil, fmt.Errorf("User.Role is missing: %w", ErrValidation)
+	}
+	if err := validRole(*user.Role); err != nil {
+		return nil, err
+	}
+
+	j, err := json.Marshal(user)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", s.BaseURL+"/v3/users/", bytes.NewBuffer(j))
+	if err != nil {
+		return nil, err
+	}
+
+	bytes, err := s.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var data User
+	if err := json.Unmarshal(bytes, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// PatchUser changes the user named by user.Username. Only the fields that are set
+// are changed: FirstName, LastName, Password, Disable and Role
+func (s *Client) PatchUser(user *User) (*User, error) {
+	return s.PatchUserWithContext(context.Background(), user)
+}
+
+// PatchUserWithContext is PatchUser with a context that can cancel the call
+func (s *Client) PatchUserWithContext(ctx context.Context, user *User) (_ *User, err error) {
+	ctx, span := s.startOperation(ctx, "PatchUser", attribute.String("ccp.user.name", nameOf(user)))
+	defer func() { endOperation(span, err) }()
+
+	if user == nil || nonzero(user.Username) {
+		return nil, fmt.Errorf("User.Username is missing: %w", ErrValidation)
+	}
+	if user.Role != nil {
+		if err := validRole(*user.Role); err != nil {
+			return nil, err
+		}
+	}
+
+	uuid, err := s.userUUID(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	defer s.cache.forget(cacheUsers, *user.Username, uuid)
+
+	j, err := json.Marshal(user)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "PATCH", s.BaseURL+"/v3/users/"+uuid+"/", bytes.NewBuffer(j))
+	if err != nil {
+		return nil, err
+	}
+
+	bytes, err := s.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var data User
+	if err := json.Unmarshal(bytes, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// EnableUser lets a disabled user log in again
+func (s *Client) EnableUser(username string) (*User, error) {
+	return s.EnableUserWithContext(context.Background(), username)
+}
+
+// EnableUserWithContext is EnableUser with a context that can cancel the call
+func (s *Client) EnableUserWithContext(ctx context.Context, username string) (*User, error) {
+	return s.PatchUserWithContext(ctx, &User{Username: String(username), Disable: Bool(false)})
+}
+
+// DisableUser stops a user logging in, without deleting it
+func (s *Client) DisableUser(username string) (*User, error) {
+	return s.DisableUserWithContext(context.Background(), username)
+}
+
+// DisableUserWithContext is DisableUser with a context that can cancel the call
+func (s *Client) DisableUserWithContext(ctx context.Context, username string) (*User, error) {
+	return s.PatchUserWithContext(ctx, &User{Username: String(username), Disable: Bool(true)})
+}
+
+// ResetUserPassword sets a new password for a user
+func (s *Client) ResetUserPassword(username, password string) (*User, error) {
+	return s.ResetUserPasswordWithContext(context.Background(), username, password)
+}
+
+// ResetUserPasswordWithContext is ResetUserPassword with a context that can cancel the call
+func (s *Client) ResetUserPasswordWithContext(ctx context.Context, username, password string) (*User, error) {
+	if password == "" {
+		return nil, fmt.Errorf("new password is missing: %w", ErrValidation)
+	}
+	return s.PatchUserWithContext(ctx, &User{Username: String(username), Password: String(password)})
+}
+
+// DeleteUser deletes a user by username
+func (s *Client) DeleteUser(username string) error {
+	return s.DeleteUserWithContext(context.Background(), username)
+}
+
+// DeleteUserWithContext is DeleteUser with a context that can cancel the call
+func (s *Client) DeleteUserWithContext(ctx context.Context, username string) (err error) {
+	ctx, span := s.startOperation(ctx, "DeleteUser", attribute.String("ccp.user.name", username))
+	defer func() { endOperation(span, err) }()
+
+	if username == "" {
+		return fmt.Errorf("Username to delete is required: %w", ErrValidation)
+	}
+
+	uuid, err := s.userUUID(ctx, &User{Username: String(username)})
+	if err != nil {
+		return err
+	}
+	defer s.cache.forget(cacheUsers, username, uuid)
+
+	req, err := http.NewRequestWithContext(ctx, "DELETE", s.BaseURL+"/v3/users/"+uuid+"/", nil)
+	if err != nil {
+		return err
+	}
+	_, err = s.doRequest(req)
+	return err
+}
+
+// userUUID returns the UUID of user, looking it up by username when it isn't set
+func (s *Client) userUUID(ctx context.Context, user *User) (string, error) {
+	if user.UUID != nil && *user.UUID != "" {
+		return *user.UUID, nil
+	}
+	found, err := s.GetUserWithContext(ctx, *user.Username)
+	if err != nil {
+		return "", err
+	}
+	if found.UUID == nil {
+		return "", fmt.Errorf("User %s has no id", *user.Username)
+	}
+	return *found.UUID, nil
+}
@@ -0,0 +1,209 @@
+/*Copyright (c) 2019 Cisco and/or its affiliates.
+
+This software is licensed to you under the terms of the Cisco Sample
+Code License, Version 1.0 (the "License"). You may obtain a copy of the
+License at
+
+               https://developer.cisco.com/docs/licenses
+
+All use of the material herein must be in accordance with the terms of
+the License. All rights not expressly granted by the License are
+reserved. Unless required by applicable law or agreed to separately in
+writing, software distributed under the License is distributed on an "AS
+IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+or implied.*/
+
+package ccp_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/rob-moss/ccp-clientlibrary-go/ccp"
+	"github.com/rob-moss/ccp-clientlibrary-go/ccp/ccptest"
+)
+
+func TestUserValidation(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	srv.AddUser("jo", "secret")
+	client := newTestClient(t, srv)
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{"AddUser nil", func() error { _, err := client.AddUser(nil); return err }},
+		{"AddUser without a username", func() error {
+			_, err := client.AddUser(&ccp.User{Role: ccp.String(ccp.RoleDevops)})
+			return err
+		}},
+		{"AddUser without a role", func() error { _, err := client.AddUser(&ccp.User{Username: ccp.String("sam")}); return err }},
+		{"AddUser with an unknown role", func() error {
+			_, err := client.AddUser(&ccp.User{Username: ccp.String("sam"), Role: ccp.String("Superuser")})
+			return err
+		}},
+		{"PatchUser without a username", func() error { _, err := client.PatchUser(&ccp.User{FirstName: ccp.String("Jo")}); return err }},
+		{"PatchUser with an unknown role", func() error {
+			_, err := client.PatchUser(&ccp.User{Username: ccp.String("jo"), Role: ccp.String("devops")})
+			return err
+		}},
+		{"ResetUserPassword without a password", func() error { _, err := client.ResetUserPassword("jo", ""); return err }},
+		{"DeleteUser without a username", func() error { return client.DeleteUser("") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); !errors.Is(err, ccp.ErrValidation) {
+				t.Errorf("error = %v, want ErrValidation", err)
+			}
+		})
+	}
+
+	if n := len(srv.Requests()); n != 0 {
+		t.Errorf("control plane got %d requests for invalid users, want none", n)
+	}
+	if u, _ := srv.User("jo"); *u.Role != ccp.RoleDevops {
+		t.Errorf("jo's role = %s, want it unchanged", *u.Role)
+	}
+}
+
+func TestAddUser(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	client := newTestClient(t, srv)
+
+	u, err := client.AddUser(&ccp.User{Username: ccp.String("sam"), Role: ccp.String(ccp.RoleAdministrator), Password: ccp.String("secret")})
+	if err != nil {
+		t.Fatalf("AddUser: %v", err)
+	}
+	if u.UUID == nil || *u.Username != "sam" || *u.Role != ccp.RoleAdministrator {
+		t.Errorf("AddUser = %+v, want sam as an Administrator with an id", u)
+	}
+	if _, err := client.AddUser(&ccp.User{Username: ccp.String("sam"), Role: ccp.String(ccp.RoleDevops)}); !errors.Is(err, ccp.ErrConflict) {
+		t.Errorf("AddUser of an existing user error = %v, want ErrConflict", err)
+	}
+}
+
+func TestPatchUserByName(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	srv.AddUser("jo", "secret")
+	client := newTestClient(t, srv)
+	stored, _ := srv.User("jo")
+
+	u, err := client.PatchUser(&ccp.User{Username: ccp.String("jo"), Role: ccp.String(ccp.RoleAdministrator)})
+	if err != nil {
+		t.Fatalf("PatchUser: %v", err)
+	}
+	if *u.Role != ccp.RoleAdministrator {
+		t.Errorf("PatchUser role = %s, want %s", *u.Role, ccp.RoleAdministrator)
+	}
+	// the username was looked up to find the id to patch
+	if n := countRequests(srv, http.MethodGet, "/v3/users"); n != 1 {
+		t.Errorf("listed users %d times, want 1 to find jo", n)
+	}
+	if n := countRequests(srv, http.MethodPatch, "/v3/users/"+*stored.UUID+"/"); n != 1 {
+		t.Errorf("PATCH of jo's id sent %d times, want 1", n)
+	}
+}
+
+func TestPatchUserByUUID(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	srv.AddUser("jo", "secret")
+	client := newTestClient(t, srv)
+	stored, _ := srv.User("jo")
+
+	if _, err := client.DisableUser("jo"); err != nil {
+		t.Fatalf("DisableUser: %v", err)
+	}
+	srv.ResetRequests()
+	if _, err := client.PatchUser(&ccp.User{UUID: stored.UUID, Username: ccp.String("jo"), Disable: ccp.Bool(false)}); err != nil {
+		t.Fatalf("PatchUser: %v", err)
+	}
+	if n := countRequests(srv, http.MethodGet, "/v3/users"); n != 0 {
+		t.Errorf("listed users %d times with the id given, want none", n)
+	}
+	if u, _ := srv.User("jo"); *u.Disable {
+		t.Error("jo is still disabled")
+	}
+}
+
+func TestPatchUserNotFound(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	client := newTestClient(t, srv)
+
+	if _, err := client.PatchUser(&ccp.User{Username: ccp.String("nobody"), FirstName: ccp.String("No")}); !errors.Is(err, ccp.ErrNotFound) {
+		t.Errorf("PatchUser of a missing user error = %v, want ErrNotFound", err)
+	}
+	if err := client.DeleteUser("nobody"); !errors.Is(err, ccp.ErrNotFound) {
+		t.Errorf("DeleteUser of a missing user error = %v, want ErrNotFound", err)
+	}
+	for _, r := range srv.Requests() {
+		if r.Method != http.MethodGet {
+			t.Errorf("%s %s sent for a missing user", r.Method, r.Path)
+		}
+	}
+}
+
+func TestDeleteUserByName(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	srv.AddUser("jo", "secret")
+	client := newTestClient(t, srv)
+	stored, _ := srv.User("jo")
+
+	if err := client.DeleteUser("jo"); err != nil {
+		t.Fatalf("DeleteUser: %v", err)
+	}
+	if n := countRequests(srv, http.MethodDelete, "/v3/users/"+*stored.UUID+"/"); n != 1 {
+		t.Errorf("DELETE of jo's id sent %d times, want 1", n)
+	}
+	if _, ok := srv.User("jo"); ok {
+		t.Error("jo was not deleted")
+	}
+}
+
+func TestUserCacheDroppedByWrites(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	srv.AddUser("jo", "secret")
+	client := newTestClient(t, srv, ccp.WithCache(time.Minute))
+
+	lists := func() int { return countRequests(srv, http.MethodGet, "/v3/users") }
+	if _, err := client.GetUser("jo"); err != nil {
+		t.Fatalf("GetUser: %v", err)
+	}
+	if _, err := client.GetUser("jo"); err != nil || lists() != 1 {
+		t.Fatalf("GetUser again = %v after %d lists, want the cached user", err, lists())
+	}
+
+	// PatchUser finds jo in the cache, then drops the entry
+	if _, err := client.PatchUser(&ccp.User{Username: ccp.String("jo"), Role: ccp.String(ccp.RoleAdministrator)}); err != nil {
+		t.Fatalf("PatchUser: %v", err)
+	}
+	u, err := client.GetUser("jo")
+	if err != nil {
+		t.Fatalf("GetUser after PatchUser: %v", err)
+	}
+	if *u.Role != ccp.RoleAdministrator || lists() != 2 {
+		t.Errorf("GetUser after PatchUser = %s after %d lists, want the new role from a new list", *u.Role, lists())
+	}
+
+	if err := client.DeleteUser("jo"); err != nil {
+		t.Fatalf("DeleteUser: %v", err)
+	}
+	if _, err := client.GetUser("jo"); !errors.Is(err, ccp.ErrNotFound) || lists() != 3 {
+		t.Errorf("GetUser after DeleteUser = %v after %d lists, want ErrNotFound from a new list", err, lists())
+	}
+
+	if _, err := client.AddUser(&ccp.User{Username: ccp.String("jo"), Role: ccp.String(ccp.RoleDevops)}); err != nil {
+		t.Fatalf("AddUser: %v", err)
+	}
+	if _, err := client.GetUser("jo"); err != nil || lists() != 4 {
+		t.Errorf("GetUser after AddUser = %v after %d lists, want the new user from a new list", err, lists())
+	}
+}
@@ -0,0 +1,326 @@
+/*Copyright (c) 2019 Cisco and/or its affiliates.
+
+This software is licensed to you under the terms of the Cisco Sample
+Code License, Version 1.0 (the "License"). You may obtain a copy of the
+License at
+
+               https://developer.cisco.com/docs/licenses
+
+All use of the material herein must be in accordance with the terms of
+the License. All rights not expressly granted by the License are
+reserved. Unless required by applicable law or agreed to separately in
+writing, software distributed under the License is distributed on an "AS
+IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+or implied.*/
+
+package ccp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// APIVersion is the generation of the CCP REST API a control plane speaks
+type APIVersion int
+
+// API generations. CCP 4.x and 5.x control planes only have the v2 API
+const (
+	APIUnknown APIVersion = iota
+	APIv2
+	APIv3
+)
+
+func (v APIVersion) String() string {
+	switch v {
+	case APIv2:
+		return "v2"
+	case APIv3:
+		return "v3"
+	}
+	return "unknown"
+}
+
+// Capabilities describes the control plane the client talks to
+type Capabilities struct {
+	APIVersion APIVersion
+	CXVersion  string // CCP release from livenessHealth, "" if it didn't say
+
+	SupportsAddons         bool // install, list and delete addons, and the addon catalog
+	SupportsNodePools      bool // scale worker node pools
+	SupportsClusterChanges bool // create and patch clusters, v2 can still list, get and delete them
+	SupportsACIProfiles    bool
+	SupportsSessions       bool // Logout, WhoAmI and ValidateSession
+	SupportsUsers          bool // user management
+	SupportsLDAP           bool // LDAP and Active Directory setup
+	SupportsRBAC           bool // roles and cluster access grants
+}
+
+// capabilitiesFor returns what a control plane with the given API supports
+func capabilitiesFor(v APIVersion, cxVersion string) *Capabilities {
+	v3 := v == APIv3
+	return &Capabilities{
+		APIVersion:             v,
+		CXVersion:              cxVersion,
+		SupportsAddons:         v3,
+		SupportsNodePools:      v3,
+		SupportsClusterChanges: v3,
+		SupportsACIProfiles:    v3,
+		SupportsSessions:       v3,
+		SupportsUsers:          v3,
+		SupportsLDAP:           v3,
+		SupportsRBAC:           v3,
+	}
+}
+
+// WithAPIVersion skips probing and assumes the control plane speaks v, for example
+// when replaying a cassette recorded before version detection existed
+func WithAPIVersion(v APIVersion) Option {
+	return func(s *Client) error {
+		if v != APIv2 && v != APIv3 {
+			return fmt.Errorf("unknown API version %d", v)
+		}
+		s.capabilities = capabilitiesFor(v, "")
+		return nil
+	}
+}
+
+// probeFailureTTL is how long a failed probe is returned to every call before the next probe
+var probeFailureTTL = 5 * time.Second
+
+// Capabilities returns what the control plane supports. The first call probes
+// /v3/system/livenessHealth, then /2/system/livenessHealth, and the answer is kept.
+// A failed probe is kept for a few seconds, so calls made meanwhile fail at once
+// instead of each probing an unreachable control plane in turn
+func (s *Client) Capabilities(ctx context.Context) (*Capabilities, error) {
+	s.capabilitiesMu.Lock()
+	defer s.capabilitiesMu.Unlock()
+	if s.capabilities != nil {
+		return s.capabilities, nil
+	}
+	if s.probeErr != nil && time.Now().Before(s.probeRetry) {
+		return nil, s.probeErr
+	}
+
+	caps, err := s.probe(ctx)
+	if err != nil {
+		// a caller that gave up says nothing about the control plane
+		if ctx.Err() == nil {
+			s.probeErr, s.probeRetry = err, time.Now().Add(probeFailureTTL)
+		}
+		return nil, err
+	}
+	s.probeErr = nil
+	s.log().DebugContext(ctx, "detected control plane", "api", caps.APIVersion.String(), "version", caps.CXVersion)
+	s.capabilities = caps
+	return caps, nil
+}
+
+// probe asks the v3 and then the v2 liveness endpoint, neither needs a session.
+// An endpoint that answers at all, even with 401, tells us the API exists
+func (s *Client) probe(ctx context.Context) (*Capabilities, error) {
+	for _, try := range []struct {
+		version APIVersion
+		path    string
+	}{
+		{APIv3, "/v3/system/livenessHealth"},
+		{APIv2, "/2/system/livenessHealth"},
+	} {
+		req, err := http.NewRequestWithContext(ctx, "GET", s.BaseURL+try.path, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("User-Agent", s.userAgentString())
+
+		resp, err := s.roundTrip(req)
+		if err != nil {
+			return nil, fmt.Errorf("detecting CCP API version: %w", err)
+		}
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("detecting CCP API version: %w", err)
+		}
+
+		switch {
+		case resp.StatusCode == http.StatusNotFound:
+			continue
+		case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+			return capabilitiesFor(try.version, ""), nil
+		case resp.StatusCode < 200 || resp.StatusCode > 299:
+			return nil, fmt.Errorf("detecting CCP API version: %w", newAPIError(req, resp, body))
+		}
+
+		var liveness LivenessHealth
+		json.Unmarshal(body, &liveness) // the version is a nice to have
+		return capabilitiesFor(try.version, derefString(liveness.CXVersion)), nil
+	}
+	return nil, errors.New("detecting CCP API version: neither /v3 nor /2 is served at " + s.BaseURL)
+}
+
+// apiRoute maps a v3 endpoint to its v2 equivalent. Patterns are matched a path
+// segment at a time, * matches one segment and a trailing ** matches any number.
+// The segments matched by * fill in the * in v2. An empty v2 means v2 has no such endpoint
+type apiRoute struct {
+	method  string // "" matches every method
+	pattern string
+	v2      string
+	missing string // what ErrUnsupported says is missing
+}
+
+var v2Routes = []apiRoute{
+	{method: "GET", pattern: "/v3/clusters", v2: "/2/clusters/"},
+	{method: "GET", pattern: "/v3/clusters/*", v2: "/2/clusters/*/"},
+	{method: "DELETE", pattern: "/v3/clusters/*", v2: "/2/clusters/*/"},
+	{pattern: "/v3/clusters/*/node-pools/**", missing: "node pools"},
+	{pattern: "/v3/clusters/*/addons/**", missing: "addons"},
+	{pattern: "/v3/clusters/*/catalog/**", missing: "the addon catalog"},
+	{pattern: "/v3/clusters/*/authz/**", missing: "cluster access grants"},
+	{pattern: "/v3/clusters/**", missing: "creating and changing clusters"},
+	{method: "GET", pattern: "/v3/providers", v2: "/2/providerclientconfigs/"},
+	{method: "GET", pattern: "/v3/providers/*", v2: "/2/providerclientconfigs/*/"},
+	{pattern: "/v3/aci-profiles/**", missing: "ACI profiles"},
+	{pattern: "/v3/users/**", missing: "user management"},
+	{pattern: "/v3/ldap/**", missing: "LDAP setup"},
+	{pattern: "/v3/rbac/**", missing: "RBAC roles"},
+	{method: "GET", pattern: "/v3/system/livenessHealth", v2: "/2/system/livenessHealth"},
+	{method: "GET", pattern: "/v3/system/health", v2: "/2/system/health"},
+	{pattern: "/v3/system/**", missing: "sessions"},
+}
+
+// route rewrites a v3 request for a v2 control plane, or fails with ErrUnsupported.
+// Requests to v3 control planes and to /2 endpoints go out unchanged. v2 reports
+// whether the control plane speaks v2, so the response needs adaptV2
+func (s *Client) route(req *http.Request) (_ *http.Request, v2 bool, err error) {
+	caps, err := s.Capabilities(req.Context())
+	if err != nil {
+		return nil, false, err
+	}
+	if caps.APIVersion != APIv2 {
+		return req, false, nil
+	}
+
+	base, err := url.Parse(s.BaseURL)
+	if err != nil {
+		return nil, true, err
+	}
+	prefix := strings.TrimSuffix(base.Path, "/")
+	path := strings.TrimPrefix(req.URL.Path, prefix)
+	if !strings.HasPrefix(path, "/v3/") && path != "/v3" {
+		return req, true, nil
+	}
+
+	for _, r := range v2Routes {
+		if r.method != "" && r.method != req.Method {
+			continue
+		}
+		matched, ok := matchRoute(r.pattern, path)
+		if !ok {
+			continue
+		}
+		if r.v2 == "" {
+			return nil, true, unsupported(req.Context(), caps, r.missing)
+		}
+
+		to := r.v2
+		for _, seg := range matched {
+			to = strings.Replace(to, "*", seg, 1)
+		}
+		routed := req.Clone(req.Context())
+		routed.URL.Path = prefix + to
+		routed.URL.RawPath = ""
+		s.log().DebugContext(req.Context(), "routed to v2 API", "from", path, "to", to)
+		return routed, true, nil
+	}
+	return nil, true, unsupported(req.Context(), caps, path)
+}
+
+// dropCookies expires the cookies the client holds for the control plane, such as a v2 session
+func (s *Client) dropCookies() {
+	jar := s.getHTTPClient().Jar
+	base, err := url.Parse(s.BaseURL)
+	if jar == nil || err != nil {
+		return
+	}
+	var expired []*http.Cookie
+	for _, c := range jar.Cookies(base) {
+		expired = append(expired, &http.Cookie{Name: c.Name, Path: "/", MaxAge: -1})
+	}
+	jar.SetCookies(base, expired)
+}
+
+// matchRoute matches path against pattern, returning the segments matched by *
+func matchRoute(pattern, path string) ([]string, bool) {
+	want := strings.Split(strings.Trim(pattern, "/"), "/")
+	got := strings.Split(strings.Trim(path, "/"), "/")
+	var matched []string
+	for i, w := range want {
+		if w == "**" {
+			return matched, true
+		}
+		if i >= len(got) {
+			return nil, false
+		}
+		switch w {
+		case "*":
+			matched = append(matched, got[i])
+		case got[i]:
+		default:
+			return nil, false
+		}
+	}
+	return matched, len(got) == len(want)
+}
+
+// unsupported is the ErrUnsupported for something a control plane can't do
+func unsupported(ctx context.Context, caps *Capabilities, what string) error {
+	if op := Operation(ctx); op != "" {
+		what = op + ": " + what
+	}
+	release := ""
+	if caps.CXVersion != "" {
+		release = " " + caps.CXVersion
+	}
+	return fmt.Errorf("%s %w by CCP%s with the %s API", what, ErrUnsupported, release, caps.APIVersion)
+}
+
+// adaptV2 gives the objects in a v2 response the "id" key the v3 types decode the UUID from
+func adaptV2(body []byte) []byte {
+	var v interface{}
+	if json.Unmarshal(body, &v) != nil {
+		return body
+	}
+
+	withID := func(v interface{}) {
+		if o, ok := v.(map[string]interface{}); ok {
+			if _, ok := o["id"]; !ok && o["uuid"] != nil {
+				o["id"] = o["uuid"]
+			}
+		}
+	}
+	switch t := v.(type) {
+	case []interface{}:
+		for _, item := range t {
+			withID(item)
+		}
+	case map[string]interface{}:
+		withID(t)
+		if results, ok := t["results"].([]interface{}); ok {
+			for _, item := range results {
+				withID(item)
+			}
+		}
+	}
+
+	adapted, err := json.Marshal(v)
+	if err != nil {
+		return body
+	}
+	return adapted
+}
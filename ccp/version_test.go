@@ -0,0 +1,211 @@
+/*Copyright (c) 2019 Cisco and/or its affiliates.
+
+This software is licensed to you under the terms of the Cisco Sample
+Code License, Version 1.0 (the "License"). You may obtain a copy of the
+License at
+
+               https://developer.cisco.com/docs/licenses
+
+All use of the material herein must be in accordance with the terms of
+the License. All rights not expressly granted by the License are
+reserved. Unless required by applicable law or agreed to separately in
+writing, software distributed under the License is distributed on an "AS
+IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+or implied.*/
+
+package ccp_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/rob-moss/ccp-clientlibrary-go/ccp"
+	"github.com/rob-moss/ccp-clientlibrary-go/ccp/ccptest"
+)
+
+func TestGetVersionV2(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	srv.SetAPIVersion(ccp.APIv2)
+	srv.SetVersion("5.2.1")
+
+	client := newTestClient(t, srv)
+	version, err := client.GetVersion()
+	if err != nil {
+		t.Fatalf("GetVersion: %v", err)
+	}
+	if version.CXVersion == nil || *version.CXVersion != "5.2.1" {
+		t.Errorf("CXVersion = %v, want 5.2.1", version.CXVersion)
+	}
+}
+
+func TestGetVersionV2Unknown(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	srv.SetAPIVersion(ccp.APIv2)
+	srv.SetVersion("")
+
+	client := newTestClient(t, srv)
+	if version, err := client.GetVersion(); !errors.Is(err, ccp.ErrUnsupported) {
+		t.Fatalf("GetVersion = %v, %v, want ErrUnsupported", version, err)
+	}
+}
+
+// newV2Client logs in to a v2 control plane with the fake's first cluster added
+func newV2Client(t *testing.T) (*ccptest.Server, *ccp.Client, ccp.Cluster) {
+	t.Helper()
+	srv := ccptest.NewServer()
+	t.Cleanup(srv.Close)
+	srv.SetAPIVersion(ccp.APIv2)
+	c := srv.AddCluster(*ccptest.NewCluster("legacy"))
+	return srv, newTestClient(t, srv, ccp.WithRetryPolicy(nil)), c
+}
+
+func TestV2Routes(t *testing.T) {
+	srv, client, c := newV2Client(t)
+
+	clusters, err := client.GetClusters()
+	if err != nil {
+		t.Fatalf("GetClusters: %v", err)
+	}
+	if len(clusters) != 1 || clusters[0].UUID == nil || *clusters[0].UUID != *c.UUID {
+		t.Errorf("GetClusters = %+v, want the cluster with its uuid as UUID", clusters)
+	}
+	got, err := client.GetClusterByUUID(*c.UUID)
+	if err != nil {
+		t.Fatalf("GetClusterByUUID: %v", err)
+	}
+	if got.UUID == nil || *got.UUID != *c.UUID || *got.Name != "legacy" {
+		t.Errorf("GetClusterByUUID = %+v, want the cluster with its uuid as UUID", got)
+	}
+
+	for _, path := range []string{"/2/clusters/", "/2/clusters/" + *c.UUID + "/"} {
+		if n := countRequests(srv, http.MethodGet, path); n != 1 {
+			t.Errorf("GET %s sent %d times, want 1", path, n)
+		}
+	}
+	for _, r := range srv.Requests() {
+		if strings.HasPrefix(r.Path, "/v3/") {
+			t.Errorf("%s %s sent to a v2 control plane", r.Method, r.Path)
+		}
+	}
+}
+
+func TestV2Unsupported(t *testing.T) {
+	srv, client, c := newV2Client(t)
+
+	if _, err := client.GetACIProfiles(); !errors.Is(err, ccp.ErrUnsupported) || !strings.Contains(err.Error(), "ACI profiles") {
+		t.Errorf("GetACIProfiles error = %v, want ErrUnsupported for ACI profiles", err)
+	}
+	if err := client.InstallAddonDashboard(*c.UUID); !errors.Is(err, ccp.ErrUnsupported) {
+		t.Errorf("InstallAddonDashboard error = %v, want ErrUnsupported", err)
+	}
+	if n := len(srv.Requests()); n != 0 {
+		t.Errorf("control plane got %d requests for unsupported endpoints, want none", n)
+	}
+}
+
+func TestV2Login(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	srv.SetAPIVersion(ccp.APIv2)
+
+	client, err := ccp.NewClient(srv.URL, ccp.WithCredentials(ccptest.Username, ccptest.Password))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if err := client.Login(client); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if n := countRequests(srv, http.MethodPost, "/2/system/login/"); n != 1 {
+		t.Fatalf("POST /2/system/login/ sent %d times, want 1", n)
+	}
+	for _, r := range srv.Requests() {
+		if r.Path != "/2/system/login/" {
+			continue
+		}
+		if ct := r.Header.Get("Content-Type"); ct != "application/x-www-form-urlencoded" {
+			t.Errorf("login Content-Type = %q, want a form", ct)
+		}
+		if body := string(r.Body); !strings.Contains(body, "username="+ccptest.Username) {
+			t.Errorf("login body = %q, want the form credentials", body)
+		}
+	}
+	if tok := client.Token(); tok != "" {
+		t.Errorf("Token() = %q after a v2 login, want none, the session is a cookie", tok)
+	}
+
+	// the session cookie authenticates later calls
+	if _, err := client.GetClusters(); err != nil {
+		t.Fatalf("GetClusters with the v2 session: %v", err)
+	}
+}
+
+func TestCapabilitiesProbeFailureKept(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	srv.AddFault(ccptest.Fault{Method: http.MethodGet, Path: "/v3/system/livenessHealth", Status: http.StatusInternalServerError, Times: 1})
+
+	client, err := ccp.NewClient(srv.URL, ccp.WithRetryPolicy(nil))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	ctx := context.Background()
+	_, first := client.Capabilities(ctx)
+	if first == nil {
+		t.Fatal("Capabilities succeeded, want the probe to fail")
+	}
+	srv.ResetRequests()
+
+	// the fault is spent, but the failure is still kept
+	if _, err := client.Capabilities(ctx); err != first {
+		t.Errorf("Capabilities again error = %v, want the kept %v", err, first)
+	}
+	if n := len(srv.Requests()); n != 0 {
+		t.Errorf("probed %d more times while the failure was kept, want none", n)
+	}
+}
+
+func TestCapabilitiesProbeFailureExpires(t *testing.T) {
+	defer ccp.SetProbeFailureTTL(0)()
+	srv := ccptest.NewServer()
+	defer srv.Close()
+	srv.AddFault(ccptest.Fault{Method: http.MethodGet, Path: "/v3/system/livenessHealth", Status: http.StatusInternalServerError, Times: 1})
+
+	client, err := ccp.NewClient(srv.URL, ccp.WithRetryPolicy(nil))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	ctx := context.Background()
+	if _, err := client.Capabilities(ctx); err == nil {
+		t.Fatal("Capabilities succeeded, want the probe to fail")
+	}
+	caps, err := client.Capabilities(ctx)
+	if err != nil {
+		t.Fatalf("Capabilities once the failure expired: %v", err)
+	}
+	if caps.APIVersion != ccp.APIv3 {
+		t.Errorf("APIVersion = %v, want v3", caps.APIVersion)
+	}
+}
+
+func TestCapabilitiesCancelledProbeNotKept(t *testing.T) {
+	srv := ccptest.NewServer()
+	defer srv.Close()
+
+	client, err := ccp.NewClient(srv.URL, ccp.WithRetryPolicy(nil))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.Capabilities(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Capabilities error = %v, want context.Canceled", err)
+	}
+	if _, err := client.Capabilities(context.Background()); err != nil {
+		t.Errorf("Capabilities after a cancelled probe: %v, want a fresh probe", err)
+	}
+}
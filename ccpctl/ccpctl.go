@@ -2,6 +2,8 @@ package main
 
 import (
 	"bytes"
+	"context"
+	"crypto/x509"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -21,8 +23,9 @@ import (
 )
 
 // user.Current().HomeDir
-var myself, _ = user.Current()                      // Get curent user and HOMEDIR
-var defaultsFile = myself.HomeDir + "/.ccpctl.json" // also make this avaialble in the ENV var CCPCTLCONF
+var myself, _ = user.Current()                           // Get curent user and HOMEDIR
+var defaultsFile = myself.HomeDir + "/.ccpctl.json"      // also make this avaialble in the ENV var CCPCTLCONF
+var tokensFile = myself.HomeDir + "/.ccpctl-tokens.json" // saved sessions, see ccp.FileTokenStore
 
 // debug levels:
 //		0 for off
@@ -49,8 +52,8 @@ type Defaults struct {
 	CPPass            string    `json:"cppass"`
 	CPURL             string    `json:"cpurl"`
 	CPClusterDfl      string    `json:"cpclusterdfl"`      // default CCP cluster to work on
-	CPToken           string    `json:"cptoken"`           // API token
-	CPTokenTime       time.Time `json:"cptokentime"`       // API token expiry
+	CPToken           string    `json:"cptoken"`           // API token, only read to move it to tokensFile
+	CPTokenTime       time.Time `json:"cptokentime"`       // API token expiry, no longer written
 	CPDatastoreDfl    string    `json:"cpdatastoredfl"`    // Default infra DS
 	CPDatacenterDfl   string    `json:"cpdatacenterdfl"`   // Default infra DC
 	CPImageDfl        string    `json:"cpimagedfl"`        // Default CCP image name
@@ -60,6 +63,9 @@ type Defaults struct {
 	CPSubnetDfl       string    `json:"cpsubnetdfl`        // Default Subnet name
 	CPSubnetDflUUID   string    `json:"cpsubnetdflUUID"`   // Default Subnet UUID
 	CPVSClusterDfl    string    `json:"cpvsclusterdfl"`    // Default vSphere Cluster
+	CPCACert          string    `json:"cpcacert"`          // CA bundle (PEM file) to verify the CP certificate
+	CPCertPin         string    `json:"cpcertpin"`         // SHA-256 fingerprint of a self-signed CP certificate
+	CPInsecure        bool      `json:"cpinsecure"`        // skip CP certificate verification, only when set on purpose
 }
 
 // todo:
@@ -152,6 +158,33 @@ func menuHelp() {
 		setdefault cpUser 		// CP username ie Admin
 		setdefault cpPass 		// CP password ie C1sc0123
 
+	session
+		logincp // logs in afresh and saves the token
+		logoutcp // ends the session on the control plane
+		whoami // shows the logged in user and role
+		health // control plane health, exits 1 when degraded and 2 when unreachable
+
+	users
+		getusers // lists users and their roles
+		adduser <username> role=Administrator|Devops password=password [first=firstname] [last=lastname]
+		setuser <username> [role=Administrator|Devops] [password=password] [first=firstname] [last=lastname] [disable=true|false]
+		deluser <username>
+
+	LDAP
+		ldap // shows the LDAP setup
+		ldap set [file=ldap.json] [server=ldap.example.com] [port=636] [basedn=dc=example,dc=com] [binddn=cn=ccp,dc=example,dc=com]
+			[bindpass=password] [groupbasedn=ou=groups,dc=example,dc=com] [groupfilter=(objectClass=group)]
+			[starttls=true|false] [insecure=true|false] [cacert=/path/to/ca.pem]
+		ldap update <same fields as set> // changes only the fields given
+		ldap test [<same fields as set>] // tests the given setup, or the saved one
+		ldap delete
+
+	RBAC
+		getroles // lists the roles users and LDAP groups can have
+		getaccess <clustername> // lists the users and LDAP groups granted the cluster
+		grantaccess <clustername> user=username|group=groupdn
+		revokeaccess <clustername> user=username|group=groupdn
+
 	add Control Plane info
 		setcp <asks interactive>
 		setcp cpname=cpname clusterdfl=clustername providerdfl=providername subnetdfl=subnetname datastoredfl=datastore datacenterdfl=dc
@@ -249,6 +282,10 @@ func menuHelpCP() {
 			datacenterdfl=dc 
 			vsclusterdfl=vsphereclustername
 			imagedfl=ccp-tenant-image-1.16.3-ubuntu18-6.1.1
+			// TLS: without one of these the CP certificate must be signed by a system CA
+			cacert=/path/to/ca.pem			// verify the CP certificate against this CA bundle
+			certpin=ab:cd:...			// accept only a CP certificate with this SHA-256 fingerprint
+			insecure=true				// do not verify the CP certificate at all
 	`)
 }
 
@@ -325,6 +362,15 @@ func menuSetCP(args []string, Settings Defaults, client *ccp.Client) (*Defaults,
 		case "networkdfl":
 			fmt.Println("network updated with: " + value)
 			Settings.CPNetworkDfl = value
+		case "cacert":
+			fmt.Println("cacert updated with: " + value)
+			Settings.CPCACert = value
+		case "certpin":
+			fmt.Println("certpin updated with: " + value)
+			Settings.CPCertPin = value
+		case "insecure":
+			fmt.Println("insecure updated with: " + value)
+			Settings.CPInsecure = value == "true"
 		default:
 			fmt.Println("Not understood: param=" + param + " value=" + value)
 			menuHelpCP()
@@ -1112,6 +1158,331 @@ func menuGetSubnet(client *ccp.Client, subnetName string, jsonout bool) {
 	}
 }
 
+func menuWhoAmI(client *ccp.Client, cpURL string, jsonout bool) {
+	session, err := client.ValidateSession()
+	if err != nil {
+		fmt.Println("ValidateSession error:", err)
+		return
+	}
+	if !session.Valid {
+		fmt.Println("* Not logged in to " + cpURL + ", run ccpctl logincp")
+		return
+	}
+	if jsonout {
+		j, _ := json.Marshal(session)
+		prettyPrintJSONString(string(j))
+	} else {
+		fmt.Println("User: ", str(session.Username), " Role: ", str(session.Role), " Control Plane: ", cpURL)
+	}
+}
+
+func menuGetUsers(client *ccp.Client, jsonout bool) {
+	users, err := client.GetUsers()
+	if err != nil {
+		fmt.Println("GetUsers error:", err)
+		return
+	}
+	for _, user := range users {
+		if jsonout {
+			j, _ := json.Marshal(user)
+			prettyPrintJSONString(string(j))
+		} else {
+			enabled := user.Disable == nil || !*user.Disable
+			fmt.Println("User: ", str(user.Username), " Role: ", str(user.Role), " Enabled: ", enabled)
+		}
+	}
+}
+
+// menuSetUser adds a user, or changes one with the fields given as param=value
+func menuSetUser(client *ccp.Client, add bool, args []string, jsonout bool) error {
+	user := &ccp.User{Username: ccp.String(args[0])}
+	for _, arg := range args[1:] {
+		param, value := splitparam(arg)
+		switch param {
+		case "role":
+			user.Role = ccp.String(value)
+		case "password":
+			user.Password = ccp.String(value)
+		case "first":
+			user.FirstName = ccp.String(value)
+		case "last":
+			user.LastName = ccp.String(value)
+		case "disable":
+			user.Disable = ccp.Bool(value == "true")
+		default:
+			return errors.New("flag " + arg + " unknown")
+		}
+	}
+
+	var err error
+	if add {
+		if user.Password == nil {
+			return errors.New("password= is required for a new user")
+		}
+		user, err = client.AddUser(user)
+	} else {
+		user, err = client.PatchUser(user)
+	}
+	if err != nil {
+		return err
+	}
+
+	if jsonout {
+		j, _ := json.Marshal(user)
+		prettyPrintJSONString(string(j))
+	} else {
+		fmt.Println("* User: ", str(user.Username), " Role: ", str(user.Role))
+	}
+	return nil
+}
+
+// menuLDAP runs the ldap subcommands, showing the setup when there is none
+func menuLDAP(client *ccp.Client, args []string, jsonout bool) error {
+	var sub string
+	var params []string
+	for _, arg := range args {
+		switch {
+		case arg == "json" || arg == "debug":
+		case sub == "":
+			sub = arg
+		default:
+			params = append(params, arg)
+		}
+	}
+
+	switch sub {
+	case "":
+		setup, err := client.GetLDAPSetup()
+		if errors.Is(err, ccp.ErrNotFound) {
+			fmt.Println("* LDAP is not set up")
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		printLDAPSetup(setup, jsonout)
+	case "set", "update":
+		setup, err := ldapSetupFromParams(params)
+		if err != nil {
+			return err
+		}
+		if sub == "set" {
+			setup, err = client.SetLDAPSetup(setup)
+		} else {
+			setup, err = client.UpdateLDAPSetup(setup)
+		}
+		if err != nil {
+			return err
+		}
+		printLDAPSetup(setup, jsonout)
+	case "test":
+		var setup *ccp.LDAPSetup
+		if len(params) > 0 {
+			var err error
+			if setup, err = ldapSetupFromParams(params); err != nil {
+				return err
+			}
+		}
+		result, err := client.TestLDAPSetup(setup)
+		if err != nil {
+			return err
+		}
+		message := ""
+		if result.Message != nil {
+			message = *result.Message
+		}
+		if !result.OK() {
+			return errors.New("LDAP test failed: " + message)
+		}
+		fmt.Println("* LDAP test passed:", message)
+	case "delete":
+		if err := client.DeleteLDAPSetup(); err != nil {
+			return err
+		}
+		fmt.Println("* LDAP setup deleted")
+	default:
+		return errors.New("unknown ldap command " + sub + ", try ccpctl help")
+	}
+	return nil
+}
+
+// ldapSetupFromParams builds an LDAP setup from file=setup.json, if given, and the param=value fields
+func ldapSetupFromParams(params []string) (*ccp.LDAPSetup, error) {
+	setup := &ccp.LDAPSetup{}
+	for _, arg := range params {
+		param, value := splitparam(arg)
+		switch param {
+		case "file":
+			j, err := ioutil.ReadFile(value)
+			if err != nil {
+				return nil, err
+			}
+			if err := json.Unmarshal(j, setup); err != nil {
+				return nil, err
+			}
+		case "server":
+			setup.Server = ccp.String(value)
+		case "port":
+			setup.Port = ccp.Int64(strtoint64(value))
+		case "basedn":
+			setup.BaseDN = ccp.String(value)
+		case "binddn":
+			setup.ServiceAccountDN = ccp.String(value)
+		case "bindpass":
+			setup.ServiceAccountPassword = ccp.String(value)
+		case "groupbasedn":
+			setup.GroupSearchBaseDN = ccp.String(value)
+		case "groupfilter":
+			setup.GroupSearchFilter = ccp.String(value)
+		case "starttls":
+			setup.StartTLS = ccp.Bool(value == "true")
+		case "insecure":
+			setup.InsecureSkipVerify = ccp.Bool(value == "true")
+		case "cacert":
+			pem, err := ioutil.ReadFile(value)
+			if err != nil {
+				return nil, err
+			}
+			setup.CACert = ccp.String(string(pem))
+		default:
+			return nil, errors.New("flag " + arg + " unknown")
+		}
+	}
+	return setup, nil
+}
+
+// str returns *p, or "" for a field the control plane left out
+func str(p *string) string {
+	if p == nil {
+		return ""
+	}
+	return *p
+}
+
+// printLDAPSetup prints the setup, as JSON that ldap set file= takes back when jsonout is set
+func printLDAPSetup(setup *ccp.LDAPSetup, jsonout bool) {
+	if jsonout {
+		j, _ := json.Marshal(setup)
+		prettyPrintJSONString(string(j))
+		return
+	}
+	port := int64(0)
+	if setup.Port != nil {
+		port = *setup.Port
+	}
+	fmt.Println("LDAP Server: ", str(setup.Server), " Port: ", port, " Base DN: ", str(setup.BaseDN))
+	fmt.Println("Bind DN: ", str(setup.ServiceAccountDN), " Group Base DN: ", str(setup.GroupSearchBaseDN), " Group Filter: ", str(setup.GroupSearchFilter))
+	fmt.Println("StartTLS: ", setup.StartTLS != nil && *setup.StartTLS, " Skip Verify: ", setup.InsecureSkipVerify != nil && *setup.InsecureSkipVerify, " CA Cert: ", setup.CACert != nil)
+}
+
+func menuGetRoles(client *ccp.Client, jsonout bool) {
+	roles, err := client.GetRoles()
+	if err != nil {
+		fmt.Println("GetRoles error:", err)
+		return
+	}
+	for _, role := range roles {
+		if jsonout {
+			j, _ := json.Marshal(role)
+			prettyPrintJSONString(string(j))
+		} else {
+			fmt.Println("Role: ", str(role.Role), " Description: ", str(role.Description))
+		}
+	}
+}
+
+// menuClusterAccess lists, grants or revokes access to a cluster, then prints who has access
+func menuClusterAccess(client *ccp.Client, cmd, clusterName string, args []string, jsonout bool) error {
+	cluster, err := client.GetClusterByName(clusterName)
+	if err != nil {
+		return err
+	}
+
+	if cmd != "getaccess" {
+		var authType, name string
+		for _, arg := range args {
+			param, value := splitparam(arg)
+			switch param {
+			case ccp.AuthTypeUser, ccp.AuthTypeGroup:
+				authType, name = param, value
+			default:
+				if arg != "json" && arg != "debug" {
+					return errors.New("flag " + arg + " unknown")
+				}
+			}
+		}
+		if cmd == "grantaccess" {
+			_, err = client.GrantClusterAccess(str(cluster.UUID), authType, name)
+		} else {
+			err = client.RevokeClusterAccess(str(cluster.UUID), authType, name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	authz, err := client.GetClusterAuthz(str(cluster.UUID))
+	if err != nil {
+		return err
+	}
+	if jsonout {
+		j, _ := json.Marshal(authz)
+		prettyPrintJSONString(string(j))
+		return nil
+	}
+	if authz.AuthList == nil || len(*authz.AuthList) == 0 {
+		fmt.Println("* Only administrators can use cluster", clusterName)
+		return nil
+	}
+	for _, e := range *authz.AuthList {
+		fmt.Println("Cluster: ", clusterName, " ", str(e.Type), ": ", str(e.Name))
+	}
+	return nil
+}
+
+// menuHealth prints the control plane health and returns the exit code for monitoring:
+// 0 healthy, 1 degraded, 2 when the health could not be read
+func menuHealth(client *ccp.Client, cpURL string, jsonout bool) int {
+	health, err := client.GetHealth()
+	if err != nil {
+		fmt.Println("GetHealth error:", err)
+		return 2
+	}
+	cxVersion := "unknown"
+	if version, err := client.GetVersion(); err != nil {
+		Debug(2, "GetVersion error: "+err.Error())
+	} else if version.CXVersion != nil && *version.CXVersion != "" {
+		cxVersion = *version.CXVersion
+	}
+
+	problems := health.Problems()
+	if jsonout {
+		j, _ := json.Marshal(health)
+		prettyPrintJSONString(string(j))
+	} else {
+		status := "Healthy"
+		if len(problems) > 0 {
+			status = "Degraded"
+		}
+		api := "unknown"
+		if caps, err := client.Capabilities(context.Background()); err == nil {
+			api = caps.APIVersion.String()
+		}
+		fmt.Println("Control Plane: ", cpURL, " Version: ", cxVersion, " API: ", api, " Health: ", status)
+		if health.CurrentNodes != nil && health.ExpectedNodes != nil {
+			fmt.Println("Nodes: ", *health.CurrentNodes, "/", *health.ExpectedNodes)
+		}
+		for _, problem := range problems {
+			fmt.Println("* " + problem)
+		}
+	}
+
+	if len(problems) > 0 {
+		return 1
+	}
+	return 0
+}
+
 func menuScaleCluster(client *ccp.Client, clusterName string, workers int, jsonout bool) error {
 	cluster, err := client.GetClusterByName(clusterName)
 	if err != nil {
@@ -1133,6 +1504,23 @@ func menuScaleCluster(client *ccp.Client, clusterName string, workers int, jsono
 	return nil
 }
 
+// tlsOptions picks how to check the CP certificate from the defaults file.
+// Without a CA, a pin or insecure=true the certificate is checked against the system CAs,
+// which a self-signed CCP certificate fails
+func tlsOptions(Settings *Defaults) []ccp.Option {
+	switch {
+	case Settings.CPCertPin != "":
+		return []ccp.Option{ccp.WithPinnedCertificate(Settings.CPCertPin)}
+	case Settings.CPCACert != "":
+		return []ccp.Option{ccp.WithCACertFile(Settings.CPCACert)}
+	case Settings.CPInsecure:
+		fmt.Fprintln(os.Stderr, "* Warning: not verifying the certificate of "+Settings.CPURL+", set cacert= or certpin= with setcp")
+		return []ccp.Option{ccp.WithInsecureSkipVerify()}
+	default:
+		return nil
+	}
+}
+
 // ccpctl help
 //
 // add Control Plane info
@@ -1182,28 +1570,42 @@ func main() {
 		return
 	}
 
-	// create the CCP Client side struct
-	client := ccp.NewClient(Settings.CPUser, Settings.CPPass, Settings.CPURL)
-	client.XAuthToken = Settings.CPToken
+	// create the CCP Client side struct, sessions are saved in tokensFile
+	tokens := ccp.NewFileTokenStore(tokensFile)
+	opts := append(tlsOptions(Settings), ccp.WithCredentials(Settings.CPUser, Settings.CPPass), ccp.WithTokenStore(tokens))
+	client, err := ccp.NewClient(Settings.CPURL, opts...)
+	if err != nil {
+		fmt.Println("* NewClient error:", err)
+		return
+	}
 
-	// ---------------------------------------------
-	// check if CPTokenTime is older than 30 mins
-	t1 := Settings.CPTokenTime
-	t2 := time.Now()
-	timediff := t2.Sub(t1).Minutes()
-	// fmt.Println(int64(timediff), "Minutes elapsed since login")
-	if timediff >= float64(180) || Settings.CPToken == "" {
-		fmt.Println("* Token expired, Logging in again")
-		err := client.Login(client)
+	// move a token saved by an older ccpctl into the token store
+	if Settings.CPToken != "" {
+		err := tokens.Save(context.Background(), ccp.TokenKey(Settings.CPURL, Settings.CPUser), Settings.CPToken)
+		if err == nil {
+			Settings.CPToken = ""
+			Settings.CPTokenTime = time.Time{}
+			err = writeDefaults(Settings)
+		}
 		if err != nil {
-			fmt.Println(err)
+			fmt.Println("* Token store error: ", err)
 		}
-		Settings.CPToken = client.XAuthToken // keep the xauthtoken
-		Settings.CPTokenTime = time.Now()    // timestamp now
-		err = writeDefaults(Settings)
+	}
+
+	// ---------------------------------------------
+	// reuses the saved session if the control plane still accepts it, the client logs in again by itself when it expires
+	resumed, err := client.ResumeSession()
+	if err != nil {
+		Debug(1, "Cannot resume the saved session: "+err.Error())
+	}
+	if !resumed {
+		err = client.Login(client)
 		if err != nil {
-			fmt.Println("* Write Defults error: ", err)
-			return
+			fmt.Println(err)
+			var unknownCA x509.UnknownAuthorityError
+			if errors.As(err, &unknownCA) {
+				fmt.Println("* The CP certificate is not signed by a known CA, set cacert=, certpin= or insecure=true with setcp")
+			}
 		}
 	}
 
@@ -1237,19 +1639,23 @@ func main() {
 	for _, arg := range os.Args[1:] {
 		switch arg {
 		case "logincp":
+			// Login always authenticates, and saves the new token
 			err := client.Login(client)
 			if err != nil {
 				fmt.Println(err)
 			}
-			Settings.CPToken = client.XAuthToken // keep the xauthtoken
-			Settings.CPTokenTime = time.Now()    // timestamp now
-			// write to defaults
-			err = writeDefaults(Settings)
+			return
+		case "logoutcp":
+			err := client.Logout()
 			if err != nil {
-				fmt.Println("* Write Defults error: ", err)
-				return
+				fmt.Println(err)
 			}
 			return
+		case "whoami":
+			menuWhoAmI(client, Settings.CPURL, jsonout)
+			return
+		case "health":
+			os.Exit(menuHealth(client, Settings.CPURL, jsonout))
 		case "setdefault":
 			// fmt.Println("setdefault " + string(pos))
 			fmt.Println("Not implemented yet")
@@ -1267,6 +1673,53 @@ func main() {
 		case "getcp":
 			menuGetCP(Settings)
 			return
+		// Users
+		case "getusers":
+			menuGetUsers(client, jsonout)
+			return
+		case "adduser", "setuser":
+			if len(os.Args[1:]) < 2 {
+				menuHelp()
+				return
+			}
+			err = menuSetUser(client, arg == "adduser", os.Args[2:], jsonout)
+			if err != nil {
+				fmt.Println(arg+" error:", err)
+			}
+			return
+		case "deluser":
+			if len(os.Args[1:]) < 2 {
+				fmt.Println("deluser <username>")
+				return
+			}
+			err = client.DeleteUser(os.Args[2])
+			if err != nil {
+				fmt.Println("DeleteUser error:", err)
+				return
+			}
+			fmt.Println("* Deleted user", os.Args[2])
+			return
+		// LDAP
+		case "ldap":
+			err = menuLDAP(client, os.Args[2:], jsonout)
+			if err != nil {
+				fmt.Println("ldap error:", err)
+			}
+			return
+		// RBAC
+		case "getroles":
+			menuGetRoles(client, jsonout)
+			return
+		case "getaccess", "grantaccess", "revokeaccess":
+			if len(os.Args[1:]) < 2 || (arg != "getaccess" && len(os.Args[1:]) < 3) {
+				fmt.Println(arg + " <clustername> [user=username|group=groupdn]")
+				return
+			}
+			err = menuClusterAccess(client, arg, os.Args[2], os.Args[3:], jsonout)
+			if err != nil {
+				fmt.Println(arg+" error:", err)
+			}
+			return
 		// Clusters
 		case "addcluster":
 			if len(os.Args[1:]) < 2 {
@@ -22,9 +22,14 @@ func main() {
 
 	fmt.Println("* Entered main")
 
-	client := ccp.NewClient(cpUser, cpPass, cpURL)
+	// most CCP instances use self-signed certs, use ccp.WithCACertFile or ccp.WithPinnedCertificate to verify them
+	client, err := ccp.NewClient(cpURL, ccp.WithCredentials(cpUser, cpPass), ccp.WithInsecureSkipVerify())
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
 
-	err := client.Login(client)
+	err = client.Login(client)
 	if err != nil {
 		fmt.Println(err)
 	}